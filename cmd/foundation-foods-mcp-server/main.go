@@ -10,7 +10,9 @@ import (
 func main() {
 	err := cmd.Run()
 	if err != nil {
-		fmt.Println(err)
+		if !cmd.IsReported(err) {
+			fmt.Println(err)
+		}
 		os.Exit(1)
 	}
 }
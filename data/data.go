@@ -0,0 +1,6 @@
+// Package data optionally embeds the Foundation Foods dataset into the binary. Building with
+// -tags embeddata compiles the dataset in, so the server can run without a separate JSON file.
+package data
+
+// DatasetFile is the path of the embedded dataset within Dataset
+const DatasetFile = "foundationfoods_2025-04-24.json"
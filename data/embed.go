@@ -0,0 +1,16 @@
+//go:build embeddata
+
+package data
+
+import (
+	"embed"
+	"io/fs"
+)
+
+// files holds the dataset compiled into the binary
+//
+//go:embed foundationfoods_2025-04-24.json
+var files embed.FS
+
+// Dataset is the filesystem holding the embedded dataset at DatasetFile
+var Dataset fs.FS = files
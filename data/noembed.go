@@ -0,0 +1,8 @@
+//go:build !embeddata
+
+package data
+
+import "io/fs"
+
+// Dataset is nil because this binary was built without the embeddata tag
+var Dataset fs.FS
@@ -17,22 +17,23 @@ func NewBearerTokenAuth(token string) *BearerTokenAuth {
 
 // IsAuthorized validates Bearer token from Authorization header
 func (b *BearerTokenAuth) IsAuthorized(r *http.Request) bool {
-	authHeader := r.Header.Get("Authorization")
-	if authHeader == "" {
+	token := b.Token(r)
+	if token == "" {
 		return false
 	}
 
+	return token == b.token
+}
+
+// Token returns the Bearer token from the Authorization header, or an empty string when there is none
+func (b *BearerTokenAuth) Token(r *http.Request) string {
 	const bearerPrefix = "Bearer "
+	authHeader := r.Header.Get("Authorization")
 	if !strings.HasPrefix(authHeader, bearerPrefix) {
-		return false
-	}
-
-	token := strings.TrimPrefix(authHeader, bearerPrefix)
-	if token == "" {
-		return false
+		return ""
 	}
 
-	return token == b.token
+	return strings.TrimPrefix(authHeader, bearerPrefix)
 }
 
 // SetUnauthorizedHeaders sets standard WWW-Authenticate header for Bearer auth
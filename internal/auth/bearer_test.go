@@ -73,3 +73,16 @@ func TestBearerTokenAuth_SetUnauthorizedHeaders(t *testing.T) {
 
 	assert.Equal(t, "Bearer", w.Header().Get("WWW-Authenticate"))
 }
+
+func TestBearerTokenAuth_Token(t *testing.T) {
+	auth := NewBearerTokenAuth("secret-token")
+
+	req := httptest.NewRequest("GET", "/", nil)
+	assert.Empty(t, auth.Token(req))
+
+	req.Header.Set("Authorization", "Basic secret-token")
+	assert.Empty(t, auth.Token(req))
+
+	req.Header.Set("Authorization", "Bearer other-token")
+	assert.Equal(t, "other-token", auth.Token(req))
+}
@@ -1,6 +1,20 @@
 package cmd
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/noot-app/foundation-foods-mcp-server/data"
 	"github.com/noot-app/foundation-foods-mcp-server/internal/auth"
 	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
 	"github.com/noot-app/foundation-foods-mcp-server/internal/mcpgo"
@@ -29,8 +43,39 @@ The server operates in three modes:
 
 Available MCP Tools:
 - search_foundation_foods_by_name: Search foundation foods by name
+- search_foundation_foods_names_only: Search foods and return only their FDC IDs, descriptions and categories
 - search_foundation_foods_and_return_nutrients: Search foods and return simplified nutrient info
 - search_foundation_foods_and_return_nutrients_simplified: Search foods and return simplified nutrient info fixed to the default nutrients
+- food_vs_category: Compare a food's nutrients against its category averages
+- top_nutrient_differences: Return the nutrients that differ most between two foods
+- compare_foundation_foods: Compare 2 to 5 foods side by side, one row per nutrient
+- batch_search_foundation_foods: Search several food names in one call
+- resolve_foods: Resolve a list of names to their single best-matching foods
+- canonicalize_food_name: Return the canonical USDA description for a loose food name
+- search_with_alternatives: Return the best match plus scored alternatives with why each matched
+- explain_food_search: Break down how a name search scores one description, step by step
+- find_foods_containing_ingredient: Find foods whose input foods match an ingredient
+- get_food_with_inputs: Return a food with its input foods expanded recursively
+- get_food_input_foods: List the FDC IDs and descriptions of a food's input foods
+- nutrients_for_household_portion: Scale a food's nutrients to a household measure like "2 cups"
+- net_carbs: Return a food's total carbs minus fiber for a serving
+- nutrients_with_upper_limits: Annotate a serving's nutrients with their tolerable upper intake levels
+- daily_intake_summary: Total a day's foods' nutrients against a demographic's daily targets
+- rank_by_protein_density: Rank foods by grams of protein per 100 kcal
+- find_foods_highest_in_nutrient: Rank foods by their amount of one nutrient per 100 g
+- find_foods_with_nutrient_in_range: Find foods whose amount of one nutrient is within a range
+- multi_nutrient_sources: Find foods that rank highly in several nutrients at once
+- rank_foods_by_nutrients: Rank foods by a weighted sum of several normalized nutrients
+- nutrients_per_200kcal: Return the best match's nutrients scaled to 200 kcal
+- macro_percentages: Return the best match's protein/fat/carb shares of calories
+- nutrient_vectors: Return matching foods as fixed-order nutrient vectors for ML pipelines
+- list_units: List the distinct nutrient and portion units in the dataset with usage counts
+- convert_nutrient_unit: Convert a nutrient amount between g, mg, µg, kcal, kJ and IU
+- get_dataset_info: Report the loaded dataset's source file, release, food and category counts
+- get_foods_by_fdc_ids: Fetch several foods by FDC ID in one call, listing IDs not found
+- get_foods_by_ndb_number: Look up foods by their legacy NDB number
+- nutrient_histogram: Return how a nutrient's amount is distributed across foods
+- nutrient_history: Return a food's nutrient amount across loaded dataset releases
 
 Authentication (HTTP Mode Only):
 Bearer token authentication is required for all MCP endpoints except /health.
@@ -39,6 +84,17 @@ Use the FOUNDATIONFOODS_MCP_TOKEN environment variable to set the token.`,
 		// Check if we should run in stdio mode (for Claude Desktop)
 		stdio, _ := cmd.Flags().GetBool("stdio")
 
+		// Without the flag, AUTO_TRANSPORT picks stdio when we were launched through a pipe
+		if !stdio {
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			if cfg.AutoTransport {
+				stdio = detectStdioTransport(os.Stdin)
+			}
+		}
+
 		if stdio {
 			return runStdioMode(cmd, args)
 		} else {
@@ -51,14 +107,139 @@ func init() {
 	rootCmd.Flags().Bool("stdio", false, "Run in stdio mode for local Claude Desktop integration (default: HTTP mode for remote deployment)")
 }
 
+// detectStdioTransport reports whether stdin looks like an MCP client's pipe rather than a terminal,
+// and no PORT was configured to ask for HTTP mode explicitly
+func detectStdioTransport(stdin *os.File) bool {
+	if _, ok := os.LookupEnv("PORT"); ok {
+		return false
+	}
+	info, err := stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice == 0
+}
+
+// reportedError marks an error whose message has already been printed to stderr
+type reportedError struct {
+	err error
+}
+
+func (e reportedError) Error() string { return e.err.Error() }
+func (e reportedError) Unwrap() error { return e.err }
+
+// IsReported reports whether err was already printed, so the caller should exit without repeating it
+func IsReported(err error) bool {
+	var reported reportedError
+	return errors.As(err, &reported)
+}
+
+// validateDataFile checks that the dataset exists and is a non-empty file before the engine tries to load it
+func validateDataFile(path string) error {
+	info, err := os.Stat(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return fmt.Errorf("foundation Foods data file not found at %q; set FOUNDATIONFOODS_JSON_FILE (or DATA_DIR) to the dataset's location", path)
+	case err != nil:
+		return fmt.Errorf("cannot read Foundation Foods data file %q: %w", path, err)
+	case info.IsDir():
+		return fmt.Errorf("foundation Foods data file %q is a directory; set FOUNDATIONFOODS_JSON_FILE to the JSON file inside it", path)
+	case info.Size() == 0:
+		return fmt.Errorf("foundation Foods data file %q is empty; download the dataset again", path)
+	}
+	return nil
+}
+
+// listenAddress combines the bind address and port into the HTTP listen address, rejecting malformed values
+// before the server tries to listen on them
+func listenAddress(bindAddress, port string) (string, error) {
+	host := strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(bindAddress), "["), "]")
+	if host != "localhost" && net.ParseIP(host) == nil {
+		return "", fmt.Errorf("invalid BIND_ADDRESS %q: must be an IP address such as 0.0.0.0, 127.0.0.1 or ::1, or localhost", bindAddress)
+	}
+
+	portNumber, err := strconv.Atoi(strings.TrimSpace(port))
+	if err != nil || portNumber < 1 || portNumber > 65535 {
+		return "", fmt.Errorf("invalid PORT %q: must be a number between 1 and 65535", port)
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(portNumber)), nil
+}
+
+// validateTLSFiles checks that TLS_CERT_FILE and TLS_KEY_FILE are set together and name a readable,
+// matching certificate and key, so a bad TLS setup fails before the server binds its port
+func validateTLSFiles(certFile, keyFile string) error {
+	switch {
+	case certFile == "" && keyFile == "":
+		return nil
+	case certFile == "":
+		return errors.New("TLS_KEY_FILE is set but TLS_CERT_FILE is not; set both to serve HTTPS, or neither for plain HTTP")
+	case keyFile == "":
+		return errors.New("TLS_CERT_FILE is set but TLS_KEY_FILE is not; set both to serve HTTPS, or neither for plain HTTP")
+	}
+
+	for _, file := range []struct{ env, path string }{{"TLS_CERT_FILE", certFile}, {"TLS_KEY_FILE", keyFile}} {
+		f, err := os.Open(file.path)
+		if err != nil {
+			return fmt.Errorf("cannot read %s %q: %w", file.env, file.path, err)
+		}
+		f.Close()
+	}
+
+	if _, err := tls.LoadX509KeyPair(certFile, keyFile); err != nil {
+		return fmt.Errorf("invalid TLS certificate %q or key %q: %w", certFile, keyFile, err)
+	}
+	return nil
+}
+
+// embeddedDatasetPath is the FOUNDATIONFOODS_JSON_FILE value that selects the dataset compiled into the binary
+const embeddedDatasetPath = "embedded"
+
+// useEmbeddedDataset reports whether the dataset compiled into the binary should be loaded: when
+// FOUNDATIONFOODS_JSON_FILE is "embedded", or when the binary embeds a dataset and neither
+// FOUNDATIONFOODS_JSON_FILE nor DATA_DIR is set. Filesystem loading stays the default otherwise.
+func useEmbeddedDataset(cfg *config.Config) (bool, error) {
+	if cfg.FoundationFoodsJsonFile == embeddedDatasetPath {
+		if data.Dataset == nil {
+			return false, errors.New("the embedded Foundation Foods dataset was requested but this binary was built without it; rebuild with -tags embeddata or set FOUNDATIONFOODS_JSON_FILE to the dataset's location")
+		}
+		return true, nil
+	}
+
+	_, fileSet := os.LookupEnv("FOUNDATIONFOODS_JSON_FILE")
+	_, dirSet := os.LookupEnv("DATA_DIR")
+	return data.Dataset != nil && !fileSet && !dirSet, nil
+}
+
+// validateDataset checks the configured dataset before the engine tries to load it
+func validateDataset(cfg *config.Config) error {
+	embedded, err := useEmbeddedDataset(cfg)
+	if err != nil || embedded {
+		return err
+	}
+	return validateDataFile(cfg.FoundationFoodsJsonFile)
+}
+
 // runStdioMode runs the MCP server in stdio mode for Claude Desktop
 func runStdioMode(cmd *cobra.Command, args []string) error {
+	// Load configuration
+	cfg, err := config.Load()
+	if err == nil {
+		err = validateDataset(cfg)
+	}
+
+	// Fail with one clean stderr line rather than log output mixed into the stdio transport, so
+	// Claude Desktop users see an actionable message in the server log
+	if err != nil {
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		fmt.Fprintf(cmd.ErrOrStderr(), "foundation-foods-mcp-server: %v\n", err)
+		return reportedError{err: err}
+	}
+
 	// Use a logger that writes to stderr to avoid interfering with stdio MCP communication
 	logger := config.NewLogger(true) // true for stdio mode
 
-	// Load configuration
-	cfg := config.Load()
-
 	logger.Info("🔌 Starting FoundationFoods MCP Server in STDIO mode",
 		"mode", "stdio",
 		"description", "Local MCP server for Claude Desktop integration",
@@ -66,7 +247,7 @@ func runStdioMode(cmd *cobra.Command, args []string) error {
 		"transport", "stdio pipes")
 
 	// Load Foundation Foods data
-	queryEngine, err := query.NewEngine(cfg.FoundationFoodsJsonFile, logger)
+	queryEngine, err := newQueryEngine(cfg, logger)
 	if err != nil {
 		logger.Error("Failed to initialize query engine", "error", err)
 		return err
@@ -76,7 +257,11 @@ func runStdioMode(cmd *cobra.Command, args []string) error {
 	authenticator := auth.NewBearerTokenAuth(cfg.AuthToken)
 
 	// Create MCP server
-	mcpSrv := mcpgo.NewServer(queryEngine, authenticator, logger)
+	mcpSrv, err := newMCPServer(cfg, queryEngine, authenticator, logger)
+	if err != nil {
+		logger.Error("Failed to create MCP server", "error", err)
+		return err
+	}
 
 	// Run the MCP server on stdio transport (no auth needed for local use)
 	return mcpSrv.ServeStdio()
@@ -88,30 +273,74 @@ func runHTTPMode(cmd *cobra.Command, args []string) error {
 	logger := config.NewLogger(false) // false for HTTP mode
 
 	// Load configuration
-	cfg := config.Load()
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Error("Invalid configuration", "error", err)
+		return err
+	}
+
+	// Reject a malformed listen address before spending time loading the dataset
+	addr, err := listenAddress(cfg.BindAddress, cfg.Port)
+	if err != nil {
+		logger.Error("Invalid listen address", "error", err)
+		return err
+	}
+
+	if err := validateTLSFiles(cfg.TLSCertFile, cfg.TLSKeyFile); err != nil {
+		logger.Error("Invalid TLS configuration", "error", err)
+		return err
+	}
 
 	logger.Info("🌐 Starting FoundationFoods MCP Server in HTTP mode",
 		"mode", "http",
 		"description", "Remote MCP server with API key authentication",
 		"auth", "Bearer token required (except /health endpoint)",
 		"transport", "HTTP/JSON-RPC 2.0",
-		"port", cfg.Port)
+		"addr", addr,
+		"tls", cfg.TLSCertFile != "")
 
 	// Load Foundation Foods data
-	queryEngine, err := query.NewEngine(cfg.FoundationFoodsJsonFile, logger)
+	queryEngine, err := newQueryEngine(cfg, logger)
 	if err != nil {
 		logger.Error("Failed to initialize query engine", "error", err)
 		return err
 	}
 
+	// Pick up dataset updates on SIGHUP without a restart
+	reloadOnSignal(queryEngine, logger, syscall.SIGHUP)
+
 	// Create auth
 	authenticator := auth.NewBearerTokenAuth(cfg.AuthToken)
 
 	// Create MCP server
-	mcpSrv := mcpgo.NewServer(queryEngine, authenticator, logger)
+	mcpSrv, err := newMCPServer(cfg, queryEngine, authenticator, logger)
+	if err != nil {
+		logger.Error("Failed to create MCP server", "error", err)
+		return err
+	}
+
+	// Drain in-flight requests on SIGINT/SIGTERM; orchestrators send SIGTERM before SIGKILL
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	// Run the MCP server on HTTP transport with auth
-	return mcpSrv.ServeHTTP(":" + cfg.Port)
+	return mcpSrv.ServeHTTP(ctx, addr)
+}
+
+// reloadOnSignal reloads the dataset each time the process receives one of the signals. A failed reload
+// is logged and the current dataset stays in place.
+func reloadOnSignal(engine *query.Engine, logger *slog.Logger, signals ...os.Signal) {
+	received := make(chan os.Signal, 1)
+	signal.Notify(received, signals...)
+
+	go func() {
+		for sig := range received {
+			logger.Info("Reloading Foundation Foods data", "signal", sig.String())
+			if err := engine.Reload(context.Background()); err != nil {
+				logger.Error("Failed to reload Foundation Foods data, keeping the current dataset", "error", err)
+			}
+		}
+	}()
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -124,3 +353,82 @@ func Execute() error {
 func Run() error {
 	return Execute()
 }
+
+// newQueryEngine loads the dataset into a query engine with the options derived from the configuration
+func newQueryEngine(cfg *config.Config, logger *slog.Logger) (*query.Engine, error) {
+	opts := []query.EngineOption{
+		query.WithMaxFoods(cfg.MaxFoodsToLoad),
+		query.WithDropInvalidPortions(cfg.DropInvalidPortions),
+		query.WithRebuildConcurrency(cfg.RebuildConcurrency),
+		query.WithSearchCacheSize(cfg.SearchCacheSize),
+		query.WithSearchLimits(query.NewSearchLimits(cfg.SearchDefaultLimit, cfg.SearchMaxLimit)),
+		query.WithHistoryFiles(cfg.HistoryDataFiles...),
+	}
+
+	if cfg.UpperLimitsFile != "" {
+		limits, err := query.LoadUpperLimits(cfg.UpperLimitsFile)
+		if err != nil {
+			return nil, err
+		}
+		logger.Info("Loaded upper intake limits",
+			"path", cfg.UpperLimitsFile,
+			"count", len(limits))
+		opts = append(opts, query.WithUpperLimits(limits))
+	}
+
+	if cfg.SynonymsFile != "" {
+		opts = append(opts, query.WithSynonymsFile(cfg.SynonymsFile))
+	}
+
+	if cfg.ScoringWeightsFile != "" {
+		opts = append(opts, query.WithScoringWeightsFile(cfg.ScoringWeightsFile))
+	}
+
+	embedded, err := useEmbeddedDataset(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if embedded {
+		logger.Info("Using the dataset embedded in the binary", "path", data.DatasetFile)
+		opts = append(opts, query.WithDatasetFS(data.Dataset))
+		return query.NewEngine(data.DatasetFile, logger, opts...)
+	}
+
+	return query.NewEngine(cfg.FoundationFoodsJsonFile, logger, opts...)
+}
+
+// newMCPServer creates the MCP server with the options derived from the configuration
+func newMCPServer(cfg *config.Config, queryEngine query.QueryEngine, authenticator *auth.BearerTokenAuth, logger *slog.Logger) (*mcpgo.Server, error) {
+	opts := []mcpgo.Option{
+		mcpgo.WithServerInfo(cfg.ServerName, cfg.ServerVersion),
+		mcpgo.WithDefaultCategory(cfg.DefaultCategoryFilter),
+		mcpgo.WithAggregateMaxResults(cfg.AggregateMaxResults),
+		mcpgo.WithMaxBatchResults(cfg.MaxBatchResults),
+		mcpgo.WithSearchLimits(cfg.SearchDefaultLimit, cfg.SearchMaxLimit),
+		mcpgo.WithCanonicalMinConfidence(cfg.CanonicalMinConfidence),
+		mcpgo.WithStateless(cfg.StatelessMode),
+		mcpgo.WithNotablePercentile(cfg.NotablePercentile),
+		mcpgo.WithFoundSemantics(cfg.FoundSemantics),
+		mcpgo.WithStrictArgs(cfg.StrictArgs),
+		mcpgo.WithContentTypeMeta(cfg.ContentTypeMeta),
+		mcpgo.WithRateLimit(cfg.RateLimitRPS, cfg.RateLimitBurst),
+		mcpgo.WithMaxRequestBytes(int64(cfg.MaxRequestBytes)),
+		mcpgo.WithShutdownTimeout(time.Duration(cfg.ShutdownTimeoutSeconds) * time.Second),
+		mcpgo.WithTLS(cfg.TLSCertFile, cfg.TLSKeyFile),
+		mcpgo.WithResponseBudget(time.Duration(cfg.ResponseBudgetMs) * time.Millisecond),
+		mcpgo.WithHealthProbeQuery(cfg.HealthProbeQuery),
+	}
+
+	if cfg.ToolDescriptionsFile != "" {
+		descriptions, err := mcpgo.LoadToolDescriptions(cfg.ToolDescriptionsFile)
+		if err != nil {
+			return nil, err
+		}
+		logger.Info("Loaded tool description overrides",
+			"path", cfg.ToolDescriptionsFile,
+			"count", len(descriptions))
+		opts = append(opts, mcpgo.WithToolDescriptions(descriptions))
+	}
+
+	return mcpgo.NewServer(queryEngine, authenticator, logger, opts...), nil
+}
@@ -2,10 +2,18 @@ package cmd
 
 import (
 	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"testing/fstest"
 
+	"github.com/noot-app/foundation-foods-mcp-server/data"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // createTestRootCmd creates a fresh root command for testing to avoid state interference
@@ -110,3 +118,157 @@ func TestStdioFlag(t *testing.T) {
 	assert.Equal(t, "bool", stdioFlag.Value.Type(), "--stdio should be a boolean flag")
 	assert.Equal(t, "false", stdioFlag.DefValue, "--stdio should default to false")
 }
+
+func TestDetectStdioTransport(t *testing.T) {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	defer r.Close()
+	defer w.Close()
+
+	t.Run("piped stdin selects stdio", func(t *testing.T) {
+		t.Setenv("PORT", "")
+		os.Unsetenv("PORT")
+		assert.True(t, detectStdioTransport(r))
+	})
+
+	t.Run("explicit PORT keeps HTTP", func(t *testing.T) {
+		t.Setenv("PORT", "9090")
+		assert.False(t, detectStdioTransport(r))
+	})
+}
+
+func TestValidateDataFile(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "foods.json")
+	require.NoError(t, os.WriteFile(valid, []byte(`{"FoundationFoods": []}`), 0o600))
+	empty := filepath.Join(dir, "empty.json")
+	require.NoError(t, os.WriteFile(empty, nil, 0o600))
+
+	assert.NoError(t, validateDataFile(valid))
+	assert.ErrorContains(t, validateDataFile(filepath.Join(dir, "missing.json")), "not found")
+	assert.ErrorContains(t, validateDataFile(empty), "is empty")
+	assert.ErrorContains(t, validateDataFile(dir), "is a directory")
+}
+
+func TestListenAddress(t *testing.T) {
+	cases := []struct {
+		bindAddress string
+		port        string
+		want        string
+	}{
+		{"0.0.0.0", "8080", "0.0.0.0:8080"},
+		{"127.0.0.1", "9000", "127.0.0.1:9000"},
+		{"localhost", "8080", "localhost:8080"},
+		{"::1", "8080", "[::1]:8080"},
+		{"[::]", "8080", "[::]:8080"},
+	}
+	for _, tc := range cases {
+		addr, err := listenAddress(tc.bindAddress, tc.port)
+		require.NoError(t, err, tc.bindAddress)
+		assert.Equal(t, tc.want, addr)
+	}
+
+	for _, bindAddress := range []string{"", "256.0.0.1", "example com", "127.0.0.1:8080"} {
+		_, err := listenAddress(bindAddress, "8080")
+		assert.ErrorContains(t, err, "invalid BIND_ADDRESS", bindAddress)
+	}
+
+	for _, port := range []string{"", "http", "0", "65536", "-1"} {
+		_, err := listenAddress("0.0.0.0", port)
+		assert.ErrorContains(t, err, "invalid PORT", port)
+	}
+}
+
+func TestValidateTLSFiles(t *testing.T) {
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "not.pem")
+	require.NoError(t, os.WriteFile(notPEM, []byte("not a certificate"), 0o600))
+	missing := filepath.Join(dir, "missing.pem")
+
+	assert.NoError(t, validateTLSFiles("", ""))
+	assert.ErrorContains(t, validateTLSFiles(notPEM, ""), "TLS_KEY_FILE is not")
+	assert.ErrorContains(t, validateTLSFiles("", notPEM), "TLS_CERT_FILE is not")
+	assert.ErrorContains(t, validateTLSFiles(missing, notPEM), "cannot read TLS_CERT_FILE")
+	assert.ErrorContains(t, validateTLSFiles(notPEM, missing), "cannot read TLS_KEY_FILE")
+	assert.ErrorContains(t, validateTLSFiles(notPEM, notPEM), "invalid TLS certificate")
+}
+
+func TestUseEmbeddedDataset(t *testing.T) {
+	unsetEnv := func(t *testing.T, key string) {
+		t.Setenv(key, "")
+		require.NoError(t, os.Unsetenv(key))
+	}
+	withDataset := func(t *testing.T, dataset fs.FS) {
+		original := data.Dataset
+		data.Dataset = dataset
+		t.Cleanup(func() { data.Dataset = original })
+	}
+
+	t.Run("sentinel fails without an embedded dataset", func(t *testing.T) {
+		withDataset(t, nil)
+
+		_, err := useEmbeddedDataset(&config.Config{FoundationFoodsJsonFile: "embedded"})
+		assert.ErrorContains(t, err, "-tags embeddata")
+		assert.ErrorContains(t, validateDataset(&config.Config{FoundationFoodsJsonFile: "embedded"}), "-tags embeddata")
+	})
+
+	t.Run("sentinel selects the embedded dataset", func(t *testing.T) {
+		withDataset(t, fstest.MapFS{})
+
+		embedded, err := useEmbeddedDataset(&config.Config{FoundationFoodsJsonFile: "embedded"})
+		require.NoError(t, err)
+		assert.True(t, embedded)
+	})
+
+	t.Run("embedded dataset is used when no location is configured", func(t *testing.T) {
+		withDataset(t, fstest.MapFS{})
+		unsetEnv(t, "FOUNDATIONFOODS_JSON_FILE")
+		unsetEnv(t, "DATA_DIR")
+
+		embedded, err := useEmbeddedDataset(&config.Config{FoundationFoodsJsonFile: "data/foods.json"})
+		require.NoError(t, err)
+		assert.True(t, embedded)
+	})
+
+	t.Run("a configured file wins over the embedded dataset", func(t *testing.T) {
+		withDataset(t, fstest.MapFS{})
+		t.Setenv("FOUNDATIONFOODS_JSON_FILE", "data/foods.json")
+
+		embedded, err := useEmbeddedDataset(&config.Config{FoundationFoodsJsonFile: "data/foods.json"})
+		require.NoError(t, err)
+		assert.False(t, embedded)
+	})
+
+	t.Run("filesystem loading without an embedded dataset", func(t *testing.T) {
+		withDataset(t, nil)
+		unsetEnv(t, "FOUNDATIONFOODS_JSON_FILE")
+		unsetEnv(t, "DATA_DIR")
+
+		embedded, err := useEmbeddedDataset(&config.Config{FoundationFoodsJsonFile: "data/foods.json"})
+		require.NoError(t, err)
+		assert.False(t, embedded)
+	})
+}
+
+func TestRunStdioMode_MissingDataFile(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.json")
+	t.Setenv("FOUNDATIONFOODS_JSON_FILE", missing)
+
+	stderr := new(bytes.Buffer)
+	cmd := &cobra.Command{}
+	cmd.SetErr(stderr)
+
+	err := runStdioMode(cmd, nil)
+
+	require.Error(t, err)
+	assert.True(t, IsReported(err))
+	assert.True(t, cmd.SilenceErrors)
+	assert.True(t, cmd.SilenceUsage)
+
+	// A single actionable line naming the file and the setting to fix
+	output := stderr.String()
+	assert.Equal(t, 1, strings.Count(output, "\n"))
+	assert.Contains(t, output, missing)
+	assert.Contains(t, output, "FOUNDATIONFOODS_JSON_FILE")
+}
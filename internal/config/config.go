@@ -2,10 +2,15 @@ package config
 
 import (
 	"bufio"
+	"errors"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/version"
 )
 
 // FileReader interface for dependency injection in tests
@@ -30,9 +35,97 @@ type Config struct {
 
 	FoundationFoodsJsonFile string
 
+	// HistoryDataFiles are older dataset releases loaded for nutrient history lookups
+	HistoryDataFiles []string
+
+	// DefaultCategoryFilter scopes all searches to a single food category (empty searches everything)
+	DefaultCategoryFilter string
+
+	// MaxBatchResults caps names × limit for a single batch search
+	MaxBatchResults int
+
+	// AggregateMaxResults caps how many rows a single aggregate tool call may return
+	AggregateMaxResults int
+
+	// CanonicalMinConfidence is the confidence below which canonicalize_food_name returns no match
+	CanonicalMinConfidence float64
+
+	// UpperLimitsFile is an optional JSON file of Tolerable Upper Intake Levels adding to or replacing the built-in ones
+	UpperLimitsFile string
+
+	// SynonymsFile is an optional JSON file of search synonyms adding to or replacing the built-in ones
+	SynonymsFile string
+
+	// ScoringWeightsFile is an optional JSON file overriding the default relevance scoring weights
+	ScoringWeightsFile string
+
+	// ToolDescriptionsFile is an optional JSON file mapping tool names to replacement descriptions
+	ToolDescriptionsFile string
+
+	// HealthProbeQuery is the sentinel query the health endpoint must find results for (empty disables the probe)
+	HealthProbeQuery string
+
+	// NotablePercentile is the percentile rank a nutrient must reach to be returned with notable_only
+	NotablePercentile float64
+
+	// StrictArgs rejects tool calls that pass arguments the tool doesn't define
+	StrictArgs bool
+
+	// ContentTypeMeta declares the media type (JSON or markdown) of tool result text in the content's _meta
+	ContentTypeMeta bool
+
+	// ResponseBudgetMs bounds how long a name search may scan before returning partial results (0 disables it)
+	ResponseBudgetMs int
+
+	// FoundSemantics selects what the found flag means: has_results or query_succeeded
+	FoundSemantics string
+
+	// DropInvalidPortions removes portions without a positive gram weight when the dataset is loaded
+	DropInvalidPortions bool
+
+	// RebuildConcurrency bounds the goroutines rebuilding search indexes when the dataset is loaded or refreshed
+	RebuildConcurrency int
+
+	// SearchCacheSize is how many recent search results are cached (0 disables the cache)
+	SearchCacheSize int
+
+	// SearchDefaultLimit and SearchMaxLimit are the default and maximum number of foods a name search returns
+	SearchDefaultLimit int
+	SearchMaxLimit     int
+
+	// MaxFoodsToLoad caps how many foods are loaded from the dataset (0 loads everything)
+	MaxFoodsToLoad int
+
 	// Server
 	Port string
 
+	// BindAddress is the host or IP the HTTP server listens on, e.g. 127.0.0.1 to accept only local connections
+	BindAddress string
+
+	// TLSCertFile and TLSKeyFile are PEM files that switch the HTTP server to HTTPS when both are set
+	TLSCertFile string
+	TLSKeyFile  string
+
+	// RateLimitRPS and RateLimitBurst limit each /mcp client's requests per second and burst size (0 RPS disables limiting)
+	RateLimitRPS   float64
+	RateLimitBurst int
+
+	// MaxRequestBytes caps the size of /mcp request bodies
+	MaxRequestBytes int
+
+	// ShutdownTimeoutSeconds is how long the HTTP server drains in-flight requests on SIGINT/SIGTERM
+	ShutdownTimeoutSeconds int
+
+	// ServerName and ServerVersion are reported in the MCP serverInfo on initialize
+	ServerName    string
+	ServerVersion string
+
+	// AutoTransport runs in stdio mode without --stdio when stdin is piped and PORT isn't set
+	AutoTransport bool
+
+	// StatelessMode disables MCP session tracking on the HTTP transport
+	StatelessMode bool
+
 	// Environment
 	Environment string // "development" or "production"
 }
@@ -43,23 +136,62 @@ func (c *Config) IsDevelopment() bool {
 }
 
 // Load reads configuration from environment variables
-func Load() *Config {
+func Load() (*Config, error) {
 	return LoadWithFileReader(OSFileReader{})
 }
 
-// LoadWithFileReader reads configuration from environment variables with injectable file reader
-func LoadWithFileReader(fileReader FileReader) *Config {
+// LoadWithFileReader reads configuration from environment variables with injectable file reader. It
+// fails on numeric or boolean variables that don't parse, naming every malformed key and value, rather
+// than quietly running with their defaults.
+func LoadWithFileReader(fileReader FileReader) (*Config, error) {
 	// Load .env file if it exists (CLI env vars will override)
 	loadEnvFileWithReader(fileReader)
 
 	dataDir := getEnv("DATA_DIR", "./data")
 
-	return &Config{
+	env := &envParser{}
+	cfg := &Config{
 		AuthToken:               getEnv("FOUNDATIONFOODS_MCP_TOKEN", "super-secret-token"),
 		FoundationFoodsJsonFile: getEnv("FOUNDATIONFOODS_JSON_FILE", filepath.Join(dataDir, "foundationfoods_2025-04-24.json")),
+		HistoryDataFiles:        getEnvList("HISTORY_DATA_FILES"),
+		DefaultCategoryFilter:   getEnv("DEFAULT_CATEGORY_FILTER", ""),
+		MaxFoodsToLoad:          env.int("MAX_FOODS_TO_LOAD", 0),
+		DropInvalidPortions:     env.bool("DROP_INVALID_PORTIONS", false),
+		RebuildConcurrency:      env.int("REBUILD_CONCURRENCY", 1),
+		AggregateMaxResults:     env.int("AGGREGATE_MAX_RESULTS", 50),
+		MaxBatchResults:         env.int("MAX_BATCH_RESULTS", 50),
+		SearchDefaultLimit:      env.int("SEARCH_DEFAULT_LIMIT", 3),
+		SearchMaxLimit:          env.int("SEARCH_MAX_LIMIT", 10),
+		CanonicalMinConfidence:  env.float("CANONICAL_MIN_CONFIDENCE", 0.1),
+		NotablePercentile:       env.float("NOTABLE_PERCENTILE", 75),
+		StrictArgs:              env.bool("STRICT_ARGS", false),
+		ContentTypeMeta:         env.bool("CONTENT_TYPE_META", false),
+		ResponseBudgetMs:        env.int("RESPONSE_BUDGET_MS", 0),
+		FoundSemantics:          getEnv("FOUND_SEMANTICS", "has_results"),
+		HealthProbeQuery:        getEnvAllowEmpty("HEALTH_PROBE_QUERY", "milk"),
+		ToolDescriptionsFile:    getEnv("TOOL_DESCRIPTIONS_FILE", ""),
+		UpperLimitsFile:         getEnv("UPPER_LIMITS_FILE", ""),
+		SynonymsFile:            getEnv("SYNONYMS_FILE", ""),
+		ScoringWeightsFile:      getEnv("SCORING_WEIGHTS_FILE", ""),
+		SearchCacheSize:         env.int("SEARCH_CACHE_SIZE", 256),
 		Port:                    getEnv("PORT", "8080"),
+		BindAddress:             getEnv("BIND_ADDRESS", "0.0.0.0"),
+		TLSCertFile:             getEnv("TLS_CERT_FILE", ""),
+		TLSKeyFile:              getEnv("TLS_KEY_FILE", ""),
+		RateLimitRPS:            env.float("RATE_LIMIT_RPS", 0),
+		RateLimitBurst:          env.int("RATE_LIMIT_BURST", 20),
+		ShutdownTimeoutSeconds:  env.int("SHUTDOWN_TIMEOUT_SECONDS", 10),
+		MaxRequestBytes:         env.int("MAX_REQUEST_BYTES", 1<<20),
+		ServerName:              getEnv("SERVER_NAME", "FoundationFoods MCP Server"),
+		ServerVersion:           getEnv("SERVER_VERSION", version.Tag()),
+		AutoTransport:           env.bool("AUTO_TRANSPORT", false),
+		StatelessMode:           env.bool("STATELESS_MODE", true),
 		Environment:             getEnv("ENV", "production"),
 	}
+	if err := errors.Join(env.errs...); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	return cfg, nil
 }
 
 func loadEnvFileWithReader(fileReader FileReader) {
@@ -101,3 +233,80 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getEnvAllowEmpty is like getEnv but keeps an explicitly empty value instead of falling back to the default
+func getEnvAllowEmpty(key, defaultValue string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvList splits a comma-separated environment variable, dropping empty entries
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// envParser reads typed environment variables, collecting an error for each malformed value
+type envParser struct {
+	errs []error
+}
+
+// lookup returns the trimmed value of key, or ok=false when it is unset or empty
+func (p *envParser) lookup(key string) (string, bool) {
+	value := strings.TrimSpace(os.Getenv(key))
+	return value, value != ""
+}
+
+// fail records a malformed value for key
+func (p *envParser) fail(key, value string, err error) {
+	p.errs = append(p.errs, fmt.Errorf("%s=%q: %w", key, value, errors.Unwrap(err)))
+}
+
+func (p *envParser) int(key string, defaultValue int) int {
+	value, ok := p.lookup(key)
+	if !ok {
+		return defaultValue
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		p.fail(key, value, err)
+		return defaultValue
+	}
+	return parsed
+}
+
+func (p *envParser) float(key string, defaultValue float64) float64 {
+	value, ok := p.lookup(key)
+	if !ok {
+		return defaultValue
+	}
+
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		p.fail(key, value, err)
+		return defaultValue
+	}
+	return parsed
+}
+
+func (p *envParser) bool(key string, defaultValue bool) bool {
+	value, ok := p.lookup(key)
+	if !ok {
+		return defaultValue
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		p.fail(key, value, err)
+		return defaultValue
+	}
+	return parsed
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// noEnvFile is a FileReader without a .env file, so tests see only the variables they set
+type noEnvFile struct{}
+
+func (noEnvFile) Open(name string) (io.ReadCloser, error) {
+	return nil, os.ErrNotExist
+}
+
+func TestLoadWithFileReader(t *testing.T) {
+	t.Run("parses typed variables", func(t *testing.T) {
+		t.Setenv("MAX_FOODS_TO_LOAD", " 100 ")
+		t.Setenv("NOTABLE_PERCENTILE", "90.5")
+		t.Setenv("STRICT_ARGS", "true")
+
+		cfg, err := LoadWithFileReader(noEnvFile{})
+
+		require.NoError(t, err)
+		assert.Equal(t, 100, cfg.MaxFoodsToLoad)
+		assert.Equal(t, 90.5, cfg.NotablePercentile)
+		assert.True(t, cfg.StrictArgs)
+	})
+
+	t.Run("uses defaults for unset variables", func(t *testing.T) {
+		t.Setenv("MAX_FOODS_TO_LOAD", "")
+		t.Setenv("STATELESS_MODE", "")
+
+		cfg, err := LoadWithFileReader(noEnvFile{})
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, cfg.MaxFoodsToLoad)
+		assert.True(t, cfg.StatelessMode)
+	})
+
+	t.Run("fails on malformed values naming each key", func(t *testing.T) {
+		t.Setenv("MAX_FOODS_TO_LOAD", "1O")
+		t.Setenv("RATE_LIMIT_RPS", "fast")
+		t.Setenv("STATELESS_MODE", "maybe")
+
+		cfg, err := LoadWithFileReader(noEnvFile{})
+
+		require.Error(t, err)
+		assert.Nil(t, cfg)
+		assert.Contains(t, err.Error(), `MAX_FOODS_TO_LOAD="1O"`)
+		assert.Contains(t, err.Error(), `RATE_LIMIT_RPS="fast"`)
+		assert.Contains(t, err.Error(), `STATELESS_MODE="maybe"`)
+	})
+}
@@ -52,10 +52,10 @@ func NewLogger(isStdioMode bool) *slog.Logger {
 	if isStdioMode {
 		// For stdio mode (Claude Desktop), use text format to stderr
 		// to avoid interfering with MCP communication on stdout
-		return slog.New(slog.NewTextHandler(os.Stderr, opts))
+		return slog.New(requestIDHandler{slog.NewTextHandler(os.Stderr, opts)})
 	} else {
 		// For HTTP mode, use JSON format to stdout for structured logging
-		return slog.New(slog.NewJSONHandler(os.Stdout, opts))
+		return slog.New(requestIDHandler{slog.NewJSONHandler(os.Stdout, opts)})
 	}
 }
 
@@ -68,7 +68,7 @@ func NewTextLogger(output io.Writer) *slog.Logger {
 		Level: level,
 	}
 
-	return slog.New(slog.NewTextHandler(output, opts))
+	return slog.New(requestIDHandler{slog.NewTextHandler(output, opts)})
 }
 
 // NewTestLogger creates a logger for testing with configurable level and output
@@ -85,5 +85,5 @@ func NewTestLogger(output io.Writer, level string) *slog.Logger {
 		Level: logLevel,
 	}
 
-	return slog.New(slog.NewTextHandler(output, opts))
+	return slog.New(requestIDHandler{slog.NewTextHandler(output, opts)})
 }
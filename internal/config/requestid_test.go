@@ -0,0 +1,33 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestIDLogging(t *testing.T) {
+	output := new(bytes.Buffer)
+	logger := NewTestLogger(output, "debug").With("component", "engine")
+
+	ctx := WithRequestID(context.Background(), "abc123")
+	logger.DebugContext(ctx, "Searching Foundation Foods")
+	logger.Debug("Derived indexes rebuilt")
+
+	lines := bytes.Split(bytes.TrimSpace(output.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 2)
+	assert.Contains(t, string(lines[0]), "request_id=abc123")
+	assert.Contains(t, string(lines[0]), "component=engine")
+	assert.NotContains(t, string(lines[1]), "request_id")
+}
+
+func TestValidRequestID(t *testing.T) {
+	assert.True(t, ValidRequestID("trace-123"))
+	assert.True(t, ValidRequestID(NewRequestID()))
+	assert.False(t, ValidRequestID(""))
+	assert.False(t, ValidRequestID("has space"))
+	assert.False(t, ValidRequestID("line\nbreak"))
+	assert.False(t, ValidRequestID(string(bytes.Repeat([]byte("a"), 129))))
+}
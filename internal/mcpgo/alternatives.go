@@ -0,0 +1,58 @@
+package mcpgo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// defaultSearchAlternatives is how many alternatives search_with_alternatives returns when none are requested
+	defaultSearchAlternatives = 4
+
+	// maxSearchAlternatives caps the alternatives of a single search_with_alternatives call
+	maxSearchAlternatives = 10
+)
+
+func (s *Server) handleSearchWithAlternatives(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.log.DebugContext(ctx, "handleSearchWithAlternatives: Starting tool call",
+		"arguments", request.GetArguments())
+
+	name, err := request.RequireString("name")
+	if err != nil {
+		s.log.WarnContext(ctx, "handleSearchWithAlternatives: Missing 'name' parameter", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Missing required parameter 'name': %v", err)), nil
+	}
+
+	if strings.TrimSpace(name) == "" {
+		return mcp.NewToolResultError("Parameter 'name' must be at least 1 character long"), nil
+	}
+
+	alternatives := request.GetInt("alternatives", defaultSearchAlternatives)
+	if alternatives < 0 || alternatives > maxSearchAlternatives {
+		return mcp.NewToolResultError(fmt.Sprintf("Parameter 'alternatives' must be between 0 and %d", maxSearchAlternatives)), nil
+	}
+
+	opts := s.searchOptions(request)
+
+	s.log.DebugContext(ctx, "MCP search_with_alternatives called",
+		"name", name,
+		"alternatives", alternatives,
+		"category", opts.Category)
+
+	response, err := s.queryEngine.SearchWithAlternatives(ctx, name, alternatives, opts)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Search with alternatives failed", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Search failed: %v", err)), nil
+	}
+
+	matches := len(response.Alternatives)
+	if response.BestMatch != nil {
+		matches++
+	}
+	response.Found = s.found(matches)
+
+	return s.structuredResult(ctx, "handleSearchWithAlternatives", response)
+}
@@ -0,0 +1,95 @@
+package mcpgo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/query"
+)
+
+// defaultMaxBatchResults caps names × limit for a single batch search when not configured
+const defaultMaxBatchResults = 50
+
+// BatchSearchResult holds the matches for a single name of a batch search
+type BatchSearchResult struct {
+	Query    string                 `json:"query"`
+	Found    bool                   `json:"found"`
+	Count    int                    `json:"count"`
+	Products []query.FoundationFood `json:"products"`
+}
+
+// BatchSearchResponse represents the response for searching several food names in one call
+type BatchSearchResponse struct {
+	Count   int                 `json:"count"`
+	Results []BatchSearchResult `json:"results"`
+}
+
+// WithMaxBatchResults caps names × limit for a single batch search so one call can't scan the dataset too often
+func WithMaxBatchResults(maxResults int) Option {
+	return func(s *Server) {
+		if maxResults > 0 {
+			s.maxBatchResults = maxResults
+		}
+	}
+}
+
+func (s *Server) handleBatchFoodSearch(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.log.DebugContext(ctx, "handleBatchFoodSearch: Starting tool call",
+		"arguments", request.GetArguments())
+
+	names, err := request.RequireStringSlice("names")
+	if err != nil {
+		s.log.WarnContext(ctx, "handleBatchFoodSearch: Missing 'names' parameter", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Missing required parameter 'names': %v", err)), nil
+	}
+
+	if len(names) == 0 {
+		return mcp.NewToolResultError("Parameter 'names' must contain at least one name"), nil
+	}
+	for i, name := range names {
+		if strings.TrimSpace(name) == "" {
+			return mcp.NewToolResultError(fmt.Sprintf("Parameter 'names' contains an empty name at index %d", i)), nil
+		}
+	}
+
+	limit := s.searchLimits.Clamp(request.GetInt("limit", s.searchLimits.Default))
+
+	// Every name is a full scan of the dataset, so bound the combined work before running anything
+	if len(names)*limit > s.maxBatchResults {
+		s.log.WarnContext(ctx, "handleBatchFoodSearch: Batch exceeds result cap",
+			"name_count", len(names),
+			"limit", limit,
+			"max_batch_results", s.maxBatchResults)
+		return mcp.NewToolResultError(fmt.Sprintf(
+			"Batch too large: %d names × limit %d = %d results, but at most %d are allowed per call. Lower 'limit' or split the names across several calls.",
+			len(names), limit, len(names)*limit, s.maxBatchResults)), nil
+	}
+
+	s.log.DebugContext(ctx, "MCP batch_search_foundation_foods called",
+		"name_count", len(names),
+		"limit", limit)
+
+	opts := s.searchOptions(request)
+	response := BatchSearchResponse{
+		Count:   len(names),
+		Results: make([]BatchSearchResult, 0, len(names)),
+	}
+	for _, name := range names {
+		products, err := s.queryEngine.SearchFoodsByName(ctx, name, limit, opts)
+		if err != nil {
+			s.log.ErrorContext(ctx, "Batch food search failed", "name", name, "error", err)
+			return mcp.NewToolResultError(fmt.Sprintf("Search failed for %q: %v", name, err)), nil
+		}
+
+		response.Results = append(response.Results, BatchSearchResult{
+			Query:    name,
+			Found:    s.found(len(products)),
+			Count:    len(products),
+			Products: products,
+		})
+	}
+
+	return s.structuredResult(ctx, "handleBatchFoodSearch", response)
+}
@@ -0,0 +1,51 @@
+package mcpgo
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/auth"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_handleBatchFoodSearch(t *testing.T) {
+	logger := config.NewTestLogger(io.Discard, "debug")
+	mockEngine := &testQueryEngine{data: &query.FoundationFoodsData{
+		FoundationFoods: []query.FoundationFood{{Description: "Milk, whole", FdcId: 1}},
+	}}
+	server := NewServer(mockEngine, auth.NewBearerTokenAuth("test-token"), logger, WithMaxBatchResults(10))
+
+	t.Run("searches every name", func(t *testing.T) {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]any{"names": []any{"milk", "whole milk"}, "limit": 5}
+
+		result, err := server.handleBatchFoodSearch(context.Background(), request)
+
+		require.NoError(t, err)
+		require.False(t, result.IsError)
+		response, ok := result.StructuredContent.(BatchSearchResponse)
+		require.True(t, ok)
+		assert.Equal(t, 2, response.Count)
+		require.Len(t, response.Results, 2)
+		assert.Equal(t, "whole milk", response.Results[1].Query)
+		assert.True(t, response.Results[1].Found)
+	})
+
+	t.Run("rejects batches over the combined cap", func(t *testing.T) {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]any{"names": []any{"milk", "eggs", "bread"}, "limit": 4}
+
+		result, err := server.handleBatchFoodSearch(context.Background(), request)
+
+		require.NoError(t, err)
+		require.True(t, result.IsError)
+		text, ok := result.Content[0].(mcp.TextContent)
+		require.True(t, ok)
+		assert.Contains(t, text.Text, "3 names × limit 4 = 12 results, but at most 10 are allowed")
+	})
+}
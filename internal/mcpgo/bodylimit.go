@@ -0,0 +1,53 @@
+package mcpgo
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// defaultMaxRequestBytes caps /mcp request bodies when no limit is configured
+const defaultMaxRequestBytes = 1 << 20
+
+// WithMaxRequestBytes caps the size of /mcp request bodies; larger requests get 413 Request Entity Too Large.
+// Non-positive values keep the default of 1 MiB.
+func WithMaxRequestBytes(maxBytes int64) Option {
+	return func(s *Server) {
+		if maxBytes > 0 {
+			s.maxRequestBytes = maxBytes
+		}
+	}
+}
+
+// readLimitedBody buffers the request body up to the configured limit so an oversized request is answered with
+// 413 instead of the transport's generic parse error. It reports false after writing the 413 response.
+func (s *Server) readLimitedBody(w http.ResponseWriter, r *http.Request) bool {
+	if r.ContentLength > s.maxRequestBytes {
+		s.rejectOversizedBody(w, r)
+		return false
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.maxRequestBytes))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			s.rejectOversizedBody(w, r)
+			return false
+		}
+		// Leave other read errors to the transport, which reports them as JSON-RPC parse errors
+		body = nil
+	}
+
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return true
+}
+
+func (s *Server) rejectOversizedBody(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusRequestEntityTooLarge)
+	w.Write([]byte("Request Entity Too Large"))
+	s.log.WarnContext(r.Context(), "Rejected oversized MCP request",
+		"remote_addr", r.RemoteAddr,
+		"content_length", r.ContentLength,
+		"max_request_bytes", s.maxRequestBytes)
+}
@@ -0,0 +1,61 @@
+package mcpgo
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/auth"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/query"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_MaxRequestBytes(t *testing.T) {
+	toolCall := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"search_foundation_foods_by_name","arguments":{"name":"milk"}}}`
+
+	mockEngine := &testQueryEngine{data: &query.FoundationFoodsData{
+		FoundationFoods: []query.FoundationFood{{Description: "Milk, whole", FdcId: 1}},
+	}}
+	logger := config.NewTestLogger(io.Discard, "debug")
+	handler := NewServer(mockEngine, auth.NewBearerTokenAuth("test-token"), logger, WithMaxRequestBytes(int64(len(toolCall)))).Handler()
+
+	post := func(body io.Reader, contentLength int64) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", body)
+		req.ContentLength = contentLength
+		req.Header.Set("Authorization", "Bearer test-token")
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("accepts requests within the limit", func(t *testing.T) {
+		rec := post(strings.NewReader(toolCall), int64(len(toolCall)))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Milk, whole")
+	})
+
+	t.Run("rejects a declared length over the limit with 413", func(t *testing.T) {
+		body := toolCall + " "
+		rec := post(strings.NewReader(body), int64(len(body)))
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	})
+
+	t.Run("rejects an oversized body without a declared length with 413", func(t *testing.T) {
+		rec := post(strings.NewReader(toolCall+strings.Repeat(" ", 100)), -1)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	})
+
+	t.Run("defaults to 1 MiB", func(t *testing.T) {
+		server := NewServer(mockEngine, auth.NewBearerTokenAuth("test-token"), logger, WithMaxRequestBytes(0))
+
+		assert.Equal(t, int64(1<<20), server.maxRequestBytes)
+	})
+}
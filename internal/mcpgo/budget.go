@@ -0,0 +1,25 @@
+package mcpgo
+
+import (
+	"context"
+	"time"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/query"
+)
+
+// WithResponseBudget bounds how long a name search may scan the dataset; a search that runs out returns
+// the best matches found so far, flagged partialDueToBudget (0 disables the budget)
+func WithResponseBudget(budget time.Duration) Option {
+	return func(s *Server) {
+		s.responseBudget = budget
+	}
+}
+
+// budgetContext applies the response budget to a search's context, returning a function that reports
+// whether the search was cut short
+func (s *Server) budgetContext(ctx context.Context) (context.Context, func() bool) {
+	if s.responseBudget <= 0 {
+		return ctx, func() bool { return false }
+	}
+	return query.WithScanBudget(ctx, s.responseBudget)
+}
@@ -0,0 +1,82 @@
+package mcpgo
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/auth"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slowQueryEngine takes delay to scan and, like the real engine, keeps only what it found before the budget ran out
+type slowQueryEngine struct {
+	*testQueryEngine
+	delay time.Duration
+}
+
+func (e *slowQueryEngine) SearchFoodsByName(ctx context.Context, name string, limit int, opts query.SearchOptions) ([]query.FoundationFood, error) {
+	foods, err := e.testQueryEngine.SearchFoodsByName(ctx, name, limit, opts)
+	time.Sleep(e.delay)
+	if query.BudgetExceeded(ctx) && len(foods) > 1 {
+		foods = foods[:1]
+	}
+	return foods, err
+}
+
+func (e *slowQueryEngine) SearchFoodsByNameWithTotal(ctx context.Context, name string, limit int, opts query.SearchOptions) ([]query.FoundationFood, int, error) {
+	foods, err := e.SearchFoodsByName(ctx, name, limit, opts)
+	return foods, len(foods), err
+}
+
+func TestServer_ResponseBudget(t *testing.T) {
+	logger := config.NewTestLogger(io.Discard, "debug")
+	mockEngine := &slowQueryEngine{
+		testQueryEngine: &testQueryEngine{data: &query.FoundationFoodsData{
+			FoundationFoods: []query.FoundationFood{
+				{Description: "Milk, whole", FdcId: 1},
+				{Description: "Milk, reduced fat", FdcId: 2},
+			},
+		}},
+		delay: 20 * time.Millisecond,
+	}
+
+	search := func(t *testing.T, server *Server) query.SearchProductsResponse {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]any{"name": "milk", "limit": float64(5)}
+
+		result, err := server.handleFoodSearch(context.Background(), request)
+		require.NoError(t, err)
+		require.False(t, result.IsError)
+
+		encoded, err := json.Marshal(result.StructuredContent)
+		require.NoError(t, err)
+		var response query.SearchProductsResponse
+		require.NoError(t, json.Unmarshal(encoded, &response))
+		return response
+	}
+
+	t.Run("flags partial results when the budget runs out", func(t *testing.T) {
+		server := NewServer(mockEngine, auth.NewBearerTokenAuth("test-token"), logger, WithResponseBudget(time.Millisecond))
+
+		response := search(t, server)
+
+		assert.True(t, response.PartialDueToBudget)
+		assert.Len(t, response.Products, 1)
+	})
+
+	t.Run("returns complete results without a budget", func(t *testing.T) {
+		server := NewServer(mockEngine, auth.NewBearerTokenAuth("test-token"), logger)
+
+		response := search(t, server)
+
+		assert.False(t, response.PartialDueToBudget)
+		assert.Len(t, response.Products, 2)
+	})
+}
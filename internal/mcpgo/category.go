@@ -0,0 +1,40 @@
+package mcpgo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func (s *Server) handleFoodVsCategory(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.log.DebugContext(ctx, "handleFoodVsCategory: Starting tool call",
+		"arguments", request.GetArguments())
+
+	fdcId, err := request.RequireInt("fdcId")
+	if err != nil {
+		s.log.WarnContext(ctx, "handleFoodVsCategory: Missing 'fdcId' parameter", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Missing required parameter 'fdcId': %v", err)), nil
+	}
+
+	offset, limit, err := s.aggregatePage(request, 0)
+	if err != nil {
+		s.log.WarnContext(ctx, "handleFoodVsCategory: Invalid 'offset' parameter", "error", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	s.log.DebugContext(ctx, "MCP food_vs_category called",
+		"fdcId", fdcId,
+		"offset", offset,
+		"limit", limit)
+
+	response, err := s.queryEngine.CompareFoodToCategory(ctx, fdcId)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Food vs category comparison failed", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Comparison failed: %v", err)), nil
+	}
+
+	response.Nutrients, _, response.Page = aggregateRows(response.Nutrients, offset, limit)
+
+	return s.structuredResult(ctx, "handleFoodVsCategory", response)
+}
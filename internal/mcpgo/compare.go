@@ -0,0 +1,38 @@
+package mcpgo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/query"
+)
+
+func (s *Server) handleCompareFoundationFoods(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.log.DebugContext(ctx, "handleCompareFoundationFoods: Starting tool call",
+		"arguments", request.GetArguments())
+
+	fdcIds, err := request.RequireIntSlice("fdcIds")
+	if err != nil {
+		s.log.WarnContext(ctx, "handleCompareFoundationFoods: Missing 'fdcIds' parameter", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Missing required parameter 'fdcIds': %v", err)), nil
+	}
+
+	if len(fdcIds) < query.MinCompareFoods || len(fdcIds) > query.MaxCompareFoods {
+		return mcp.NewToolResultError(fmt.Sprintf("Parameter 'fdcIds' must list %d to %d foods", query.MinCompareFoods, query.MaxCompareFoods)), nil
+	}
+
+	nutrientsToInclude := request.GetStringSlice("nutrients_to_include", query.DefaultNutrients)
+
+	s.log.DebugContext(ctx, "MCP compare_foundation_foods called",
+		"fdcIds", fdcIds,
+		"nutrients_count", len(nutrientsToInclude))
+
+	response, err := s.queryEngine.CompareFoods(ctx, fdcIds, nutrientsToInclude)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Food comparison failed", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Comparison failed: %v", err)), nil
+	}
+
+	return s.structuredResult(ctx, "handleCompareFoundationFoods", response)
+}
@@ -0,0 +1,68 @@
+package mcpgo
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Media types declared for tool result text
+const (
+	mediaTypeJSON     = "application/json"
+	mediaTypeMarkdown = "text/markdown"
+	mediaTypeCSV      = "text/csv"
+)
+
+// contentTypeMetaKey is the _meta key of a text content item that carries its media type
+const contentTypeMetaKey = "mimeType"
+
+// WithContentTypeMeta makes successful tool results declare the media type of their text content in its
+// _meta, so gateways and renderers don't have to guess whether the text is JSON or markdown
+func WithContentTypeMeta(enabled bool) Option {
+	return func(s *Server) {
+		s.contentTypeMeta = enabled
+	}
+}
+
+// resultMediaType returns the media type of a tool result's text: markdown or CSV when the call asked for
+// that format, JSON otherwise
+func resultMediaType(request mcp.CallToolRequest) string {
+	switch request.GetString("format", formatJSON) {
+	case formatMarkdown:
+		return mediaTypeMarkdown
+	case formatCSV:
+		return mediaTypeCSV
+	default:
+		return mediaTypeJSON
+	}
+}
+
+// contentTypeHandler wraps a tool handler so the text content of successful results carries its media type.
+// Error results are plain text messages and are left alone.
+func contentTypeHandler(handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := handler(ctx, request)
+		if err != nil || result == nil || result.IsError {
+			return result, err
+		}
+
+		mediaType := resultMediaType(request)
+		for i, content := range result.Content {
+			text, ok := content.(mcp.TextContent)
+			if !ok {
+				continue
+			}
+			if text.Meta == nil {
+				text.Meta = &mcp.Meta{}
+			}
+			if text.Meta.AdditionalFields == nil {
+				text.Meta.AdditionalFields = make(map[string]any)
+			}
+			text.Meta.AdditionalFields[contentTypeMetaKey] = mediaType
+			result.Content[i] = text
+		}
+
+		return result, nil
+	}
+}
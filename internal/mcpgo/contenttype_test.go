@@ -0,0 +1,57 @@
+package mcpgo
+
+import (
+	"io"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/auth"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_ContentTypeMeta(t *testing.T) {
+	logger := config.NewTestLogger(io.Discard, "debug")
+	mockEngine := &testQueryEngine{
+		data: &query.FoundationFoodsData{
+			FoundationFoods: []query.FoundationFood{{Description: "Milk, whole", FdcId: 1}},
+		},
+		simplified: &query.SimplifiedNutrientResponse{
+			Found: true,
+			Count: 1,
+			Foods: []query.SimplifiedFood{{Name: "Milk, whole"}},
+		},
+	}
+
+	mediaType := func(t *testing.T, result *mcp.CallToolResult) any {
+		t.Helper()
+		require.False(t, result.IsError)
+		require.Len(t, result.Content, 1)
+		text, ok := result.Content[0].(mcp.TextContent)
+		require.True(t, ok)
+		if text.Meta == nil {
+			return nil
+		}
+		return text.Meta.AdditionalFields["mimeType"]
+	}
+
+	jsonCall := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"search_foundation_foods_and_return_nutrients","arguments":{"name":"milk"}}}`
+	markdownCall := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"search_foundation_foods_and_return_nutrients","arguments":{"name":"milk","format":"markdown"}}}`
+	csvCall := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"search_foundation_foods_and_return_nutrients","arguments":{"name":"milk","format":"csv"}}}`
+
+	t.Run("declares JSON, markdown and CSV text when enabled", func(t *testing.T) {
+		server := NewServer(mockEngine, auth.NewBearerTokenAuth("test-token"), logger, WithContentTypeMeta(true))
+
+		assert.Equal(t, "application/json", mediaType(t, callTool(t, server, jsonCall)))
+		assert.Equal(t, "text/markdown", mediaType(t, callTool(t, server, markdownCall)))
+		assert.Equal(t, "text/csv", mediaType(t, callTool(t, server, csvCall)))
+	})
+
+	t.Run("leaves results untouched by default", func(t *testing.T) {
+		server := NewServer(mockEngine, auth.NewBearerTokenAuth("test-token"), logger)
+
+		assert.Nil(t, mediaType(t, callTool(t, server, jsonCall)))
+	})
+}
@@ -0,0 +1,48 @@
+package mcpgo
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strconv"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/query"
+)
+
+// simplifiedCSVHeader is the header row of the CSV rendering of a simplified nutrient response
+var simplifiedCSVHeader = []string{"description", "fdcId", "nutrient", "amount", "unit"}
+
+// renderSimplifiedCSV renders a simplified nutrient response as CSV with one row per food nutrient.
+// The header row is always written, so an empty result is just the header.
+func renderSimplifiedCSV(response *query.SimplifiedNutrientResponse) (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	if err := w.Write(simplifiedCSVHeader); err != nil {
+		return "", err
+	}
+
+	if response != nil {
+		for _, food := range response.Foods {
+			fdcID := strconv.Itoa(food.FdcId)
+			for _, nutrient := range food.Nutrients {
+				record := []string{
+					food.Name,
+					fdcID,
+					nutrient.Name,
+					strconv.FormatFloat(nutrient.Amount, 'f', -1, 64),
+					nutrient.Unit,
+				}
+				if err := w.Write(record); err != nil {
+					return "", err
+				}
+			}
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
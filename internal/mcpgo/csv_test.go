@@ -0,0 +1,59 @@
+package mcpgo
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderSimplifiedCSV(t *testing.T) {
+	t.Run("renders a row per nutrient", func(t *testing.T) {
+		response := &query.SimplifiedNutrientResponse{
+			Found: true,
+			Count: 2,
+			Foods: []query.SimplifiedFood{
+				{
+					Name:  "Milk, whole, 3.25% milkfat",
+					FdcId: 746782,
+					Nutrients: []query.SimplifiedNutrient{
+						{Name: "Protein", Unit: "g", Amount: 3.27},
+						{Name: "Calcium, Ca", Unit: "mg", Amount: 123},
+					},
+				},
+				{
+					Name:  `Cheese, "cheddar"`,
+					FdcId: 328637,
+					Nutrients: []query.SimplifiedNutrient{
+						{Name: "Protein", Unit: "g", Amount: 23.3},
+					},
+				},
+			},
+		}
+
+		text, err := renderSimplifiedCSV(response)
+		require.NoError(t, err)
+
+		lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+		require.Len(t, lines, 4)
+		assert.Equal(t, "description,fdcId,nutrient,amount,unit", lines[0])
+		assert.Equal(t, `"Milk, whole, 3.25% milkfat",746782,Protein,3.27,g`, lines[1])
+		assert.Equal(t, `"Milk, whole, 3.25% milkfat",746782,"Calcium, Ca",123,mg`, lines[2])
+		assert.Equal(t, `"Cheese, ""cheddar""",328637,Protein,23.3,g`, lines[3])
+
+		// The escaped output parses back to the original values
+		records, err := csv.NewReader(strings.NewReader(text)).ReadAll()
+		require.NoError(t, err)
+		assert.Equal(t, []string{`Cheese, "cheddar"`, "328637", "Protein", "23.3", "g"}, records[3])
+	})
+
+	t.Run("renders only the header for empty results", func(t *testing.T) {
+		text, err := renderSimplifiedCSV(&query.SimplifiedNutrientResponse{})
+		require.NoError(t, err)
+
+		assert.Equal(t, "description,fdcId,nutrient,amount,unit\n", text)
+	})
+}
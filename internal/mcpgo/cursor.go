@@ -0,0 +1,109 @@
+package mcpgo
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/query"
+)
+
+// searchCursorTTL is how long a search cursor stays valid after it was issued
+const searchCursorTTL = 10 * time.Minute
+
+// searchCursor is the server-side state behind an opaque cursor token
+type searchCursor struct {
+	sessionID string
+	name      string
+	limit     int
+	opts      query.SearchOptions
+	expiresAt time.Time
+}
+
+// cursorStore holds issued search cursors. Tokens are opaque and only valid for the session they were issued to.
+type cursorStore struct {
+	mu      sync.Mutex
+	cursors map[string]searchCursor
+}
+
+func newCursorStore() *cursorStore {
+	return &cursorStore{cursors: make(map[string]searchCursor)}
+}
+
+// issue stores a cursor and returns its token, pruning expired cursors on the way
+func (c *cursorStore) issue(cursor searchCursor) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate cursor: %w", err)
+	}
+	token := hex.EncodeToString(buf)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for existing, stored := range c.cursors {
+		if now.After(stored.expiresAt) {
+			delete(c.cursors, existing)
+		}
+	}
+
+	cursor.expiresAt = now.Add(searchCursorTTL)
+	c.cursors[token] = cursor
+
+	return token, nil
+}
+
+// resolve returns the cursor for a token if it exists, hasn't expired and belongs to the session
+func (c *cursorStore) resolve(token, sessionID string) (searchCursor, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cursor, ok := c.cursors[token]
+	if !ok || cursor.sessionID != sessionID {
+		return searchCursor{}, fmt.Errorf("unknown cursor")
+	}
+	if time.Now().After(cursor.expiresAt) {
+		delete(c.cursors, token)
+		return searchCursor{}, fmt.Errorf("cursor expired")
+	}
+
+	return cursor, nil
+}
+
+// cursorSessionID returns the MCP session a cursor is scoped to, or false when cursors aren't available.
+// Cursors need a session that outlives the request, so they are only issued in stateful mode.
+func (s *Server) cursorSessionID(ctx context.Context) (string, bool) {
+	if s.stateless {
+		return "", false
+	}
+
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil || session.SessionID() == "" {
+		return "", false
+	}
+
+	return session.SessionID(), true
+}
+
+// nextSearchCursor issues a cursor for the page after the one just returned, or returns "" when it was the
+// last of the total matches
+func (s *Server) nextSearchCursor(sessionID, name string, limit int, opts query.SearchOptions, returned, total int) (string, error) {
+	if opts.Offset+returned >= total {
+		return "", nil
+	}
+
+	next := opts
+	next.Offset += limit
+
+	return s.cursors.issue(searchCursor{
+		sessionID: sessionID,
+		name:      name,
+		limit:     limit,
+		opts:      next,
+	})
+}
@@ -0,0 +1,117 @@
+package mcpgo
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/auth"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSession is a minimal client session for handlers that read the session from the context
+type testSession struct {
+	id string
+}
+
+func (t *testSession) Initialize()                                         {}
+func (t *testSession) Initialized() bool                                   { return true }
+func (t *testSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return nil }
+func (t *testSession) SessionID() string                                   { return t.id }
+
+func TestServer_handleFoodSearch_Cursor(t *testing.T) {
+	logger := config.NewTestLogger(io.Discard, "debug")
+	mockEngine := &testQueryEngine{data: &query.FoundationFoodsData{
+		FoundationFoods: []query.FoundationFood{
+			{Description: "Milk, whole", FdcId: 1},
+			{Description: "Milk, reduced fat", FdcId: 2},
+			{Description: "Milk, lowfat", FdcId: 3},
+			{Description: "Milk, nonfat", FdcId: 4},
+		},
+	}}
+	server := NewServer(mockEngine, auth.NewBearerTokenAuth("test-token"), logger, WithStateless(false))
+	ctx := server.mcpServer.WithContext(context.Background(), &testSession{id: "session-1"})
+
+	search := func(t *testing.T, ctx context.Context, args map[string]any) (query.SearchProductsResponse, *mcp.CallToolResult) {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+
+		result, err := server.handleFoodSearch(ctx, request)
+		require.NoError(t, err)
+		if result.IsError {
+			return query.SearchProductsResponse{}, result
+		}
+
+		response, ok := result.StructuredContent.(query.SearchProductsResponse)
+		require.True(t, ok)
+		return response, result
+	}
+
+	fdcIds := func(products []query.FoundationFood) []int {
+		var ids []int
+		for _, product := range products {
+			ids = append(ids, product.FdcId)
+		}
+		return ids
+	}
+
+	t.Run("walks two pages without overlap or gaps", func(t *testing.T) {
+		first, _ := search(t, ctx, map[string]any{"name": "milk", "limit": 2})
+		assert.Equal(t, []int{1, 2}, fdcIds(first.Products))
+		require.NotEmpty(t, first.NextCursor)
+
+		second, _ := search(t, ctx, map[string]any{"name": "milk", "cursor": first.NextCursor})
+		assert.Equal(t, []int{3, 4}, fdcIds(second.Products))
+
+		// The last page doesn't point at an empty page
+		assert.Empty(t, second.NextCursor)
+	})
+
+	t.Run("each page scans the dataset once", func(t *testing.T) {
+		mockEngine.searchCount = 0
+
+		first, _ := search(t, ctx, map[string]any{"name": "milk", "limit": 2})
+		require.NotEmpty(t, first.NextCursor)
+		assert.Equal(t, 1, mockEngine.searchCount)
+
+		second, _ := search(t, ctx, map[string]any{"name": "milk", "cursor": first.NextCursor})
+		assert.Empty(t, second.NextCursor)
+		assert.Equal(t, 2, mockEngine.searchCount)
+	})
+
+	t.Run("cursors are scoped to their session", func(t *testing.T) {
+		first, _ := search(t, ctx, map[string]any{"name": "milk", "limit": 2})
+		require.NotEmpty(t, first.NextCursor)
+
+		otherCtx := server.mcpServer.WithContext(context.Background(), &testSession{id: "session-2"})
+		_, result := search(t, otherCtx, map[string]any{"name": "milk", "cursor": first.NextCursor})
+
+		assert.True(t, result.IsError)
+	})
+
+	t.Run("cursors are bound to their query", func(t *testing.T) {
+		first, _ := search(t, ctx, map[string]any{"name": "milk", "limit": 2})
+		require.NotEmpty(t, first.NextCursor)
+
+		_, result := search(t, ctx, map[string]any{"name": "eggs", "cursor": first.NextCursor})
+
+		assert.True(t, result.IsError)
+	})
+
+	t.Run("stateless mode issues no cursor", func(t *testing.T) {
+		statelessServer := NewServer(mockEngine, auth.NewBearerTokenAuth("test-token"), logger)
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]any{"name": "milk", "limit": 2}
+
+		result, err := statelessServer.handleFoodSearch(statelessServer.mcpServer.WithContext(context.Background(), &testSession{id: "session-1"}), request)
+
+		require.NoError(t, err)
+		response, ok := result.StructuredContent.(query.SearchProductsResponse)
+		require.True(t, ok)
+		assert.Empty(t, response.NextCursor)
+	})
+}
@@ -0,0 +1,46 @@
+package mcpgo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/query"
+)
+
+// dailyIntakeArgs holds the list arguments of daily_intake_summary
+type dailyIntakeArgs struct {
+	Foods []query.IntakeEntry `json:"foods"`
+}
+
+func (s *Server) handleDailyIntakeSummary(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.log.DebugContext(ctx, "handleDailyIntakeSummary: Starting tool call",
+		"arguments", request.GetArguments())
+
+	var args dailyIntakeArgs
+	if err := request.BindArguments(&args); err != nil || len(args.Foods) == 0 {
+		s.log.WarnContext(ctx, "handleDailyIntakeSummary: Missing 'foods' parameter", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Missing required parameter 'foods': %v", err)), nil
+	}
+
+	demographic, err := request.RequireString("demographic")
+	if err != nil {
+		s.log.WarnContext(ctx, "handleDailyIntakeSummary: Missing 'demographic' parameter", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Missing required parameter 'demographic': %v", err)), nil
+	}
+
+	opts := s.searchOptions(request)
+
+	s.log.DebugContext(ctx, "MCP daily_intake_summary called",
+		"food_count", len(args.Foods),
+		"demographic", demographic,
+		"category", opts.Category)
+
+	response, err := s.queryEngine.DailyIntakeSummary(ctx, args.Foods, demographic, opts)
+	if err != nil {
+		s.log.WarnContext(ctx, "Daily intake summary failed", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Daily intake summary failed: %v", err)), nil
+	}
+
+	return s.structuredResult(ctx, "handleDailyIntakeSummary", response)
+}
@@ -0,0 +1,23 @@
+package mcpgo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func (s *Server) handleDatasetInfo(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.log.DebugContext(ctx, "handleDatasetInfo: Starting tool call",
+		"arguments", request.GetArguments())
+
+	s.log.DebugContext(ctx, "MCP get_dataset_info called")
+
+	response, err := s.queryEngine.DatasetInfo(ctx)
+	if err != nil {
+		s.log.WarnContext(ctx, "Dataset info failed", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Dataset info failed: %v", err)), nil
+	}
+
+	return s.structuredResult(ctx, "handleDatasetInfo", response)
+}
@@ -0,0 +1,39 @@
+package mcpgo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// defaultProteinDensityResults is how many foods rank_by_protein_density returns when no limit is given
+const defaultProteinDensityResults = 10
+
+func (s *Server) handleRankByProteinDensity(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.log.DebugContext(ctx, "handleRankByProteinDensity: Starting tool call",
+		"arguments", request.GetArguments())
+
+	offset, limit, err := s.aggregatePage(request, defaultProteinDensityResults)
+	if err != nil {
+		s.log.WarnContext(ctx, "handleRankByProteinDensity: Invalid 'offset' parameter", "error", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	opts := s.searchOptions(request)
+
+	s.log.DebugContext(ctx, "MCP rank_by_protein_density called",
+		"offset", offset,
+		"limit", limit,
+		"category", opts.Category)
+
+	response, err := s.queryEngine.RankByProteinDensity(ctx, 0, opts)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Protein density ranking failed", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Ranking failed: %v", err)), nil
+	}
+
+	response.Foods, response.Count, response.Page = aggregateRows(response.Foods, offset, limit)
+
+	return s.structuredResult(ctx, "handleRankByProteinDensity", response)
+}
@@ -0,0 +1,67 @@
+package mcpgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// LoadToolDescriptions reads a JSON object mapping tool names to replacement descriptions
+func LoadToolDescriptions(path string) (map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tool descriptions file: %w", err)
+	}
+
+	var descriptions map[string]string
+	if err := json.Unmarshal(content, &descriptions); err != nil {
+		return nil, fmt.Errorf("failed to parse tool descriptions file: %w", err)
+	}
+
+	return descriptions, nil
+}
+
+// WithToolDescriptions overrides the built-in descriptions of the named tools. Tools without an
+// entry keep their default description.
+func WithToolDescriptions(descriptions map[string]string) Option {
+	return func(s *Server) {
+		s.toolDescriptions = descriptions
+	}
+}
+
+// addTool registers a tool, applying any configured description override first and rejecting
+// unknown arguments in strict mode
+func (s *Server) addTool(tool mcp.Tool, handler server.ToolHandlerFunc) {
+	if description, ok := s.toolDescriptions[tool.Name]; ok && description != "" {
+		tool.Description = description
+	}
+
+	if s.contentTypeMeta {
+		handler = contentTypeHandler(handler)
+	}
+
+	if s.strictArgs {
+		handler = strictArgsHandler(tool, handler)
+	}
+
+	handler = requestIDToolHandler(handler)
+
+	if s.toolNames == nil {
+		s.toolNames = make(map[string]bool)
+	}
+	s.toolNames[tool.Name] = true
+
+	s.mcpServer.AddTool(tool, handler)
+}
+
+// warnUnknownToolDescriptions logs overrides that don't match a registered tool, which usually means a typo
+func (s *Server) warnUnknownToolDescriptions() {
+	for name := range s.toolDescriptions {
+		if !s.toolNames[name] {
+			s.log.Warn("Ignoring description override for unknown tool", "tool", name)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package mcpgo
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/auth"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_ToolDescriptionOverrides(t *testing.T) {
+	logger := config.NewTestLogger(io.Discard, "debug")
+
+	path := filepath.Join(t.TempDir(), "descriptions.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"resolve_foods": "Resuelve una lista de nombres de alimentos."}`), 0o600))
+
+	descriptions, err := LoadToolDescriptions(path)
+	require.NoError(t, err)
+
+	mockEngine := &testQueryEngine{data: &query.FoundationFoodsData{}}
+	server := NewServer(mockEngine, auth.NewBearerTokenAuth("test-token"), logger, WithToolDescriptions(descriptions))
+
+	message := server.mcpServer.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+	encoded, err := json.Marshal(message)
+	require.NoError(t, err)
+
+	var response struct {
+		Result mcp.ListToolsResult `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(encoded, &response))
+
+	toolDescriptions := make(map[string]string)
+	for _, tool := range response.Result.Tools {
+		toolDescriptions[tool.Name] = tool.Description
+	}
+
+	assert.Equal(t, "Resuelve una lista de nombres de alimentos.", toolDescriptions["resolve_foods"])
+
+	// Tools without an override keep their built-in description
+	assert.Contains(t, toolDescriptions["search_foundation_foods_by_name"], "Search USDA foundation foods by name")
+}
+
+func TestLoadToolDescriptions(t *testing.T) {
+	t.Run("returns error for missing file", func(t *testing.T) {
+		_, err := LoadToolDescriptions(filepath.Join(t.TempDir(), "missing.json"))
+
+		assert.Error(t, err)
+	})
+
+	t.Run("returns error for malformed file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "descriptions.json")
+		require.NoError(t, os.WriteFile(path, []byte(`["not", "an", "object"]`), 0o600))
+
+		_, err := LoadToolDescriptions(path)
+
+		assert.Error(t, err)
+	})
+}
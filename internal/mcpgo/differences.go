@@ -0,0 +1,54 @@
+package mcpgo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/query"
+)
+
+// defaultNutrientDifferences is how many nutrients top_nutrient_differences returns when no limit is given
+const defaultNutrientDifferences = 5
+
+func (s *Server) handleTopNutrientDifferences(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.log.DebugContext(ctx, "handleTopNutrientDifferences: Starting tool call",
+		"arguments", request.GetArguments())
+
+	fdcIdA, err := request.RequireInt("fdcIdA")
+	if err != nil {
+		s.log.WarnContext(ctx, "handleTopNutrientDifferences: Missing 'fdcIdA' parameter", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Missing required parameter 'fdcIdA': %v", err)), nil
+	}
+
+	fdcIdB, err := request.RequireInt("fdcIdB")
+	if err != nil {
+		s.log.WarnContext(ctx, "handleTopNutrientDifferences: Missing 'fdcIdB' parameter", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Missing required parameter 'fdcIdB': %v", err)), nil
+	}
+
+	offset, limit, err := s.aggregatePage(request, defaultNutrientDifferences)
+	if err != nil {
+		s.log.WarnContext(ctx, "handleTopNutrientDifferences: Invalid 'offset' parameter", "error", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	rankBy := request.GetString("rank_by", query.RankByAbsolute)
+
+	s.log.DebugContext(ctx, "MCP top_nutrient_differences called",
+		"fdcIdA", fdcIdA,
+		"fdcIdB", fdcIdB,
+		"offset", offset,
+		"limit", limit,
+		"rank_by", rankBy)
+
+	response, err := s.queryEngine.TopNutrientDifferences(ctx, fdcIdA, fdcIdB, 0, rankBy)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Nutrient difference comparison failed", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Comparison failed: %v", err)), nil
+	}
+
+	response.Nutrients, response.Count, response.Page = aggregateRows(response.Nutrients, offset, limit)
+
+	return s.structuredResult(ctx, "handleTopNutrientDifferences", response)
+}
@@ -0,0 +1,38 @@
+package mcpgo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func (s *Server) handleNutrientsPer200Kcal(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.log.DebugContext(ctx, "handleNutrientsPer200Kcal: Starting tool call",
+		"arguments", request.GetArguments())
+
+	name, err := request.RequireString("name")
+	if err != nil {
+		s.log.WarnContext(ctx, "handleNutrientsPer200Kcal: Missing 'name' parameter", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Missing required parameter 'name': %v", err)), nil
+	}
+
+	if strings.TrimSpace(name) == "" {
+		return mcp.NewToolResultError("Parameter 'name' must be at least 1 character long"), nil
+	}
+
+	opts := s.searchOptions(request)
+
+	s.log.DebugContext(ctx, "MCP nutrients_per_200kcal called",
+		"name", name,
+		"category", opts.Category)
+
+	response, err := s.queryEngine.NutrientsPer200Kcal(ctx, name, opts)
+	if err != nil {
+		s.log.WarnContext(ctx, "Per 200 kcal scaling failed", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Per 200 kcal failed: %v", err)), nil
+	}
+
+	return s.structuredResult(ctx, "handleNutrientsPer200Kcal", response)
+}
@@ -0,0 +1,45 @@
+package mcpgo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func (s *Server) handleExplainFoodSearch(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.log.DebugContext(ctx, "handleExplainFoodSearch: Starting tool call",
+		"arguments", request.GetArguments())
+
+	name, err := request.RequireString("name")
+	if err != nil {
+		s.log.WarnContext(ctx, "handleExplainFoodSearch: Missing 'name' parameter", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Missing required parameter 'name': %v", err)), nil
+	}
+
+	if strings.TrimSpace(name) == "" {
+		return mcp.NewToolResultError("Parameter 'name' must be at least 1 character long"), nil
+	}
+
+	description := request.GetString("description", "")
+	fdcId := request.GetInt("fdcId", 0)
+	if (strings.TrimSpace(description) == "") == (fdcId == 0) {
+		return mcp.NewToolResultError("Exactly one of 'description' or 'fdcId' is required"), nil
+	}
+
+	opts := s.searchOptions(request)
+
+	s.log.DebugContext(ctx, "MCP explain_food_search called",
+		"name", name,
+		"description", description,
+		"fdcId", fdcId)
+
+	response, err := s.queryEngine.ExplainFoodSearch(ctx, name, description, fdcId, opts)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Explain food search failed", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Explain failed: %v", err)), nil
+	}
+
+	return s.structuredResult(ctx, "handleExplainFoodSearch", response)
+}
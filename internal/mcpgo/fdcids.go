@@ -0,0 +1,37 @@
+package mcpgo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// maxFdcIdLookups caps how many foods a single get_foods_by_fdc_ids call may fetch
+const maxFdcIdLookups = 50
+
+func (s *Server) handleGetFoodsByFdcIds(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.log.DebugContext(ctx, "handleGetFoodsByFdcIds: Starting tool call",
+		"arguments", request.GetArguments())
+
+	fdcIds, err := request.RequireIntSlice("fdcIds")
+	if err != nil {
+		s.log.WarnContext(ctx, "handleGetFoodsByFdcIds: Missing 'fdcIds' parameter", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Missing required parameter 'fdcIds': %v", err)), nil
+	}
+
+	if len(fdcIds) == 0 || len(fdcIds) > maxFdcIdLookups {
+		return mcp.NewToolResultError(fmt.Sprintf("Parameter 'fdcIds' must list 1 to %d FDC IDs, got %d", maxFdcIdLookups, len(fdcIds))), nil
+	}
+
+	s.log.DebugContext(ctx, "MCP get_foods_by_fdc_ids called",
+		"fdcIds", fdcIds)
+
+	response, err := s.queryEngine.GetFoodsByFdcIds(ctx, fdcIds)
+	if err != nil {
+		s.log.ErrorContext(ctx, "FDC ID lookup failed", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Lookup failed: %v", err)), nil
+	}
+
+	return s.structuredResult(ctx, "handleGetFoodsByFdcIds", response)
+}
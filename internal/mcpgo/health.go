@@ -0,0 +1,129 @@
+package mcpgo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/query"
+)
+
+// healthProbeTTL is how long a readiness probe result is reused before the probe query runs again
+const healthProbeTTL = 5 * time.Second
+
+// healthProbeLimit caps how many results the health probe query asks for
+const healthProbeLimit = 10
+
+// healthProbeResult is the cached outcome of the last health probe
+type healthProbeResult struct {
+	checkedAt time.Time
+	count     int
+	err       error
+}
+
+// probeHealth checks the engine is loaded and, when a probe query is configured, that it finds at least one food.
+// Results are cached for healthProbeTTL so frequent health checks stay cheap.
+func (s *Server) probeHealth(ctx context.Context) (int, error) {
+	s.healthMu.Lock()
+	defer s.healthMu.Unlock()
+
+	if !s.lastProbe.checkedAt.IsZero() && time.Since(s.lastProbe.checkedAt) < healthProbeTTL {
+		return s.lastProbe.count, s.lastProbe.err
+	}
+
+	count, err := s.runHealthProbe(ctx)
+	s.lastProbe = healthProbeResult{checkedAt: time.Now(), count: count, err: err}
+
+	return count, err
+}
+
+// runHealthProbe runs the engine health check followed by the probe query
+func (s *Server) runHealthProbe(ctx context.Context) (int, error) {
+	if err := s.queryEngine.Health(ctx); err != nil {
+		return 0, err
+	}
+
+	if s.healthProbeQuery == "" {
+		return 0, nil
+	}
+
+	foods, err := s.queryEngine.SearchFoodsByName(ctx, s.healthProbeQuery, healthProbeLimit, query.SearchOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("probe query %q failed: %w", s.healthProbeQuery, err)
+	}
+	if len(foods) == 0 {
+		return 0, fmt.Errorf("probe query %q returned no results", s.healthProbeQuery)
+	}
+
+	return len(foods), nil
+}
+
+// handleHealth is the liveness probe: it reports healthy whenever the server can answer HTTP requests, so
+// orchestrators don't restart a process that is merely not ready. No authentication is required.
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	body := map[string]interface{}{
+		"status": "healthy",
+	}
+	if cache := s.queryEngine.SearchCacheStats(); cache.Capacity > 0 {
+		body["search_cache"] = cache
+	}
+
+	writeProbeResponse(w, r, http.StatusOK, body)
+}
+
+// handleReady is the readiness probe: it reports whether the server can answer searches, returning 503
+// when the engine has no data or the probe query finds nothing. No authentication is required.
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	count, err := s.probeHealth(r.Context())
+
+	body := map[string]interface{}{
+		"status": "ready",
+	}
+	status := http.StatusOK
+	if err != nil {
+		s.log.Warn("Readiness check failed", "error", err)
+		body["status"] = "not_ready"
+		body["error"] = err.Error()
+		status = http.StatusServiceUnavailable
+	}
+	if s.healthProbeQuery != "" {
+		body["probe_query"] = s.healthProbeQuery
+		body["probe_results"] = count
+	}
+
+	writeProbeResponse(w, r, status, body)
+}
+
+// writeProbeResponse writes a probe's status code and JSON body
+func writeProbeResponse(w http.ResponseWriter, r *http.Request, status int, body map[string]interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	// HEAD requests get the same status code without a body for HEAD-based uptime monitors
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	json.NewEncoder(w).Encode(body)
+}
+
+// WithHealthProbeQuery sets the sentinel query the readiness endpoint must find at least one food for.
+// An empty query only checks that data is loaded.
+func WithHealthProbeQuery(probeQuery string) Option {
+	return func(s *Server) {
+		s.healthProbeQuery = strings.TrimSpace(probeQuery)
+	}
+}
@@ -0,0 +1,213 @@
+package mcpgo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// defaultHighestInNutrientResults is how many foods find_foods_highest_in_nutrient returns when no limit is given
+const defaultHighestInNutrientResults = 10
+
+func (s *Server) handleFindFoodsHighestInNutrient(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.log.DebugContext(ctx, "handleFindFoodsHighestInNutrient: Starting tool call",
+		"arguments", request.GetArguments())
+
+	nutrient, err := request.RequireString("nutrient")
+	if err != nil {
+		s.log.WarnContext(ctx, "handleFindFoodsHighestInNutrient: Missing 'nutrient' parameter", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Missing required parameter 'nutrient': %v", err)), nil
+	}
+
+	if strings.TrimSpace(nutrient) == "" {
+		return mcp.NewToolResultError("Parameter 'nutrient' must be at least 1 character long"), nil
+	}
+
+	offset, limit, err := s.aggregatePage(request, defaultHighestInNutrientResults)
+	if err != nil {
+		s.log.WarnContext(ctx, "handleFindFoodsHighestInNutrient: Invalid 'offset' parameter", "error", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	opts := s.searchOptions(request)
+
+	s.log.DebugContext(ctx, "MCP find_foods_highest_in_nutrient called",
+		"nutrient", nutrient,
+		"offset", offset,
+		"limit", limit,
+		"category", opts.Category)
+
+	response, err := s.queryEngine.FindFoodsByNutrient(ctx, nutrient, 0, opts)
+	if err != nil {
+		s.log.WarnContext(ctx, "Nutrient ranking failed", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Ranking failed: %v", err)), nil
+	}
+
+	response.Foods, response.Count, response.Page = aggregateRows(response.Foods, offset, limit)
+
+	return s.structuredResult(ctx, "handleFindFoodsHighestInNutrient", response)
+}
+
+// optionalFloat returns a numeric argument, or nil when the call didn't pass it
+func optionalFloat(request mcp.CallToolRequest, name string) (*float64, error) {
+	if _, ok := request.GetArguments()[name]; !ok {
+		return nil, nil
+	}
+
+	value, err := request.RequireFloat(name)
+	if err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+func (s *Server) handleFindFoodsWithNutrientInRange(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.log.DebugContext(ctx, "handleFindFoodsWithNutrientInRange: Starting tool call",
+		"arguments", request.GetArguments())
+
+	nutrient, err := request.RequireString("nutrient")
+	if err != nil {
+		s.log.WarnContext(ctx, "handleFindFoodsWithNutrientInRange: Missing 'nutrient' parameter", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Missing required parameter 'nutrient': %v", err)), nil
+	}
+
+	if strings.TrimSpace(nutrient) == "" {
+		return mcp.NewToolResultError("Parameter 'nutrient' must be at least 1 character long"), nil
+	}
+
+	minAmount, err := optionalFloat(request, "min")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid parameter 'min': %v", err)), nil
+	}
+	maxAmount, err := optionalFloat(request, "max")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid parameter 'max': %v", err)), nil
+	}
+	if minAmount != nil && maxAmount != nil && *minAmount > *maxAmount {
+		return mcp.NewToolResultError("Parameter 'min' must not be greater than 'max'"), nil
+	}
+
+	offset, limit, err := s.aggregatePage(request, defaultHighestInNutrientResults)
+	if err != nil {
+		s.log.WarnContext(ctx, "handleFindFoodsWithNutrientInRange: Invalid 'offset' parameter", "error", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	opts := s.searchOptions(request)
+
+	s.log.DebugContext(ctx, "MCP find_foods_with_nutrient_in_range called",
+		"nutrient", nutrient,
+		"min", minAmount,
+		"max", maxAmount,
+		"offset", offset,
+		"limit", limit,
+		"category", opts.Category)
+
+	response, err := s.queryEngine.FindFoodsByNutrientRange(ctx, nutrient, minAmount, maxAmount, 0, opts)
+	if err != nil {
+		s.log.WarnContext(ctx, "Nutrient range search failed", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Range search failed: %v", err)), nil
+	}
+
+	response.Foods, response.Count, response.Page = aggregateRows(response.Foods, offset, limit)
+
+	return s.structuredResult(ctx, "handleFindFoodsWithNutrientInRange", response)
+}
+
+func (s *Server) handleMultiNutrientSources(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.log.DebugContext(ctx, "handleMultiNutrientSources: Starting tool call",
+		"arguments", request.GetArguments())
+
+	nutrients, err := request.RequireStringSlice("nutrients")
+	if err != nil {
+		s.log.WarnContext(ctx, "handleMultiNutrientSources: Missing 'nutrients' parameter", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Missing required parameter 'nutrients': %v", err)), nil
+	}
+
+	if len(nutrients) == 0 {
+		return mcp.NewToolResultError("Parameter 'nutrients' must list at least one nutrient"), nil
+	}
+
+	percentile := request.GetFloat("percentile", s.notablePercentile)
+	if percentile < 0 || percentile > 100 {
+		return mcp.NewToolResultError("Parameter 'percentile' must be between 0 and 100"), nil
+	}
+
+	offset, limit, err := s.aggregatePage(request, defaultHighestInNutrientResults)
+	if err != nil {
+		s.log.WarnContext(ctx, "handleMultiNutrientSources: Invalid 'offset' parameter", "error", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	opts := s.searchOptions(request)
+
+	s.log.DebugContext(ctx, "MCP multi_nutrient_sources called",
+		"nutrients", nutrients,
+		"percentile", percentile,
+		"offset", offset,
+		"limit", limit,
+		"category", opts.Category)
+
+	response, err := s.queryEngine.MultiNutrientSources(ctx, nutrients, percentile, 0, opts)
+	if err != nil {
+		s.log.WarnContext(ctx, "Multi-nutrient search failed", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Multi-nutrient search failed: %v", err)), nil
+	}
+
+	response.Foods, response.Count, response.Page = aggregateRows(response.Foods, offset, limit)
+
+	return s.structuredResult(ctx, "handleMultiNutrientSources", response)
+}
+
+func (s *Server) handleRankFoodsByNutrients(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.log.DebugContext(ctx, "handleRankFoodsByNutrients: Starting tool call",
+		"arguments", request.GetArguments())
+
+	nutrients, err := request.RequireStringSlice("nutrients")
+	if err != nil {
+		s.log.WarnContext(ctx, "handleRankFoodsByNutrients: Missing 'nutrients' parameter", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Missing required parameter 'nutrients': %v", err)), nil
+	}
+
+	if len(nutrients) == 0 {
+		return mcp.NewToolResultError("Parameter 'nutrients' must list at least one nutrient"), nil
+	}
+
+	var weights []float64
+	if _, ok := request.GetArguments()["weights"]; ok {
+		weights, err = request.RequireFloatSlice("weights")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid parameter 'weights': %v", err)), nil
+		}
+		if len(weights) != len(nutrients) {
+			return mcp.NewToolResultError(fmt.Sprintf("Parameter 'weights' must have one weight per nutrient (got %d weights for %d nutrients)", len(weights), len(nutrients))), nil
+		}
+	}
+
+	offset, limit, err := s.aggregatePage(request, defaultHighestInNutrientResults)
+	if err != nil {
+		s.log.WarnContext(ctx, "handleRankFoodsByNutrients: Invalid 'offset' parameter", "error", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	opts := s.searchOptions(request)
+
+	s.log.DebugContext(ctx, "MCP rank_foods_by_nutrients called",
+		"nutrients", nutrients,
+		"weights", weights,
+		"offset", offset,
+		"limit", limit,
+		"category", opts.Category)
+
+	response, err := s.queryEngine.RankFoodsByNutrients(ctx, nutrients, weights, 0, opts)
+	if err != nil {
+		s.log.WarnContext(ctx, "Composite nutrient ranking failed", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Ranking failed: %v", err)), nil
+	}
+
+	response.Foods, response.Count, response.Page = aggregateRows(response.Foods, offset, limit)
+
+	return s.structuredResult(ctx, "handleRankFoodsByNutrients", response)
+}
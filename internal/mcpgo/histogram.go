@@ -0,0 +1,52 @@
+package mcpgo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// defaultHistogramBuckets is how many buckets nutrient_histogram uses when none are given
+	defaultHistogramBuckets = 10
+
+	// maxHistogramBuckets caps the bucket count of a single nutrient_histogram call
+	maxHistogramBuckets = 50
+)
+
+func (s *Server) handleNutrientHistogram(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.log.DebugContext(ctx, "handleNutrientHistogram: Starting tool call",
+		"arguments", request.GetArguments())
+
+	nutrient, err := request.RequireString("nutrient")
+	if err != nil {
+		s.log.WarnContext(ctx, "handleNutrientHistogram: Missing 'nutrient' parameter", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Missing required parameter 'nutrient': %v", err)), nil
+	}
+
+	if strings.TrimSpace(nutrient) == "" {
+		return mcp.NewToolResultError("Parameter 'nutrient' must be at least 1 character long"), nil
+	}
+
+	buckets := request.GetInt("buckets", defaultHistogramBuckets)
+	if buckets < 1 || buckets > maxHistogramBuckets {
+		return mcp.NewToolResultError(fmt.Sprintf("Parameter 'buckets' must be between 1 and %d", maxHistogramBuckets)), nil
+	}
+
+	opts := s.searchOptions(request)
+
+	s.log.DebugContext(ctx, "MCP nutrient_histogram called",
+		"nutrient", nutrient,
+		"buckets", buckets,
+		"category", opts.Category)
+
+	response, err := s.queryEngine.NutrientHistogram(ctx, nutrient, buckets, opts)
+	if err != nil {
+		s.log.WarnContext(ctx, "Nutrient histogram failed", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Histogram failed: %v", err)), nil
+	}
+
+	return s.structuredResult(ctx, "handleNutrientHistogram", response)
+}
@@ -0,0 +1,42 @@
+package mcpgo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func (s *Server) handleNutrientHistory(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.log.DebugContext(ctx, "handleNutrientHistory: Starting tool call",
+		"arguments", request.GetArguments())
+
+	fdcId, err := request.RequireInt("fdcId")
+	if err != nil {
+		s.log.WarnContext(ctx, "handleNutrientHistory: Missing 'fdcId' parameter", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Missing required parameter 'fdcId': %v", err)), nil
+	}
+
+	nutrient, err := request.RequireString("nutrient")
+	if err != nil {
+		s.log.WarnContext(ctx, "handleNutrientHistory: Missing 'nutrient' parameter", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Missing required parameter 'nutrient': %v", err)), nil
+	}
+
+	if strings.TrimSpace(nutrient) == "" {
+		return mcp.NewToolResultError("Parameter 'nutrient' must be at least 1 character long"), nil
+	}
+
+	s.log.DebugContext(ctx, "MCP nutrient_history called",
+		"fdcId", fdcId,
+		"nutrient", nutrient)
+
+	response, err := s.queryEngine.NutrientHistory(ctx, fdcId, nutrient)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Nutrient history failed", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Nutrient history failed: %v", err)), nil
+	}
+
+	return s.structuredResult(ctx, "handleNutrientHistory", response)
+}
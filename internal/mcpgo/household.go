@@ -0,0 +1,42 @@
+package mcpgo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func (s *Server) handleNutrientsForHouseholdPortion(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.log.DebugContext(ctx, "handleNutrientsForHouseholdPortion: Starting tool call",
+		"arguments", request.GetArguments())
+
+	fdcId, err := request.RequireInt("fdcId")
+	if err != nil {
+		s.log.WarnContext(ctx, "handleNutrientsForHouseholdPortion: Missing 'fdcId' parameter", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Missing required parameter 'fdcId': %v", err)), nil
+	}
+
+	measure, err := request.RequireString("measure")
+	if err != nil {
+		s.log.WarnContext(ctx, "handleNutrientsForHouseholdPortion: Missing 'measure' parameter", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Missing required parameter 'measure': %v", err)), nil
+	}
+
+	if strings.TrimSpace(measure) == "" {
+		return mcp.NewToolResultError("Parameter 'measure' must be at least 1 character long"), nil
+	}
+
+	s.log.DebugContext(ctx, "MCP nutrients_for_household_portion called",
+		"fdcId", fdcId,
+		"measure", measure)
+
+	response, err := s.queryEngine.NutrientsForHouseholdPortion(ctx, fdcId, measure)
+	if err != nil {
+		s.log.WarnContext(ctx, "Household portion scaling failed", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Household portion failed: %v", err)), nil
+	}
+
+	return s.structuredResult(ctx, "handleNutrientsForHouseholdPortion", response)
+}
@@ -0,0 +1,46 @@
+package mcpgo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func (s *Server) handleFindFoodsContainingIngredient(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.log.DebugContext(ctx, "handleFindFoodsContainingIngredient: Starting tool call",
+		"arguments", request.GetArguments())
+
+	ingredient, err := request.RequireString("ingredient")
+	if err != nil {
+		s.log.WarnContext(ctx, "handleFindFoodsContainingIngredient: Missing 'ingredient' parameter", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Missing required parameter 'ingredient': %v", err)), nil
+	}
+
+	if strings.TrimSpace(ingredient) == "" {
+		return mcp.NewToolResultError("Parameter 'ingredient' must be at least 1 character long"), nil
+	}
+
+	offset, limit, err := s.aggregatePage(request, 0)
+	if err != nil {
+		s.log.WarnContext(ctx, "handleFindFoodsContainingIngredient: Invalid 'offset' parameter", "error", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	s.log.DebugContext(ctx, "MCP find_foods_containing_ingredient called",
+		"ingredient", ingredient,
+		"offset", offset,
+		"limit", limit)
+
+	response, err := s.queryEngine.FindFoodsContainingIngredient(ctx, ingredient, 0, s.searchOptions(request))
+	if err != nil {
+		s.log.ErrorContext(ctx, "Ingredient search failed", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Ingredient search failed: %v", err)), nil
+	}
+
+	response.Foods, response.Count, response.Page = aggregateRows(response.Foods, offset, limit)
+	response.Found = s.found(response.Page.Total)
+
+	return s.structuredResult(ctx, "handleFindFoodsContainingIngredient", response)
+}
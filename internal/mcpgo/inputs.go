@@ -0,0 +1,62 @@
+package mcpgo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/query"
+)
+
+// defaultInputDepth is how many levels of input foods get_food_with_inputs expands when no depth is given
+const defaultInputDepth = 1
+
+func (s *Server) handleGetFoodWithInputs(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.log.DebugContext(ctx, "handleGetFoodWithInputs: Starting tool call",
+		"arguments", request.GetArguments())
+
+	fdcId, err := request.RequireInt("fdcId")
+	if err != nil {
+		s.log.WarnContext(ctx, "handleGetFoodWithInputs: Missing 'fdcId' parameter", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Missing required parameter 'fdcId': %v", err)), nil
+	}
+
+	depth := request.GetInt("depth", defaultInputDepth)
+	if depth < 1 || depth > query.MaxInputDepth {
+		return mcp.NewToolResultError(fmt.Sprintf("Parameter 'depth' must be between 1 and %d", query.MaxInputDepth)), nil
+	}
+
+	s.log.DebugContext(ctx, "MCP get_food_with_inputs called",
+		"fdcId", fdcId,
+		"depth", depth)
+
+	response, err := s.queryEngine.GetFoodWithInputs(ctx, fdcId, depth)
+	if err != nil {
+		s.log.WarnContext(ctx, "Input food expansion failed", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Lookup failed: %v", err)), nil
+	}
+
+	return s.structuredResult(ctx, "handleGetFoodWithInputs", response)
+}
+
+func (s *Server) handleGetFoodInputFoods(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.log.DebugContext(ctx, "handleGetFoodInputFoods: Starting tool call",
+		"arguments", request.GetArguments())
+
+	fdcId, err := request.RequireInt("fdcId")
+	if err != nil {
+		s.log.WarnContext(ctx, "handleGetFoodInputFoods: Missing 'fdcId' parameter", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Missing required parameter 'fdcId': %v", err)), nil
+	}
+
+	s.log.DebugContext(ctx, "MCP get_food_input_foods called",
+		"fdcId", fdcId)
+
+	response, err := s.queryEngine.GetFoodInputFoods(ctx, fdcId)
+	if err != nil {
+		s.log.WarnContext(ctx, "Input food lookup failed", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Lookup failed: %v", err)), nil
+	}
+
+	return s.structuredResult(ctx, "handleGetFoodInputFoods", response)
+}
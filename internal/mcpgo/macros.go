@@ -0,0 +1,38 @@
+package mcpgo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func (s *Server) handleMacroPercentages(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.log.DebugContext(ctx, "handleMacroPercentages: Starting tool call",
+		"arguments", request.GetArguments())
+
+	name, err := request.RequireString("name")
+	if err != nil {
+		s.log.WarnContext(ctx, "handleMacroPercentages: Missing 'name' parameter", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Missing required parameter 'name': %v", err)), nil
+	}
+
+	if strings.TrimSpace(name) == "" {
+		return mcp.NewToolResultError("Parameter 'name' must be at least 1 character long"), nil
+	}
+
+	opts := s.searchOptions(request)
+
+	s.log.DebugContext(ctx, "MCP macro_percentages called",
+		"name", name,
+		"category", opts.Category)
+
+	response, err := s.queryEngine.MacroPercentages(ctx, name, opts)
+	if err != nil {
+		s.log.WarnContext(ctx, "Macro percentages failed", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Macro percentages failed: %v", err)), nil
+	}
+
+	return s.structuredResult(ctx, "handleMacroPercentages", response)
+}
@@ -0,0 +1,54 @@
+package mcpgo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/query"
+)
+
+const (
+	// formatJSON returns the JSON-encoded response as the tool result text (default)
+	formatJSON = "json"
+	// formatMarkdown returns the response as GitHub-flavored markdown tables in the tool result text
+	formatMarkdown = "markdown"
+	// formatCSV returns the nutrient data as CSV rows in the tool result text
+	formatCSV = "csv"
+)
+
+// renderSimplifiedMarkdown renders a simplified nutrient response as one GitHub-flavored markdown table per food
+func renderSimplifiedMarkdown(response *query.SimplifiedNutrientResponse) string {
+	if response == nil || len(response.Foods) == 0 {
+		return "No matching foods found."
+	}
+
+	var b strings.Builder
+	for i, food := range response.Foods {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+
+		if food.Basis != "" {
+			fmt.Fprintf(&b, "### %s (per %s)\n\n", escapeMarkdownCell(food.Name), escapeMarkdownCell(food.Basis))
+		} else {
+			fmt.Fprintf(&b, "### %s\n\n", escapeMarkdownCell(food.Name))
+		}
+		b.WriteString("| Nutrient | Amount | Unit |\n")
+		b.WriteString("| --- | ---: | --- |\n")
+		for _, nutrient := range food.Nutrients {
+			fmt.Fprintf(&b, "| %s | %s | %s |\n",
+				escapeMarkdownCell(nutrient.Name),
+				strconv.FormatFloat(nutrient.Amount, 'f', -1, 64),
+				escapeMarkdownCell(nutrient.Unit))
+		}
+	}
+
+	return b.String()
+}
+
+// escapeMarkdownCell escapes characters that would break a markdown table cell
+func escapeMarkdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
@@ -0,0 +1,102 @@
+package mcpgo
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/auth"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderSimplifiedMarkdown(t *testing.T) {
+	t.Run("renders a table per food", func(t *testing.T) {
+		response := &query.SimplifiedNutrientResponse{
+			Found: true,
+			Count: 1,
+			Foods: []query.SimplifiedFood{
+				{
+					Name: "Milk, whole",
+					Nutrients: []query.SimplifiedNutrient{
+						{Name: "Protein", Unit: "g", Amount: 3.27},
+						{Name: "Odd | name", Unit: "mg", Amount: 120},
+					},
+				},
+			},
+		}
+
+		markdown := renderSimplifiedMarkdown(response)
+		lines := strings.Split(markdown, "\n")
+
+		assert.Equal(t, "### Milk, whole", lines[0])
+		assert.Equal(t, "| Nutrient | Amount | Unit |", lines[2])
+		assert.Equal(t, "| --- | ---: | --- |", lines[3])
+		assert.Equal(t, "| Protein | 3.27 | g |", lines[4])
+		assert.Equal(t, `| Odd \| name | 120 | mg |`, lines[5])
+	})
+
+	t.Run("reports empty results", func(t *testing.T) {
+		markdown := renderSimplifiedMarkdown(&query.SimplifiedNutrientResponse{})
+
+		assert.Equal(t, "No matching foods found.", markdown)
+	})
+}
+
+func TestServer_SimplifiedMarkdownFormat(t *testing.T) {
+	logger := config.NewTestLogger(io.Discard, "debug")
+	mockEngine := &testQueryEngine{
+		data: &query.FoundationFoodsData{},
+		simplified: &query.SimplifiedNutrientResponse{
+			Found: true,
+			Count: 1,
+			Foods: []query.SimplifiedFood{
+				{
+					Name:      "Milk, whole",
+					FdcId:     1,
+					Nutrients: []query.SimplifiedNutrient{{Name: "Protein", Unit: "g", Amount: 3.27}},
+				},
+			},
+		},
+	}
+	server := NewServer(mockEngine, auth.NewBearerTokenAuth("test-token"), logger)
+
+	resultText := func(t *testing.T, result *mcp.CallToolResult) string {
+		t.Helper()
+		require.False(t, result.IsError)
+		require.Len(t, result.Content, 1)
+		text, ok := result.Content[0].(mcp.TextContent)
+		require.True(t, ok)
+		return text.Text
+	}
+
+	for _, tool := range []string{"search_foundation_foods_and_return_nutrients", "search_foundation_foods_and_return_nutrients_simplified"} {
+		t.Run(tool, func(t *testing.T) {
+			t.Run("renders markdown text and keeps structured JSON", func(t *testing.T) {
+				result := callTool(t, server, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"`+tool+`","arguments":{"name":"milk","format":"markdown"}}}`)
+
+				text := resultText(t, result)
+				assert.True(t, strings.HasPrefix(text, "### Milk, whole\n"))
+				assert.Contains(t, text, "| Protein | 3.27 | g |")
+
+				encoded, err := json.Marshal(result.StructuredContent)
+				require.NoError(t, err)
+				var structured query.SimplifiedNutrientResponse
+				require.NoError(t, json.Unmarshal(encoded, &structured))
+				assert.Equal(t, mockEngine.simplified.Foods, structured.Foods)
+			})
+
+			t.Run("defaults to JSON text", func(t *testing.T) {
+				result := callTool(t, server, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"`+tool+`","arguments":{"name":"milk"}}}`)
+
+				var decoded query.SimplifiedNutrientResponse
+				require.NoError(t, json.Unmarshal([]byte(resultText(t, result)), &decoded))
+				assert.Equal(t, mockEngine.simplified.Foods, decoded.Foods)
+			})
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package mcpgo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/query"
+)
+
+// defaultNamesOnlyResults and maxNamesOnlyResults bound search_foundation_foods_names_only, which can
+// afford a longer list than the full searches since each match is only a few fields
+const (
+	defaultNamesOnlyResults = 10
+	maxNamesOnlyResults     = 50
+)
+
+// FoodNameMatch is the lightweight projection of a food returned by search_foundation_foods_names_only
+type FoodNameMatch struct {
+	FdcId        int    `json:"fdcId"`
+	Description  string `json:"description"`
+	FoodCategory string `json:"foodCategory"`
+}
+
+// NamesOnlySearchResponse represents the response from search_foundation_foods_names_only
+type NamesOnlySearchResponse struct {
+	Found bool            `json:"found"`
+	Count int             `json:"count"`
+	Foods []FoodNameMatch `json:"foods"`
+
+	PartialDueToBudget bool `json:"partialDueToBudget,omitempty"`
+}
+
+// foodNameMatches projects foods down to their FDC ID, description and category
+func foodNameMatches(foods []query.FoundationFood) []FoodNameMatch {
+	matches := make([]FoodNameMatch, 0, len(foods))
+	for _, food := range foods {
+		matches = append(matches, FoodNameMatch{
+			FdcId:        food.FdcId,
+			Description:  food.Description,
+			FoodCategory: food.FoodCategory.Description,
+		})
+	}
+	return matches
+}
+
+func (s *Server) handleNamesOnlyFoodSearch(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.log.DebugContext(ctx, "handleNamesOnlyFoodSearch: Starting tool call",
+		"arguments", request.GetArguments())
+
+	name, err := request.RequireString("name")
+	if err != nil {
+		s.log.WarnContext(ctx, "handleNamesOnlyFoodSearch: Missing 'name' parameter", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Missing required parameter 'name': %v", err)), nil
+	}
+
+	if len(name) < 1 {
+		return mcp.NewToolResultError("Parameter 'name' must be at least 1 character long"), nil
+	}
+
+	limit := request.GetInt("limit", defaultNamesOnlyResults)
+	if limit <= 0 {
+		limit = defaultNamesOnlyResults
+	}
+	if limit > maxNamesOnlyResults {
+		limit = maxNamesOnlyResults
+	}
+
+	opts := s.searchOptions(request)
+
+	s.log.DebugContext(ctx, "MCP search_foundation_foods_names_only called",
+		"name", name,
+		"limit", limit,
+		"category", opts.Category)
+
+	ctx, partial := s.budgetContext(ctx)
+	foods, err := s.queryEngine.SearchFoodsByName(ctx, name, limit, opts)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Food search failed", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Search failed: %v", err)), nil
+	}
+
+	matches := foodNameMatches(foods)
+	return s.structuredResult(ctx, "handleNamesOnlyFoodSearch", NamesOnlySearchResponse{
+		Found: s.found(len(matches)),
+		Count: len(matches),
+		Foods: matches,
+
+		PartialDueToBudget: partial(),
+	})
+}
@@ -0,0 +1,30 @@
+package mcpgo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func (s *Server) handleGetFoodsByNdbNumber(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.log.DebugContext(ctx, "handleGetFoodsByNdbNumber: Starting tool call",
+		"arguments", request.GetArguments())
+
+	ndbNumber, err := request.RequireInt("ndbNumber")
+	if err != nil {
+		s.log.WarnContext(ctx, "handleGetFoodsByNdbNumber: Missing 'ndbNumber' parameter", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Missing required parameter 'ndbNumber': %v", err)), nil
+	}
+
+	s.log.DebugContext(ctx, "MCP get_foods_by_ndb_number called",
+		"ndbNumber", ndbNumber)
+
+	response, err := s.queryEngine.GetFoodByNdbNumber(ctx, ndbNumber)
+	if err != nil {
+		s.log.WarnContext(ctx, "NDB number lookup failed", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Lookup failed: %v", err)), nil
+	}
+
+	return s.structuredResult(ctx, "handleGetFoodsByNdbNumber", response)
+}
@@ -0,0 +1,35 @@
+package mcpgo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func (s *Server) handleNetCarbs(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.log.DebugContext(ctx, "handleNetCarbs: Starting tool call",
+		"arguments", request.GetArguments())
+
+	fdcId, err := request.RequireInt("fdcId")
+	if err != nil {
+		s.log.WarnContext(ctx, "handleNetCarbs: Missing 'fdcId' parameter", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Missing required parameter 'fdcId': %v", err)), nil
+	}
+
+	measure := request.GetString("measure", "")
+	subtractSugarAlcohols := request.GetBool("subtract_sugar_alcohols", true)
+
+	s.log.DebugContext(ctx, "MCP net_carbs called",
+		"fdcId", fdcId,
+		"measure", measure,
+		"subtract_sugar_alcohols", subtractSugarAlcohols)
+
+	response, err := s.queryEngine.NetCarbs(ctx, fdcId, measure, subtractSugarAlcohols)
+	if err != nil {
+		s.log.WarnContext(ctx, "Net carbs calculation failed", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Net carbs failed: %v", err)), nil
+	}
+
+	return s.structuredResult(ctx, "handleNetCarbs", response)
+}
@@ -0,0 +1,86 @@
+package mcpgo
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/query"
+)
+
+// ProjectedSearchProductsResponse is the search response when the caller restricts the food fields returned
+type ProjectedSearchProductsResponse struct {
+	Found         bool             `json:"found"`
+	Count         int              `json:"count"`
+	Products      []map[string]any `json:"products"`
+	Total         int              `json:"total"`
+	UnknownFields []string         `json:"unknownFields,omitempty"`
+	NextCursor    string           `json:"nextCursor,omitempty"`
+
+	PartialDueToBudget bool `json:"partialDueToBudget,omitempty"`
+}
+
+// foodFieldNames lists the top-level JSON field names of a FoundationFood
+var foodFieldNames = jsonFieldNames(reflect.TypeOf(query.FoundationFood{}))
+
+// jsonFieldNames returns the JSON names of a struct's exported fields
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = field.Name
+		}
+		names[name] = true
+	}
+	return names
+}
+
+// splitFoodFields separates requested field names into known top-level food fields and unknown ones
+func splitFoodFields(fields []string) (known []string, unknown []string) {
+	for _, field := range fields {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if foodFieldNames[field] {
+			known = append(known, field)
+		} else {
+			unknown = append(unknown, field)
+		}
+	}
+	return known, unknown
+}
+
+// projectFoods reduces each food to only the given top-level JSON fields
+func projectFoods(foods []query.FoundationFood, fields []string) ([]map[string]any, error) {
+	projected := make([]map[string]any, 0, len(foods))
+	for _, food := range foods {
+		data, err := json.Marshal(food)
+		if err != nil {
+			return nil, err
+		}
+
+		var full map[string]any
+		if err := json.Unmarshal(data, &full); err != nil {
+			return nil, err
+		}
+
+		item := make(map[string]any, len(fields))
+		for _, field := range fields {
+			if value, ok := full[field]; ok {
+				item[field] = value
+			}
+		}
+		projected = append(projected, item)
+	}
+	return projected, nil
+}
@@ -0,0 +1,50 @@
+package mcpgo
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/auth"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_ResponseFieldProjection(t *testing.T) {
+	logger := config.NewTestLogger(io.Discard, "debug")
+	mockEngine := &testQueryEngine{data: &query.FoundationFoodsData{
+		FoundationFoods: []query.FoundationFood{
+			{
+				Description:  "Milk, whole",
+				FdcId:        1,
+				NdbNumber:    1077,
+				FoodCategory: query.FoodCategory{Description: "Dairy and Egg Products"},
+			},
+		},
+	}}
+	server := NewServer(mockEngine, auth.NewBearerTokenAuth("test-token"), logger)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]any{
+		"name":            "milk",
+		"response_fields": []any{"description", "fdcId", "notAField"},
+	}
+
+	result, err := server.handleFoodSearch(context.Background(), request)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	response, ok := result.StructuredContent.(ProjectedSearchProductsResponse)
+	require.True(t, ok)
+	require.Len(t, response.Products, 1)
+
+	product := response.Products[0]
+	assert.Len(t, product, 2)
+	assert.Equal(t, "Milk, whole", product["description"])
+	assert.Equal(t, float64(1), product["fdcId"])
+	assert.NotContains(t, product, "foodCategory")
+	assert.Equal(t, []string{"notAField"}, response.UnknownFields)
+}
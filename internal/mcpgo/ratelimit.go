@@ -0,0 +1,116 @@
+package mcpgo
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// rateLimitSweepInterval is how often idle client buckets are dropped so the limiter doesn't grow unbounded
+const rateLimitSweepInterval = time.Minute
+
+// WithRateLimit limits each client of the /mcp endpoint to rps requests per second with bursts of up to
+// burst requests. Clients are keyed by their bearer token, or by remote IP when they aren't authorized.
+// A non-positive rps disables limiting; a non-positive burst allows one second's worth of requests.
+func WithRateLimit(rps float64, burst int) Option {
+	return func(s *Server) {
+		if rps <= 0 {
+			s.rateLimiter = nil
+			return
+		}
+		if burst <= 0 {
+			burst = int(math.Max(1, math.Ceil(rps)))
+		}
+		s.rateLimiter = newRateLimiter(rps, burst, time.Now)
+	}
+}
+
+// tokenBucket holds the tokens one client has left and when they were last refilled
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rateLimiter is a per-client token bucket limiter. It is safe for concurrent use.
+type rateLimiter struct {
+	mu        sync.Mutex
+	rate      float64
+	burst     float64
+	now       func() time.Time
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+// newRateLimiter returns a limiter refilling rate tokens per second up to burst tokens per client
+func newRateLimiter(rate float64, burst int, now func() time.Time) *rateLimiter {
+	return &rateLimiter{
+		rate:      rate,
+		burst:     float64(burst),
+		now:       now,
+		buckets:   make(map[string]*tokenBucket),
+		lastSweep: now(),
+	}
+}
+
+// allow takes a token from the client's bucket. When the bucket is empty it returns false and how long
+// the client has to wait for the next token.
+func (l *rateLimiter) allow(client string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	bucket, ok := l.buckets[client]
+	if !ok {
+		bucket = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[client] = bucket
+	}
+	bucket.tokens = math.Min(l.burst, bucket.tokens+now.Sub(bucket.last).Seconds()*l.rate)
+	bucket.last = now
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return true, 0
+	}
+
+	wait := time.Duration((1 - bucket.tokens) / l.rate * float64(time.Second))
+	return false, wait
+}
+
+// sweep drops buckets that have refilled completely, since a fresh bucket behaves the same.
+// Callers must hold l.mu.
+func (l *rateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < rateLimitSweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	for client, bucket := range l.buckets {
+		if bucket.tokens+now.Sub(bucket.last).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, client)
+		}
+	}
+}
+
+// rateLimitKey identifies the client of a request: its bearer token when authorized, otherwise its remote IP.
+// Unauthorized requests are keyed by IP so made-up tokens can't be used to get fresh buckets.
+func (s *Server) rateLimitKey(r *http.Request) string {
+	if s.auth.IsAuthorized(r) {
+		return "token:" + s.auth.Token(r)
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// retryAfterSeconds formats a wait as the whole seconds of a Retry-After header, rounding up to at least 1
+func retryAfterSeconds(wait time.Duration) string {
+	return strconv.Itoa(int(math.Max(1, math.Ceil(wait.Seconds()))))
+}
@@ -0,0 +1,106 @@
+package mcpgo
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/auth"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_RateLimit(t *testing.T) {
+	toolCall := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"search_foundation_foods_by_name","arguments":{"name":"milk"}}}`
+
+	newHandler := func(opts ...Option) http.Handler {
+		mockEngine := &testQueryEngine{data: &query.FoundationFoodsData{
+			FoundationFoods: []query.FoundationFood{{Description: "Milk, whole", FdcId: 1}},
+		}}
+		logger := config.NewTestLogger(io.Discard, "debug")
+		return NewServer(mockEngine, auth.NewBearerTokenAuth("test-token"), logger, opts...).Handler()
+	}
+
+	post := func(handler http.Handler, token, remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(toolCall))
+		req.RemoteAddr = remoteAddr
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("rejects requests beyond the burst with 429 and Retry-After", func(t *testing.T) {
+		handler := newHandler(WithRateLimit(1, 3))
+
+		var ok, limited int
+		for range 10 {
+			rec := post(handler, "test-token", "192.0.2.1:1234")
+			switch rec.Code {
+			case http.StatusOK:
+				ok++
+			case http.StatusTooManyRequests:
+				limited++
+				assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+			default:
+				t.Fatalf("unexpected status %d", rec.Code)
+			}
+		}
+
+		assert.Equal(t, 3, ok)
+		assert.Equal(t, 7, limited)
+	})
+
+	t.Run("limits anonymous clients by remote IP", func(t *testing.T) {
+		handler := newHandler(WithRateLimit(1, 1))
+
+		assert.Equal(t, http.StatusUnauthorized, post(handler, "", "192.0.2.1:1234").Code)
+		assert.Equal(t, http.StatusTooManyRequests, post(handler, "made-up-token", "192.0.2.1:5678").Code)
+		assert.Equal(t, http.StatusUnauthorized, post(handler, "", "192.0.2.2:1234").Code)
+		assert.Equal(t, http.StatusOK, post(handler, "test-token", "192.0.2.1:1234").Code)
+	})
+
+	t.Run("does not limit when disabled", func(t *testing.T) {
+		handler := newHandler(WithRateLimit(0, 1))
+
+		for range 10 {
+			require.Equal(t, http.StatusOK, post(handler, "test-token", "192.0.2.1:1234").Code)
+		}
+	})
+}
+
+func TestRateLimiter_Refill(t *testing.T) {
+	now := time.Unix(0, 0)
+	limiter := newRateLimiter(2, 2, func() time.Time { return now })
+
+	allowed, _ := limiter.allow("a")
+	assert.True(t, allowed)
+	allowed, _ = limiter.allow("a")
+	assert.True(t, allowed)
+
+	allowed, wait := limiter.allow("a")
+	assert.False(t, allowed)
+	assert.Equal(t, 500*time.Millisecond, wait)
+
+	// Another client has its own bucket
+	allowed, _ = limiter.allow("b")
+	assert.True(t, allowed)
+
+	now = now.Add(500 * time.Millisecond)
+	allowed, _ = limiter.allow("a")
+	assert.True(t, allowed)
+
+	// Buckets that refilled completely are swept after the sweep interval
+	now = now.Add(rateLimitSweepInterval)
+	limiter.allow("c")
+	assert.Len(t, limiter.buckets, 1)
+}
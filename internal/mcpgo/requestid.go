@@ -0,0 +1,20 @@
+package mcpgo
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+)
+
+// requestIDToolHandler wraps a tool handler so every call carries a request ID in its context. HTTP calls
+// already have one from the /mcp handler; stdio calls get a fresh one.
+func requestIDToolHandler(handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if config.RequestIDFromContext(ctx) == "" {
+			ctx = config.WithRequestID(ctx, config.NewRequestID())
+		}
+		return handler(ctx, request)
+	}
+}
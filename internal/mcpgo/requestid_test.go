@@ -0,0 +1,68 @@
+package mcpgo
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/auth"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_RequestID(t *testing.T) {
+	toolCall := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"search_foundation_foods_by_name","arguments":{"name":"milk"}}}`
+
+	newHandler := func(logs *bytes.Buffer) http.Handler {
+		mockEngine := &testQueryEngine{data: &query.FoundationFoodsData{
+			FoundationFoods: []query.FoundationFood{{Description: "Milk, whole", FdcId: 1}},
+		}}
+		return NewServer(mockEngine, auth.NewBearerTokenAuth("test-token"), config.NewTestLogger(logs, "debug")).Handler()
+	}
+
+	post := func(handler http.Handler, requestID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(toolCall))
+		req.Header.Set("Authorization", "Bearer test-token")
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		if requestID != "" {
+			req.Header.Set("X-Request-ID", requestID)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("echoes the client's request ID and logs it on the tool call", func(t *testing.T) {
+		logs := new(bytes.Buffer)
+
+		rec := post(newHandler(logs), "trace-123")
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "trace-123", rec.Header().Get("X-Request-ID"))
+
+		var handlerLines int
+		for _, line := range strings.Split(logs.String(), "\n") {
+			if strings.Contains(line, "handleFoodSearch") {
+				handlerLines++
+				assert.Contains(t, line, "request_id=trace-123")
+			}
+		}
+		assert.Positive(t, handlerLines)
+	})
+
+	t.Run("generates a request ID when the client sends none or an invalid one", func(t *testing.T) {
+		handler := newHandler(new(bytes.Buffer))
+
+		generated := post(handler, "").Header().Get("X-Request-ID")
+		assert.Len(t, generated, 16)
+
+		replaced := post(handler, "bad id\nwith newline").Header().Get("X-Request-ID")
+		assert.Len(t, replaced, 16)
+		assert.NotEqual(t, generated, replaced)
+	})
+}
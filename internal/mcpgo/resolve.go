@@ -0,0 +1,74 @@
+package mcpgo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// maxResolveNames caps how many names a single resolve_foods call may resolve
+const maxResolveNames = 50
+
+func (s *Server) handleResolveFoods(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.log.DebugContext(ctx, "handleResolveFoods: Starting tool call",
+		"arguments", request.GetArguments())
+
+	names, err := request.RequireStringSlice("names")
+	if err != nil {
+		s.log.WarnContext(ctx, "handleResolveFoods: Missing 'names' parameter", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Missing required parameter 'names': %v", err)), nil
+	}
+
+	if len(names) == 0 {
+		return mcp.NewToolResultError("Parameter 'names' must contain at least one name"), nil
+	}
+	if len(names) > maxResolveNames {
+		return mcp.NewToolResultError(fmt.Sprintf("Parameter 'names' may contain at most %d names, got %d", maxResolveNames, len(names))), nil
+	}
+	for i, name := range names {
+		if strings.TrimSpace(name) == "" {
+			return mcp.NewToolResultError(fmt.Sprintf("Parameter 'names' contains an empty name at index %d", i)), nil
+		}
+	}
+
+	s.log.DebugContext(ctx, "MCP resolve_foods called", "name_count", len(names))
+
+	response, err := s.queryEngine.ResolveFoods(ctx, names, s.searchOptions(request))
+	if err != nil {
+		s.log.ErrorContext(ctx, "Resolve foods failed", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Resolve failed: %v", err)), nil
+	}
+
+	return s.structuredResult(ctx, "handleResolveFoods", response)
+}
+
+func (s *Server) handleCanonicalizeFoodName(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.log.DebugContext(ctx, "handleCanonicalizeFoodName: Starting tool call",
+		"arguments", request.GetArguments())
+
+	name, err := request.RequireString("name")
+	if err != nil {
+		s.log.WarnContext(ctx, "handleCanonicalizeFoodName: Missing 'name' parameter", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Missing required parameter 'name': %v", err)), nil
+	}
+
+	if strings.TrimSpace(name) == "" {
+		return mcp.NewToolResultError("Parameter 'name' must be at least 1 character long"), nil
+	}
+
+	minConfidence := request.GetFloat("min_confidence", s.canonicalMinConfidence)
+
+	s.log.DebugContext(ctx, "MCP canonicalize_food_name called",
+		"name", name,
+		"min_confidence", minConfidence)
+
+	response, err := s.queryEngine.CanonicalizeFoodName(ctx, name, minConfidence, s.searchOptions(request))
+	if err != nil {
+		s.log.ErrorContext(ctx, "Canonicalize food name failed", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Canonicalize failed: %v", err)), nil
+	}
+
+	return s.structuredResult(ctx, "handleCanonicalizeFoodName", response)
+}
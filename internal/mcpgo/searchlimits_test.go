@@ -0,0 +1,110 @@
+package mcpgo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/auth"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_SearchLimits(t *testing.T) {
+	logger := config.NewTestLogger(io.Discard, "debug")
+	foods := make([]query.FoundationFood, 0, 20)
+	for i := range 20 {
+		foods = append(foods, query.FoundationFood{Description: fmt.Sprintf("Milk %d", i), FdcId: i + 1})
+	}
+	mockEngine := &testQueryEngine{data: &query.FoundationFoodsData{FoundationFoods: foods}}
+
+	search := func(t *testing.T, server *Server, args map[string]any) int {
+		t.Helper()
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+
+		result, err := server.handleFoodSearch(context.Background(), request)
+		require.NoError(t, err)
+		require.False(t, result.IsError)
+
+		response, ok := result.StructuredContent.(query.SearchProductsResponse)
+		require.True(t, ok)
+		return response.Count
+	}
+
+	limitSchema := func(t *testing.T, server *Server, tool string) map[string]any {
+		t.Helper()
+		message := server.mcpServer.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+		encoded, err := json.Marshal(message)
+		require.NoError(t, err)
+
+		var response struct {
+			Result mcp.ListToolsResult `json:"result"`
+		}
+		require.NoError(t, json.Unmarshal(encoded, &response))
+		for _, listed := range response.Result.Tools {
+			if listed.Name == tool {
+				limit, ok := listed.InputSchema.Properties["limit"].(map[string]any)
+				require.True(t, ok)
+				return limit
+			}
+		}
+		t.Fatalf("tool %s not listed", tool)
+		return nil
+	}
+
+	t.Run("keeps the 3 and 10 defaults", func(t *testing.T) {
+		server := NewServer(mockEngine, auth.NewBearerTokenAuth("test-token"), logger)
+
+		assert.Equal(t, 3, search(t, server, map[string]any{"name": "milk"}))
+		assert.Equal(t, 10, search(t, server, map[string]any{"name": "milk", "limit": 50}))
+
+		schema := limitSchema(t, server, "search_foundation_foods_by_name")
+		assert.Equal(t, float64(3), schema["default"])
+		assert.Equal(t, float64(10), schema["maximum"])
+	})
+
+	t.Run("applies configured limits to handlers and schemas", func(t *testing.T) {
+		server := NewServer(mockEngine, auth.NewBearerTokenAuth("test-token"), logger, WithSearchLimits(5, 15))
+
+		assert.Equal(t, 5, search(t, server, map[string]any{"name": "milk"}))
+		assert.Equal(t, 15, search(t, server, map[string]any{"name": "milk", "limit": 50}))
+
+		for _, tool := range []string{
+			"search_foundation_foods_by_name",
+			"search_foundation_foods_and_return_nutrients",
+			"search_foundation_foods_and_return_nutrients_simplified",
+			"batch_search_foundation_foods",
+			"nutrient_vectors",
+		} {
+			schema := limitSchema(t, server, tool)
+			assert.Equal(t, float64(5), schema["default"], tool)
+			assert.Equal(t, float64(15), schema["maximum"], tool)
+			assert.Contains(t, schema["description"], "default: 5, max: 15", tool)
+		}
+
+		vectors := func(args map[string]any) int {
+			request := mcp.CallToolRequest{}
+			request.Params.Arguments = args
+
+			result, err := server.handleNutrientVectors(context.Background(), request)
+			require.NoError(t, err)
+			require.False(t, result.IsError)
+			return mockEngine.lastVectorLimit
+		}
+		assert.Equal(t, 5, vectors(map[string]any{"name": "milk"}))
+		assert.Equal(t, 15, vectors(map[string]any{"name": "milk", "limit": 50}))
+	})
+
+	t.Run("bounds a huge configured maximum", func(t *testing.T) {
+		server := NewServer(mockEngine, auth.NewBearerTokenAuth("test-token"), logger, WithSearchLimits(0, 1_000_000))
+
+		schema := limitSchema(t, server, "search_foundation_foods_and_return_nutrients_simplified")
+		assert.Equal(t, float64(query.MaxSearchLimit), schema["maximum"])
+	})
+}
@@ -3,13 +3,19 @@ package mcpgo
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/noot-app/foundation-foods-mcp-server/internal/auth"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
 	"github.com/noot-app/foundation-foods-mcp-server/internal/query"
 )
 
@@ -39,34 +45,217 @@ func (r *responseRecorder) Write(data []byte) (int, error) {
 	return n, err
 }
 
+// defaultAggregateMaxResults is the page size cap applied to aggregate tools when not configured
+const defaultAggregateMaxResults = 50
+
+// defaultServerName and defaultServerVersion are reported in serverInfo when not configured
+const (
+	defaultServerName    = "FoundationFoods MCP Server"
+	defaultServerVersion = "1.0.0"
+)
+
+// defaultHealthProbeQuery is the sentinel query the health endpoint searches for when not configured
+const defaultHealthProbeQuery = "milk"
+
+// defaultCanonicalMinConfidence is the confidence threshold for canonicalize_food_name when not configured
+const defaultCanonicalMinConfidence = 0.1
+
+// Found flag semantics for search responses
+const (
+	// FoundSemanticsHasResults sets found only when at least one result matched
+	FoundSemanticsHasResults = "has_results"
+
+	// FoundSemanticsQuerySucceeded sets found whenever the query ran without error, even with zero results
+	FoundSemanticsQuerySucceeded = "query_succeeded"
+)
+
 // Server wraps the mark3labs MCP server with authentication
 type Server struct {
 	mcpServer   *server.MCPServer
 	queryEngine query.QueryEngine
 	auth        *auth.BearerTokenAuth
 	log         *slog.Logger
+	hooks       *server.Hooks
+
+	// serverName and serverVersion are reported in the serverInfo of the initialize response
+	serverName    string
+	serverVersion string
+
+	// stateless disables MCP session tracking on the streamable HTTP transport
+	stateless bool
+
+	// cursors holds the session-scoped search cursors issued in stateful mode
+	cursors *cursorStore
+
+	// defaultCategory scopes searches to a single food category unless a request overrides it
+	defaultCategory string
+
+	// canonicalMinConfidence is the default confidence below which canonicalize_food_name returns null
+	canonicalMinConfidence float64
+
+	// aggregateMaxResults caps how many rows a single aggregate tool call may return
+	aggregateMaxResults int
+
+	// maxBatchResults caps names × limit for a single batch search
+	maxBatchResults int
+
+	// searchLimits bounds the limit argument of the name search tools
+	searchLimits query.SearchLimits
+
+	// notablePercentile is the default percentile rank threshold for notable_only
+	notablePercentile float64
+
+	// foundSemantics controls what the found flag of search responses means
+	foundSemantics string
+
+	// healthProbeQuery is the sentinel query /health must find results for (empty disables the probe)
+	healthProbeQuery string
+	healthMu         sync.Mutex
+	lastProbe        healthProbeResult
+
+	// strictArgs rejects tool calls that pass arguments the tool doesn't define
+	strictArgs bool
+
+	// contentTypeMeta declares the media type of successful tool results' text content in its _meta
+	contentTypeMeta bool
+
+	// rateLimiter limits how often each client may call /mcp (nil disables limiting)
+	rateLimiter *rateLimiter
+
+	// maxRequestBytes caps the size of /mcp request bodies
+	maxRequestBytes int64
+
+	// responseBudget bounds how long name searches scan before returning partial results (0 means no bound)
+	responseBudget time.Duration
+
+	// shutdownTimeout is how long ServeHTTP drains in-flight requests once its context is canceled
+	shutdownTimeout time.Duration
+
+	// tlsCertFile and tlsKeyFile make ServeHTTP serve HTTPS when both are set
+	tlsCertFile string
+	tlsKeyFile  string
+
+	// toolDescriptions overrides built-in tool descriptions by tool name
+	toolDescriptions map[string]string
+	toolNames        map[string]bool
+}
+
+// Option configures optional Server behavior
+type Option func(*Server)
+
+// WithServerInfo sets the name and version reported in serverInfo on initialize. Empty values keep the defaults.
+func WithServerInfo(name, version string) Option {
+	return func(s *Server) {
+		if name = strings.TrimSpace(name); name != "" {
+			s.serverName = name
+		}
+		if version = strings.TrimSpace(version); version != "" {
+			s.serverVersion = version
+		}
+	}
+}
+
+// WithStateless toggles stateless mode on the streamable HTTP transport. Stateless mode is friendlier to
+// OpenAI and plain load balancers; stateful mode keeps MCP sessions but needs sticky sessions when scaled out.
+func WithStateless(stateless bool) Option {
+	return func(s *Server) {
+		s.stateless = stateless
+	}
+}
+
+// WithDefaultCategory scopes all searches to the given food category unless a request passes category=all
+func WithDefaultCategory(category string) Option {
+	return func(s *Server) {
+		s.defaultCategory = strings.TrimSpace(category)
+	}
+}
+
+// WithAggregateMaxResults caps how many rows a single aggregate tool call may return
+func WithAggregateMaxResults(maxResults int) Option {
+	return func(s *Server) {
+		if maxResults > 0 {
+			s.aggregateMaxResults = maxResults
+		}
+	}
+}
+
+// WithSearchLimits sets the default and maximum limit of the name search tools, as advertised in their schemas.
+// Non-positive values keep the built-in default of 3 and maximum of 10; the maximum never exceeds
+// query.MaxSearchLimit.
+func WithSearchLimits(defaultLimit, maxLimit int) Option {
+	return func(s *Server) {
+		s.searchLimits = query.NewSearchLimits(defaultLimit, maxLimit)
+	}
+}
+
+// WithCanonicalMinConfidence sets the default confidence below which canonicalize_food_name returns null
+func WithCanonicalMinConfidence(minConfidence float64) Option {
+	return func(s *Server) {
+		s.canonicalMinConfidence = minConfidence
+	}
+}
+
+// WithNotablePercentile sets the default percentile rank a nutrient must reach to be returned with notable_only
+func WithNotablePercentile(percentile float64) Option {
+	return func(s *Server) {
+		if percentile > 0 && percentile <= 100 {
+			s.notablePercentile = percentile
+		}
+	}
+}
+
+// WithFoundSemantics selects what the found flag of search responses means. Unknown values keep has_results.
+func WithFoundSemantics(semantics string) Option {
+	return func(s *Server) {
+		if semantics == FoundSemanticsQuerySucceeded {
+			s.foundSemantics = FoundSemanticsQuerySucceeded
+		}
+	}
 }
 
 // NewServer creates a new MCP server with the mark3labs SDK
-func NewServer(queryEngine query.QueryEngine, authenticator *auth.BearerTokenAuth, logger *slog.Logger) *Server {
-	// Create MCP server
-	mcpServer := server.NewMCPServer(
-		"FoundationFoods MCP Server",
-		"1.0.0",
-		server.WithToolCapabilities(false), // Tools don't change dynamically
-		server.WithRecovery(),              // Recover from panics
-		server.WithLogging(),               // Enable logging
-	)
+func NewServer(queryEngine query.QueryEngine, authenticator *auth.BearerTokenAuth, logger *slog.Logger, opts ...Option) *Server {
+	// Hooks are shared with the MCP server so transports can attach request logging later
+	hooks := &server.Hooks{}
 
 	s := &Server{
-		mcpServer:   mcpServer,
 		queryEngine: queryEngine,
 		auth:        authenticator,
 		log:         logger,
+		hooks:       hooks,
+		cursors:     newCursorStore(),
+		stateless:   true,
+
+		serverName:             defaultServerName,
+		serverVersion:          defaultServerVersion,
+		aggregateMaxResults:    defaultAggregateMaxResults,
+		canonicalMinConfidence: defaultCanonicalMinConfidence,
+		maxBatchResults:        defaultMaxBatchResults,
+		searchLimits:           query.DefaultSearchLimits,
+		notablePercentile:      query.DefaultNotablePercentile,
+		foundSemantics:         FoundSemanticsHasResults,
+		healthProbeQuery:       defaultHealthProbeQuery,
+		shutdownTimeout:        defaultShutdownTimeout,
+		maxRequestBytes:        defaultMaxRequestBytes,
 	}
 
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	// Create MCP server
+	s.mcpServer = server.NewMCPServer(
+		s.serverName,
+		s.serverVersion,
+		server.WithToolCapabilities(false), // Tools don't change dynamically
+		server.WithRecovery(),              // Recover from panics
+		server.WithLogging(),               // Enable logging
+		server.WithHooks(hooks),
+	)
+
 	// Add tools
 	s.addTools()
+	s.warnUnknownToolDescriptions()
 
 	return s
 }
@@ -81,16 +270,85 @@ func (s *Server) addTools() {
 			mcp.Description("Food items/name to search for. Required and must be a non-empty string."),
 		),
 		mcp.WithNumber("limit",
-			mcp.Description("Maximum number of results (default: 3, max: 10)"),
-			mcp.DefaultNumber(3),
+			mcp.Description(fmt.Sprintf("Maximum number of results (default: %d, max: %d)", s.searchLimits.Default, s.searchLimits.Max)),
+			mcp.DefaultNumber(float64(s.searchLimits.Default)),
 			mcp.Min(1),
-			mcp.Max(10),
+			mcp.Max(float64(s.searchLimits.Max)),
+		),
+		mcp.WithBoolean("per_serving",
+			mcp.Description("Scale every nutrient amount from per 100 g to a single serving. The portion used is returned in 'servingPortion' on each food. Foods without a usable portion are left per 100 g."),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithString("portion_label",
+			mcp.Description("Optional measure unit name or abbreviation (e.g. 'cup', 'tbsp') selecting which portion to scale to when per_serving is true. Defaults to the food's first portion by sequence number."),
+		),
+		mcp.WithString("verbosity",
+			mcp.Description("Payload size of each food: 'full' (default) returns everything, 'standard' drops each nutrient's source, 'lean' drops each nutrient's derivation and source."),
+			mcp.Enum(query.VerbosityFull, query.VerbosityStandard, query.VerbosityLean),
+		),
+		mcp.WithBoolean("include_scores",
+			mcp.Description("Include each food's relevance 'score' so weak matches can be told from strong ones. An exact description match scores 1000 or more; a substring match within a word scores around 10."),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("exact",
+			mcp.Description("Only return foods whose description equals 'name' exactly, ignoring case and the punctuation search ignores (such as commas), instead of ranking partial matches. Use it to look up a food by its precise USDA description. Nothing is returned when no description matches exactly, even with return_nearest_on_empty."),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("include_highlights",
+			mcp.Description("Include each food's 'highlights': for every query word, where it matched the description as character offset ranges ('start' inclusive, 'end' exclusive) and how ('exact', 'plural', 'prefix', 'partial', 'synonym' or 'fuzzy'). Useful for highlighting matches in a UI or understanding a ranking."),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithNumber("offset",
+			mcp.Description("Number of ranked results to skip before applying the limit, for paging (default: 0). Compare with 'total' in the response to tell whether more pages exist; an offset past the end returns no products."),
+			mcp.DefaultNumber(0),
+			mcp.Min(0),
 		),
+		mcp.WithString("cursor",
+			mcp.Description("Opaque 'nextCursor' from a previous response, returning the next page of the same search. Only issued when the server runs in stateful mode; 'name' must match the original search and the original limit and category are reused."),
+		),
+		mcp.WithArray("response_fields",
+			mcp.Description("Optional list of top-level food fields to return (e.g. ['description', 'fdcId', 'foodNutrients']). When set, every other field is omitted. Unknown field names are ignored and reported in 'unknownFields'."),
+			mcp.Items(map[string]any{"type": "string"}),
+		),
+		mcp.WithString("sort_by",
+			mcp.Description("Order of the matches before offset and limit are applied: 'relevance' (default), 'description' for alphabetical, or 'nutrient:<name>' (e.g. 'nutrient:Protein') for the highest amount per 100 g first. Foods without that nutrient sort last."),
+			mcp.DefaultString(query.SortByRelevance),
+		),
+		withMinScoreParam(),
+		withPreparationParam(),
+		withFuzzyParam(),
+		withNearestParam(),
+		withRoundingParam(),
+		withCategoryParam(),
 		mcp.WithOutputSchema[query.SearchProductsResponse](),
 		mcp.WithIdempotentHintAnnotation(true),
 	)
 
-	s.mcpServer.AddTool(searchTool, s.handleFoodSearch)
+	s.addTool(searchTool, s.handleFoodSearch)
+
+	// Lightweight search returning only what's needed to pick a food to drill into
+	namesOnlyTool := mcp.NewTool("search_foundation_foods_names_only",
+		mcp.WithDescription("Search USDA foundation foods by name and return only each match's FDC ID, description and food category, without nutrients. Use this first to pick a food cheaply, then fetch its details by FDC ID with another tool."),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.MinLength(1),
+			mcp.Description("Food items/name to search for. Required and must be a non-empty string."),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Maximum number of results (default: %d, max: %d)", defaultNamesOnlyResults, maxNamesOnlyResults)),
+			mcp.DefaultNumber(defaultNamesOnlyResults),
+			mcp.Min(1),
+			mcp.Max(maxNamesOnlyResults),
+		),
+		withMinScoreParam(),
+		withPreparationParam(),
+		withFuzzyParam(),
+		withCategoryParam(),
+		mcp.WithOutputSchema[NamesOnlySearchResponse](),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+
+	s.addTool(namesOnlyTool, s.handleNamesOnlyFoodSearch)
 
 	// Simplified nutrients search tool
 	simplifiedTool := mcp.NewTool("search_foundation_foods_and_return_nutrients",
@@ -101,21 +359,47 @@ func (s *Server) addTools() {
 			mcp.Description("Food items/name to search for. Required and must be a non-empty string."),
 		),
 		mcp.WithNumber("limit",
-			mcp.Description("Maximum number of results (default: 3, max: 10)"),
-			mcp.DefaultNumber(3),
+			mcp.Description(fmt.Sprintf("Maximum number of results (default: %d, max: %d)", s.searchLimits.Default, s.searchLimits.Max)),
+			mcp.DefaultNumber(float64(s.searchLimits.Default)),
 			mcp.Min(1),
-			mcp.Max(10),
+			mcp.Max(float64(s.searchLimits.Max)),
 		),
 		mcp.WithArray("nutrients_to_include",
-			mcp.Description("Optional list of nutrient names to include in the response. If empty or not provided, a default set of essential nutrients will be included."),
-			mcp.Items([]string{}),
+			mcp.Description("Optional list of nutrient names to include in the response. If empty or not provided, a default set of essential nutrients will be included. Names are trimmed and deduplicated case-insensitively; names that match no nutrient of the returned foods are listed in 'unmatchedFilters'."),
+			mcp.Items(map[string]any{"type": "string"}),
 			mcp.DefaultArray(query.DefaultNutrients),
 		),
+		mcp.WithString("format",
+			mcp.Description("Format of the tool result text: 'json' (default), 'markdown' for a GitHub-flavored markdown table per food, or 'csv' for one row per nutrient with columns description, fdcId, nutrient, amount, unit. Structured content is always JSON."),
+			mcp.Enum(formatJSON, formatMarkdown, formatCSV),
+			mcp.DefaultString(formatJSON),
+		),
+		mcp.WithNumber("relative_to_reference",
+			mcp.Description("Optional FDC ID of a reference food. Each returned nutrient then also includes 'relativeToReference', the ratio of the food's amount to the reference food's amount (e.g. 2.3 = 2.3x the reference). Nutrients the reference lacks get no ratio."),
+		),
+		mcp.WithBoolean("include_sequence",
+			mcp.Description("Include each portion's USDA sequence number (its intended display order). Portions are always returned in this order."),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("include_derivation",
+			mcp.Description("Attach each nutrient's USDA derivation, e.g. {code: 'A', description: 'Analytical'} or {code: 'NC', description: 'Calculated'}, saying how the amount was obtained. Omitted by default to keep responses small."),
+			mcp.DefaultBool(false),
+		),
+		withNotableParams(),
+		withMergeParams(),
+		withEnergyUnitParam(),
+		withSimplifiedPortionParams(),
+		withMinScoreParam(),
+		withPreparationParam(),
+		withFuzzyParam(),
+		withNearestParam(),
+		withRoundingParam(),
+		withCategoryParam(),
 		mcp.WithOutputSchema[query.SimplifiedNutrientResponse](),
 		mcp.WithIdempotentHintAnnotation(true),
 	)
 
-	s.mcpServer.AddTool(simplifiedTool, s.handleSimplifiedFoodSearch)
+	s.addTool(simplifiedTool, s.handleSimplifiedFoodSearch)
 
 	// Fixed default nutrients search tool (no customization allowed)
 	simplifiedFixedTool := mcp.NewTool("search_foundation_foods_and_return_nutrients_simplified",
@@ -126,50 +410,830 @@ func (s *Server) addTools() {
 			mcp.Description("Food items/name to search for. Required and must be a non-empty string."),
 		),
 		mcp.WithNumber("limit",
-			mcp.Description("Maximum number of results (default: 3, max: 10)"),
-			mcp.DefaultNumber(3),
+			mcp.Description(fmt.Sprintf("Maximum number of results (default: %d, max: %d)", s.searchLimits.Default, s.searchLimits.Max)),
+			mcp.DefaultNumber(float64(s.searchLimits.Default)),
 			mcp.Min(1),
-			mcp.Max(10),
+			mcp.Max(float64(s.searchLimits.Max)),
+		),
+		mcp.WithString("format",
+			mcp.Description("Format of the tool result text: 'json' (default), 'markdown' for a GitHub-flavored markdown table per food, or 'csv' for one row per nutrient with columns description, fdcId, nutrient, amount, unit. Structured content is always JSON."),
+			mcp.Enum(formatJSON, formatMarkdown, formatCSV),
+			mcp.DefaultString(formatJSON),
+		),
+		mcp.WithNumber("relative_to_reference",
+			mcp.Description("Optional FDC ID of a reference food. Each returned nutrient then also includes 'relativeToReference', the ratio of the food's amount to the reference food's amount (e.g. 2.3 = 2.3x the reference). Nutrients the reference lacks get no ratio."),
+		),
+		mcp.WithBoolean("include_sequence",
+			mcp.Description("Include each portion's USDA sequence number (its intended display order). Portions are always returned in this order."),
+			mcp.DefaultBool(false),
 		),
+		mcp.WithBoolean("include_derivation",
+			mcp.Description("Attach each nutrient's USDA derivation, e.g. {code: 'A', description: 'Analytical'} or {code: 'NC', description: 'Calculated'}, saying how the amount was obtained. Omitted by default to keep responses small."),
+			mcp.DefaultBool(false),
+		),
+		withNotableParams(),
+		withMergeParams(),
+		withEnergyUnitParam(),
+		withSimplifiedPortionParams(),
+		withMinScoreParam(),
+		withPreparationParam(),
+		withFuzzyParam(),
+		withNearestParam(),
+		withRoundingParam(),
+		withCategoryParam(),
 		mcp.WithOutputSchema[query.SimplifiedNutrientResponse](),
 		mcp.WithIdempotentHintAnnotation(true),
 	)
 
-	s.mcpServer.AddTool(simplifiedFixedTool, s.handleSimplifiedFixedFoodSearch)
+	s.addTool(simplifiedFixedTool, s.handleSimplifiedFixedFoodSearch)
+
+	// Food versus category average comparison tool
+	foodVsCategoryTool := mcp.NewTool("food_vs_category",
+		mcp.WithDescription("Compare a USDA foundation food against the average of its food category. Returns, per nutrient, the food's amount, the category mean, the signed difference and the percentage deviation. Useful for questions like 'is this cheese higher in sodium than typical cheeses?'."),
+		mcp.WithNumber("fdcId",
+			mcp.Required(),
+			mcp.Description("FDC ID of the food to compare against its category."),
+		),
+		withPagingParams(0),
+		mcp.WithOutputSchema[query.FoodVsCategoryResponse](),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+
+	s.addTool(foodVsCategoryTool, s.handleFoodVsCategory)
+
+	// Two-food nutrient difference tool
+	differencesTool := mcp.NewTool("top_nutrient_differences",
+		mcp.WithDescription("Return the nutrients that differ most between two USDA foundation foods, e.g. 'the biggest differences are sodium and calcium'. Amounts are normalized to grams (mass) and kcal (energy) before comparing, and a nutrient reported by only one food counts as zero in the other."),
+		mcp.WithNumber("fdcIdA",
+			mcp.Required(),
+			mcp.Description("FDC ID of the first food."),
+		),
+		mcp.WithNumber("fdcIdB",
+			mcp.Required(),
+			mcp.Description("FDC ID of the second food. Differences are reported as B minus A."),
+		),
+		withPagingParams(defaultNutrientDifferences),
+		mcp.WithString("rank_by",
+			mcp.Description("Rank by 'absolute' difference of the normalized amounts (default) or by 'percent' difference relative to the larger amount."),
+			mcp.Enum(query.RankByAbsolute, query.RankByPercent),
+		),
+		mcp.WithOutputSchema[query.NutrientDifferencesResponse](),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+
+	s.addTool(differencesTool, s.handleTopNutrientDifferences)
+
+	// Side-by-side comparison tool
+	compareTool := mcp.NewTool("compare_foundation_foods",
+		mcp.WithDescription("Compare 2 to 5 USDA foundation foods side by side, e.g. 'how does whole milk compare to 2% milk on fat and calcium?'. Returns one row per nutrient with each food's amount and unit per 100 g, in the order the FDC IDs were given, and null where a food lacks the nutrient."),
+		mcp.WithArray("fdcIds",
+			mcp.Required(),
+			mcp.Description("FDC IDs of the foods to compare (2 to 5)."),
+			mcp.Items(map[string]any{"type": "number"}),
+			mcp.MinItems(query.MinCompareFoods),
+			mcp.MaxItems(query.MaxCompareFoods),
+		),
+		mcp.WithArray("nutrients_to_include",
+			mcp.Description("Optional list of nutrient names to compare, one row each. If empty or not provided, the default set of essential nutrients is compared. Names that none of the foods report are listed in 'unmatchedFilters'."),
+			mcp.Items(map[string]any{"type": "string"}),
+			mcp.DefaultArray(query.DefaultNutrients),
+		),
+		mcp.WithOutputSchema[query.CompareFoodsResponse](),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+
+	s.addTool(compareTool, s.handleCompareFoundationFoods)
+
+	// Batch search tool
+	batchSearchTool := mcp.NewTool("batch_search_foundation_foods",
+		mcp.WithDescription("Search USDA foundation foods for several names in one call, returning up to 'limit' complete food matches per name. The number of names times 'limit' is capped per call; use resolve_foods when only the single best match per name is needed."),
+		mcp.WithArray("names",
+			mcp.Required(),
+			mcp.Description("List of food names to search for."),
+			mcp.Items(map[string]any{"type": "string"}),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Maximum number of results per name (default: %d, max: %d). The number of names times limit may not exceed the server's batch result cap.", s.searchLimits.Default, s.searchLimits.Max)),
+			mcp.DefaultNumber(float64(s.searchLimits.Default)),
+			mcp.Min(1),
+			mcp.Max(float64(s.searchLimits.Max)),
+		),
+		withCategoryParam(),
+		mcp.WithOutputSchema[BatchSearchResponse](),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+
+	s.addTool(batchSearchTool, s.handleBatchFoodSearch)
+
+	// Batch best-match resolution tool
+	resolveFoodsTool := mcp.NewTool("resolve_foods",
+		mcp.WithDescription("Resolve a list of food names (e.g. a recipe's ingredient strings) to USDA foundation foods. Returns, for each name, only the single best-matching food's description and FDC ID (null when nothing matches) plus a 0-1 confidence derived from the match score and its lead over the runner-up. Matches below 0.5 are usually weak or ambiguous."),
+		mcp.WithArray("names",
+			mcp.Required(),
+			mcp.Description("List of food names to resolve (max 50)."),
+			mcp.Items(map[string]any{"type": "string"}),
+		),
+		withCategoryParam(),
+		mcp.WithOutputSchema[query.ResolveFoodsResponse](),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+
+	s.addTool(resolveFoodsTool, s.handleResolveFoods)
+
+	// Canonical description lookup tool
+	canonicalizeTool := mcp.NewTool("canonicalize_food_name",
+		mcp.WithDescription("Map a loose food name (e.g. '2 percent milk') to the exact canonical USDA description string and FDC ID of its best match, for storing food names consistently. Returns null fields when the match confidence is below the threshold."),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.MinLength(1),
+			mcp.Description("Loose food name to canonicalize. Required and must be a non-empty string."),
+		),
+		mcp.WithNumber("min_confidence",
+			mcp.Description("Optional 0-1 confidence below which no match is returned. Defaults to the server's configured threshold."),
+			mcp.Min(0),
+			mcp.Max(1),
+		),
+		withCategoryParam(),
+		mcp.WithOutputSchema[query.ResolvedFood](),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+
+	s.addTool(canonicalizeTool, s.handleCanonicalizeFoodName)
+
+	// Disambiguation tool
+	alternativesTool := mcp.NewTool("search_with_alternatives",
+		mcp.WithDescription("Search USDA foundation foods by name and return the best match plus up to N alternatives, each with its relevance score and a short note on why it matched. Designed for presenting a user with options when a name is ambiguous, e.g. 'cheese' or 'apple'."),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.MinLength(1),
+			mcp.Description("Food name to search for."),
+		),
+		mcp.WithNumber("alternatives",
+			mcp.Description("Number of alternatives to return besides the best match (default: 4, max: 10)."),
+			mcp.DefaultNumber(4),
+			mcp.Min(0),
+			mcp.Max(10),
+		),
+		withCategoryParam(),
+		mcp.WithOutputSchema[query.SearchWithAlternativesResponse](),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+
+	s.addTool(alternativesTool, s.handleSearchWithAlternatives)
+
+	// Scoring explanation tool for diagnosing rankings
+	explainTool := mcp.NewTool("explain_food_search",
+		mcp.WithDescription("Explain how a name search scores one food: returns the step-by-step relevance score of a single description (or the food with an FDC ID) for a query, including the exact, prefix and substring bonuses, each query word's match and points, and every multiplier applied (multi-word bonus, long-description penalty, food-context and preparation adjustments). A debugging tool for understanding why foods rank where they do."),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.MinLength(1),
+			mcp.Description("Search query to score, as passed to search_foundation_foods_by_name."),
+		),
+		mcp.WithString("description",
+			mcp.Description("Food description to score the query against. Provide this or 'fdcId', not both. Need not be in the dataset."),
+		),
+		mcp.WithNumber("fdcId",
+			mcp.Description("FDC ID of the food whose description is scored. Provide this or 'description', not both."),
+		),
+		withPreparationParam(),
+		withFuzzyParam(),
+		mcp.WithOutputSchema[query.SearchExplanation](),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+
+	s.addTool(explainTool, s.handleExplainFoodSearch)
+
+	// Reverse ingredient lookup tool
+	ingredientTool := mcp.NewTool("find_foods_containing_ingredient",
+		mcp.WithDescription("Find foods whose input foods (the ingredients of composite foods) match an ingredient, e.g. 'tomato'. Every word of the ingredient must appear in the same input food description. Returns each food's description and FDC ID alongside the matched ingredient."),
+		mcp.WithString("ingredient",
+			mcp.Required(),
+			mcp.MinLength(1),
+			mcp.Description("Ingredient to look for in the foods' input foods. Required and must be a non-empty string."),
+		),
+		withPagingParams(0),
+		withCategoryParam(),
+		mcp.WithOutputSchema[query.IngredientSearchResponse](),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+
+	s.addTool(ingredientTool, s.handleFindFoodsContainingIngredient)
+
+	// Composite food breakdown tool
+	inputsTool := mcp.NewTool("get_food_with_inputs",
+		mcp.WithDescription("Return a USDA foundation food together with the full records of the input foods it is composed of, expanded recursively up to 'depth' levels, for a complete ingredient breakdown in one call. Inputs are listed depth-first with their level and parent FDC ID; inputs whose FDC ID isn't in the dataset are returned as stubs with found=false."),
+		mcp.WithNumber("fdcId",
+			mcp.Required(),
+			mcp.Description("FDC ID of the food."),
+		),
+		mcp.WithNumber("depth",
+			mcp.Description(fmt.Sprintf("How many levels of input foods to expand (default: 1, max: %d). 1 returns only the food's direct inputs.", query.MaxInputDepth)),
+			mcp.DefaultNumber(1),
+			mcp.Min(1),
+			mcp.Max(query.MaxInputDepth),
+		),
+		mcp.WithOutputSchema[query.FoodWithInputsResponse](),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+
+	s.addTool(inputsTool, s.handleGetFoodWithInputs)
+
+	// Input food listing tool
+	inputFoodsTool := mcp.NewTool("get_food_input_foods",
+		mcp.WithDescription("List the FDC IDs and descriptions of the input foods a USDA foundation food is composed of, without their full records. Call it again on an input's FDC ID to traverse from a composite food to its components. Foods without input foods return an empty list; use get_food_with_inputs for the inputs' full records."),
+		mcp.WithNumber("fdcId",
+			mcp.Required(),
+			mcp.Description("FDC ID of the food."),
+		),
+		mcp.WithOutputSchema[query.FoodInputFoodsResponse](),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+
+	s.addTool(inputFoodsTool, s.handleGetFoodInputFoods)
+
+	// Household measure scaling tool
+	householdTool := mcp.NewTool("nutrients_for_household_portion",
+		mcp.WithDescription("Return every nutrient of a USDA foundation food scaled to a household measure such as '2 cups' or '1/2 tbsp'. The gram weight is resolved from the food's portions; when the unit isn't available the error lists the units that are."),
+		mcp.WithNumber("fdcId",
+			mcp.Required(),
+			mcp.Description("FDC ID of the food."),
+		),
+		mcp.WithString("measure",
+			mcp.Required(),
+			mcp.MinLength(1),
+			mcp.Description("Household measure: an optional quantity (decimal or fraction) followed by a unit, e.g. '2 cups', '1/2 tbsp' or 'cup'."),
+		),
+		mcp.WithOutputSchema[query.HouseholdPortionResponse](),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+
+	s.addTool(householdTool, s.handleNutrientsForHouseholdPortion)
+
+	// Net carbs tool
+	netCarbsTool := mcp.NewTool("net_carbs",
+		mcp.WithDescription("Return the net carbs of a USDA foundation food: total carbohydrate minus dietary fiber, and minus sugar alcohols when the food reports them. Scaled to a household measure such as '1 cup', or per 100 g when no measure is given. Foods without a fiber value report net carbs equal to total carbs with fiberAssumedZero set."),
+		mcp.WithNumber("fdcId",
+			mcp.Required(),
+			mcp.Description("FDC ID of the food."),
+		),
+		mcp.WithString("measure",
+			mcp.Description("Optional household measure for the serving, e.g. '1 cup' or '1/2 tbsp'. Defaults to 100 g."),
+		),
+		mcp.WithBoolean("subtract_sugar_alcohols",
+			mcp.Description("Also subtract sugar alcohols when the food reports them (default: true)."),
+			mcp.DefaultBool(true),
+		),
+		mcp.WithOutputSchema[query.NetCarbsResponse](),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+
+	s.addTool(netCarbsTool, s.handleNetCarbs)
+
+	// Upper intake limits tool
+	upperLimitsTool := mcp.NewTool("nutrients_with_upper_limits",
+		mcp.WithDescription("Return a USDA foundation food's nutrients for a serving, each annotated with its adult Tolerable Upper Intake Level (UL) and the serving's percentage of it, flagging nutrients where the serving alone exceeds the UL. Nutrients without a UL are returned without a limit. Useful for safety questions like 'is this over the upper limit for sodium?'."),
+		mcp.WithNumber("fdcId",
+			mcp.Required(),
+			mcp.Description("FDC ID of the food."),
+		),
+		mcp.WithString("measure",
+			mcp.Description("Optional household measure for the serving, e.g. '1 cup' or '2 tbsp'. Defaults to 100 g."),
+		),
+		mcp.WithOutputSchema[query.UpperLimitsResponse](),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+
+	s.addTool(upperLimitsTool, s.handleNutrientsWithUpperLimits)
+
+	// Day tracking tool
+	dailyIntakeTool := mcp.NewTool("daily_intake_summary",
+		mcp.WithDescription("Total the nutrients of the foods eaten in a day and compare each total with a demographic's recommended daily intake (RDA, or AI where no RDA is set) and Tolerable Upper Intake Level. Each food name is resolved to its best USDA foundation food match and scaled to the grams eaten. Returns per-nutrient totals with the amount 'remaining' to reach the target (negative when exceeded); foods that match nothing are listed in 'unresolved' and left out of the totals."),
+		mcp.WithArray("foods",
+			mcp.Required(),
+			mcp.Description("Foods eaten, e.g. [{\"food\": \"milk, whole\", \"grams\": 244}]. Grams must be positive."),
+			mcp.Items(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"food":  map[string]any{"type": "string", "description": "Food name to resolve"},
+					"grams": map[string]any{"type": "number", "description": "Grams eaten"},
+				},
+				"required": []string{"food", "grams"},
+			}),
+		),
+		mcp.WithString("demographic",
+			mcp.Required(),
+			mcp.Description("Whose daily targets to compare against (adults 19-50)."),
+			mcp.Enum(query.Demographics()...),
+		),
+		withCategoryParam(),
+		mcp.WithOutputSchema[query.DailyIntakeSummaryResponse](),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+
+	s.addTool(dailyIntakeTool, s.handleDailyIntakeSummary)
+
+	// Protein density ranking tool
+	proteinDensityTool := mcp.NewTool("rank_by_protein_density",
+		mcp.WithDescription("Return the USDA foundation foods with the most grams of protein per 100 kcal, optionally within one food category. Foods missing protein or kcal energy are excluded. Useful for questions like 'what are the leanest protein sources?'."),
+		withPagingParams(defaultProteinDensityResults),
+		withCategoryParam(),
+		mcp.WithOutputSchema[query.ProteinDensityResponse](),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+
+	s.addTool(proteinDensityTool, s.handleRankByProteinDensity)
+
+	// Highest nutrient content ranking tool
+	highestInNutrientTool := mcp.NewTool("find_foods_highest_in_nutrient",
+		mcp.WithDescription("Return the USDA foundation foods with the highest amount of one nutrient per 100 g, optionally within one food category. Foods that don't report the nutrient are excluded and ties are ordered by description. Useful for questions like 'which foods are highest in calcium?'."),
+		mcp.WithString("nutrient",
+			mcp.Required(),
+			mcp.MinLength(1),
+			mcp.Description("Nutrient name as it appears in the dataset, e.g. 'Calcium, Ca', 'Protein' or 'Vitamin C, total ascorbic acid'."),
+		),
+		withPagingParams(defaultHighestInNutrientResults),
+		withCategoryParam(),
+		mcp.WithOutputSchema[query.NutrientRankingResponse](),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+
+	s.addTool(highestInNutrientTool, s.handleFindFoodsHighestInNutrient)
+
+	// Nutrient amount range tool
+	nutrientRangeTool := mcp.NewTool("find_foods_with_nutrient_in_range",
+		mcp.WithDescription("Return the USDA foundation foods whose amount of one nutrient per 100 g falls within an inclusive range, highest first, with the amount and unit of each. Supports dietary queries like 'foods with 0-1 g saturated fat' (min 0, max 1) or 'protein above 20 g' (min 20). Bounds are in the unit the dataset reports the nutrient in."),
+		mcp.WithString("nutrient",
+			mcp.Required(),
+			mcp.MinLength(1),
+			mcp.Description("Nutrient name as it appears in the dataset, e.g. 'Fatty acids, total saturated', 'Protein' or 'Vitamin C'."),
+		),
+		mcp.WithNumber("min",
+			mcp.Description("Smallest amount to include. Omit to leave the range open below."),
+		),
+		mcp.WithNumber("max",
+			mcp.Description("Largest amount to include. Omit to leave the range open above."),
+		),
+		withPagingParams(defaultHighestInNutrientResults),
+		withCategoryParam(),
+		mcp.WithOutputSchema[query.NutrientRangeResponse](),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+
+	s.addTool(nutrientRangeTool, s.handleFindFoodsWithNutrientInRange)
+
+	// Multiple nutrient sources tool
+	multiNutrientTool := mcp.NewTool("multi_nutrient_sources",
+		mcp.WithDescription("Return the USDA foundation foods that are good sources of several nutrients at once: foods whose amount of every listed nutrient ranks at or above a percentile across the dataset, ordered by their mean percentile. Useful for questions like 'which foods are high in both iron and vitamin C?'."),
+		mcp.WithArray("nutrients",
+			mcp.Required(),
+			mcp.Description("Nutrient names the food must be a good source of, e.g. ['Iron, Fe', 'Vitamin C']."),
+			mcp.Items(map[string]any{"type": "string"}),
+		),
+		mcp.WithNumber("percentile",
+			mcp.Description("Percentile rank (0-100) each nutrient must reach. Defaults to the server's notable percentile."),
+			mcp.Min(0),
+			mcp.Max(100),
+		),
+		withPagingParams(defaultHighestInNutrientResults),
+		withCategoryParam(),
+		mcp.WithOutputSchema[query.MultiNutrientSourcesResponse](),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+
+	s.addTool(multiNutrientTool, s.handleMultiNutrientSources)
+
+	// Weighted multi-nutrient ranking tool
+	compositeRankingTool := mcp.NewTool("rank_foods_by_nutrients",
+		mcp.WithDescription("Rank USDA foundation foods by a weighted sum of several nutrients. Each amount is divided by the dataset's largest amount of that nutrient before weighting so units don't dominate; a negative weight favors foods low in a nutrient. For 'high in protein and low in fat' pass nutrients ['Protein', 'Total lipid (fat)'] with weights [1, -1]. Each food includes its per-nutrient breakdown so the ranking is explainable. Foods lacking any listed nutrient are excluded."),
+		mcp.WithArray("nutrients",
+			mcp.Required(),
+			mcp.Description("Nutrient names to combine, e.g. ['Protein', 'Total lipid (fat)']."),
+			mcp.Items(map[string]any{"type": "string"}),
+		),
+		mcp.WithArray("weights",
+			mcp.Description("Weight of each nutrient, in the same order as 'nutrients'. Defaults to 1 for every nutrient."),
+			mcp.Items(map[string]any{"type": "number"}),
+		),
+		withPagingParams(defaultHighestInNutrientResults),
+		withCategoryParam(),
+		mcp.WithOutputSchema[query.CompositeRankingResponse](),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+
+	s.addTool(compositeRankingTool, s.handleRankFoodsByNutrients)
+
+	// Nutrient density per 200 kcal tool
+	per200KcalTool := mcp.NewTool("nutrients_per_200kcal",
+		mcp.WithDescription("Search USDA foundation foods by name and return every nutrient of the best match scaled to 200 kcal instead of 100 g, the standard nutrient-density basis for comparing foods of different energy density. Better matches that don't report Energy in kcal are skipped and listed in 'excludedFoods'; if no match reports it the call fails with an explanation."),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.MinLength(1),
+			mcp.Description("Food name to search for, e.g. 'spinach, raw'."),
+		),
+		withCategoryParam(),
+		mcp.WithOutputSchema[query.EnergyBasisResponse](),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+
+	s.addTool(per200KcalTool, s.handleNutrientsPer200Kcal)
+
+	// Macro ratio tool
+	macroTool := mcp.NewTool("macro_percentages",
+		mcp.WithDescription("Search USDA foundation foods by name and return the best match's macronutrient breakdown as percentages of calories: protein and carbohydrate at 4 kcal/g and fat at 9 kcal/g, summing to 100%. Ideal for macro-ratio pie charts. Macros the food doesn't report count as zero and are listed in 'missingMacros'."),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.MinLength(1),
+			mcp.Description("Food name to search for, e.g. 'salmon, atlantic, raw'."),
+		),
+		withCategoryParam(),
+		mcp.WithOutputSchema[query.MacroPercentagesResponse](),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+
+	s.addTool(macroTool, s.handleMacroPercentages)
+
+	// Nutrient feature vector tool
+	vectorTool := mcp.NewTool("nutrient_vectors",
+		mcp.WithDescription("Search USDA foundation foods by name and return each match's nutrients as a fixed-order numeric vector, ready for embedding or ML pipelines. 'nutrientOrder' names the columns and 'nutrientUnits' their units; amounts are normalized to grams (kcal for energy) and missing nutrients are 0. The column order always follows the requested nutrient list."),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.MinLength(1),
+			mcp.Description("Food name to search for, e.g. 'apple'."),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Maximum number of foods to return (default: %d, max: %d)", s.searchLimits.Default, s.searchLimits.Max)),
+			mcp.DefaultNumber(float64(s.searchLimits.Default)),
+			mcp.Min(1),
+			mcp.Max(float64(s.searchLimits.Max)),
+		),
+		mcp.WithArray("nutrients",
+			mcp.Description("Nutrient names defining the vector columns, in order. Defaults to the standard set of essential nutrients."),
+			mcp.Items(map[string]any{"type": "string"}),
+			mcp.DefaultArray(query.DefaultNutrients),
+		),
+		withCategoryParam(),
+		mcp.WithOutputSchema[query.NutrientVectorsResponse](),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+
+	s.addTool(vectorTool, s.handleNutrientVectors)
+
+	// Dataset units tool
+	unitsTool := mcp.NewTool("list_units",
+		mcp.WithDescription("List every distinct nutrient unit (such as 'g', 'mg', 'kcal') and portion measure unit (such as 'cup', 'tbsp') used in the USDA foundation foods dataset, each with how many nutrient entries or portions use it, most used first. Useful for building unit-aware clients."),
+		mcp.WithOutputSchema[query.UnitListResponse](),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+
+	s.addTool(unitsTool, s.handleListUnits)
+
+	// Nutrient unit conversion tool
+	convertUnitTool := mcp.NewTool("convert_nutrient_unit",
+		mcp.WithDescription("Convert a nutrient amount between compatible units so values reported in different units can be compared: mass units (g, mg, µg) with each other and energy units (kcal, kJ) with each other. International Units (IU) are nutrient-specific and only convert to or from mass when 'nutrient' names vitamin A, D or E. Incompatible units, such as kcal to mg, return an error."),
+		mcp.WithNumber("amount",
+			mcp.Required(),
+			mcp.Description("Amount to convert."),
+		),
+		mcp.WithString("from_unit",
+			mcp.Required(),
+			mcp.Description("Unit of the amount: g, mg, µg (or mcg), kcal, kJ or IU."),
+		),
+		mcp.WithString("to_unit",
+			mcp.Required(),
+			mcp.Description("Unit to convert to: g, mg, µg (or mcg), kcal, kJ or IU."),
+		),
+		mcp.WithString("nutrient",
+			mcp.Description("Nutrient the amount belongs to, e.g. 'Vitamin D (D2 + D3), International Units'. Required for IU conversions, which use the nutrient's IU definition."),
+		),
+		mcp.WithOutputSchema[query.UnitConversionResponse](),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+
+	s.addTool(convertUnitTool, s.handleConvertNutrientUnit)
+
+	// Dataset metadata tool
+	datasetInfoTool := mcp.NewTool("get_dataset_info",
+		mcp.WithDescription("Describe the loaded USDA foundation foods dataset: the file it was loaded from, its release date, the number of foods and distinct food categories, and the most recent publication date across its foods. Useful for checking which USDA release is loaded, for example after a reload."),
+		mcp.WithOutputSchema[query.DatasetInfoResponse](),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+
+	s.addTool(datasetInfoTool, s.handleDatasetInfo)
+
+	// Batch FDC ID lookup tool
+	fdcIdsTool := mcp.NewTool("get_foods_by_fdc_ids",
+		mcp.WithDescription("Fetch several USDA foundation foods by FDC ID in one call, with their complete details. Foods are returned in the order requested, each once; IDs that match no food are listed in 'notFound' instead of failing the call."),
+		mcp.WithArray("fdcIds",
+			mcp.Required(),
+			mcp.Description(fmt.Sprintf("FDC IDs of the foods to fetch (1 to %d).", maxFdcIdLookups)),
+			mcp.Items(map[string]any{"type": "number"}),
+			mcp.MinItems(1),
+			mcp.MaxItems(maxFdcIdLookups),
+		),
+		mcp.WithOutputSchema[query.FoodsByFdcIdsResponse](),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+
+	s.addTool(fdcIdsTool, s.handleGetFoodsByFdcIds)
+
+	// Legacy NDB number lookup tool
+	ndbNumberTool := mcp.NewTool("get_foods_by_ndb_number",
+		mcp.WithDescription("Return the USDA foundation foods with a legacy NDB number (the identifier used by the retired USDA National Nutrient Database and by older datasets) instead of an FDC ID. NDB numbers are less unique than FDC IDs, so every food sharing the number is returned; prefer FDC IDs when you have one."),
+		mcp.WithNumber("ndbNumber",
+			mcp.Required(),
+			mcp.Description("NDB number of the food, e.g. 1077."),
+		),
+		mcp.WithOutputSchema[query.NdbNumberLookupResponse](),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+
+	s.addTool(ndbNumberTool, s.handleGetFoodsByNdbNumber)
+
+	// Nutrient distribution tool
+	histogramTool := mcp.NewTool("nutrient_histogram",
+		mcp.WithDescription("Return how one nutrient is distributed across USDA foundation foods as a histogram: the number of foods whose amount falls into each of equal-width buckets between the smallest and largest amount. Amounts are normalized to grams (or kcal for energy) before bucketing. Useful for charts such as how sodium is distributed."),
+		mcp.WithString("nutrient",
+			mcp.Required(),
+			mcp.MinLength(1),
+			mcp.Description("Exact nutrient name (case-insensitive), e.g. 'Sodium, Na' or 'Protein'."),
+		),
+		mcp.WithNumber("buckets",
+			mcp.Description("Number of buckets (default: 10, max: 50)."),
+			mcp.DefaultNumber(10),
+			mcp.Min(1),
+			mcp.Max(50),
+		),
+		withCategoryParam(),
+		mcp.WithOutputSchema[query.NutrientHistogramResponse](),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+
+	s.addTool(histogramTool, s.handleNutrientHistogram)
+
+	// Nutrient time-series across dataset releases tool
+	historyTool := mcp.NewTool("nutrient_history",
+		mcp.WithDescription("Return a food's amount of one nutrient in every loaded USDA dataset release, tagged by release date and oldest first, for charting how a value changed across snapshots. Releases where the food or nutrient is absent have a null amount. Only the current release is available unless the server loads history datasets."),
+		mcp.WithNumber("fdcId",
+			mcp.Required(),
+			mcp.Description("FDC ID of the food."),
+		),
+		mcp.WithString("nutrient",
+			mcp.Required(),
+			mcp.MinLength(1),
+			mcp.Description("Exact nutrient name (case-insensitive), e.g. 'Protein' or 'Calcium, Ca'."),
+		),
+		mcp.WithOutputSchema[query.NutrientHistoryResponse](),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+
+	s.addTool(historyTool, s.handleNutrientHistory)
 }
 
-// ServeHTTP serves the MCP server over HTTP with authentication
-func (s *Server) ServeHTTP(addr string) error {
-	// Create a custom HTTP handler that includes authentication
-	mux := http.NewServeMux()
+// withCategoryParam declares the optional category filter shared by the search tools
+func withCategoryParam() mcp.ToolOption {
+	return mcp.WithString("category",
+		mcp.Description("Optional USDA food category (e.g. 'Dairy and Egg Products') to restrict results to. Matching is case-insensitive and treats '&' and 'and' alike. Pass 'all' to search every category when the server is scoped to a default category."),
+	)
+}
 
-	// Health endpoint (no auth required)
-	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodGet {
-			w.WriteHeader(http.StatusMethodNotAllowed)
+// withNotableParams declares the notable-nutrient filter shared by the simplified search tools
+func withNotableParams() mcp.ToolOption {
+	return func(t *mcp.Tool) {
+		mcp.WithBoolean("notable_only",
+			mcp.Description("Return only nutrients the food is a notable source of, i.e. where it ranks at or above 'notable_percentile' among all foods reporting that nutrient. Hides trace amounts."),
+			mcp.DefaultBool(false),
+		)(t)
+		mcp.WithNumber("notable_percentile",
+			mcp.Description("Percentile rank (0-100) a nutrient must reach when notable_only is true, e.g. 75 for the top 25%. Defaults to the server's configured threshold."),
+			mcp.Min(0),
+			mcp.Max(100),
+		)(t)
+	}
+}
+
+// withMergeParams declares the duplicate-nutrient merge options shared by the simplified search tools
+func withMergeParams() mcp.ToolOption {
+	return func(t *mcp.Tool) {
+		mcp.WithBoolean("merge_duplicate_nutrients",
+			mcp.Description("Collapse nutrients a food lists more than once (e.g. via different derivations) into a single entry."),
+			mcp.DefaultBool(false),
+		)(t)
+		mcp.WithString("merge_strategy",
+			mcp.Description("How duplicates are merged: 'most_data_points' (default) keeps the entry backed by the most data points, 'average' averages their amounts."),
+			mcp.Enum(query.MergeMostDataPoints, query.MergeAverage),
+		)(t)
+	}
+}
+
+// withEnergyUnitParam declares the energy unit option shared by the simplified search tools
+func withEnergyUnitParam() mcp.ToolOption {
+	return mcp.WithString("energy_unit",
+		mcp.Description("Which Energy entry to return: 'kcal' (default), 'kj' for kilojoules, or 'both'. Foods the USDA only reports Atwater energy for keep those kcal entries either way."),
+		mcp.Enum(query.EnergyUnitKcal, query.EnergyUnitKJ, query.EnergyUnitBoth),
+		mcp.DefaultString(query.EnergyUnitKcal),
+	)
+}
+
+// withMinScoreParam declares the min_score option shared by the name search tools
+func withMinScoreParam() mcp.ToolOption {
+	return mcp.WithNumber("min_score",
+		mcp.Description("Drop matches with a relevance score below this threshold before the limit is applied (default: 0, keep all). Exact-prefix matches typically score 500 or more; substring-only matches score around 100 or less."),
+		mcp.Min(0),
+		mcp.DefaultNumber(0),
+	)
+}
+
+// withPreparationParam declares the preparation option shared by the name search tools
+func withPreparationParam() mcp.ToolOption {
+	return mcp.WithString("preparation",
+		mcp.Description("Rank raw or cooked forms first: 'raw' boosts foods described as raw and demotes cooked ones (roasted, boiled, grilled, ...), 'cooked' does the opposite. 'any' (default) ranks as usual."),
+		mcp.Enum(query.PreparationAny, query.PreparationRaw, query.PreparationCooked),
+		mcp.DefaultString(query.PreparationAny),
+	)
+}
+
+// withFuzzyParam declares the fuzzy option shared by the name search tools
+func withFuzzyParam() mcp.ToolOption {
+	return mcp.WithBoolean("fuzzy",
+		mcp.Description("Tolerate typos: a word within one edit (two for words longer than 5 letters) of a description word counts as a weak match, so 'brocolli' finds 'Broccoli, raw'. Default: false."),
+		mcp.DefaultBool(false),
+	)
+}
+
+// withNearestParam declares the return_nearest_on_empty option shared by the name search tools
+func withNearestParam() mcp.ToolOption {
+	return mcp.WithBoolean("return_nearest_on_empty",
+		mcp.Description("When nothing matches the name, return the most similar foods by spelling instead of an empty result. Such foods are flagged with 'fuzzyFallback: true'."),
+		mcp.DefaultBool(false),
+	)
+}
+
+// withRoundingParam declares the round_gram_weights option shared by the name search tools
+func withRoundingParam() mcp.ToolOption {
+	return mcp.WithBoolean("round_gram_weights",
+		mcp.Description(fmt.Sprintf("Round portion gram weights to %d decimal for display (default: true). Scaling always uses the full-precision weights.", query.GramWeightDecimals)),
+		mcp.DefaultBool(true),
+	)
+}
+
+// withPagingParams declares the offset and limit arguments shared by the aggregate tools. A positive
+// defaultLimit is the limit when none is passed; otherwise it defaults to the aggregate result limit.
+func withPagingParams(defaultLimit int) mcp.ToolOption {
+	return func(t *mcp.Tool) {
+		mcp.WithNumber("offset",
+			mcp.Description("Number of rows to skip before returning results, for paging (default: 0). Pass the response's 'page.nextOffset' to fetch the next page."),
+			mcp.DefaultNumber(0),
+			mcp.Min(0),
+		)(t)
+
+		if defaultLimit > 0 {
+			mcp.WithNumber("limit",
+				mcp.Description(fmt.Sprintf("Maximum number of rows to return (default: %d). Capped by the server's aggregate result limit.", defaultLimit)),
+				mcp.DefaultNumber(float64(defaultLimit)),
+				mcp.Min(1),
+			)(t)
 			return
 		}
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of rows to return. Capped by the server's aggregate result limit."),
+			mcp.Min(1),
+		)(t)
+	}
+}
 
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"status": "healthy",
-		})
-	})
+// errNegativeOffset is the tool error for a negative 'offset' argument on any paging tool
+var errNegativeOffset = errors.New("Parameter 'offset' must not be negative")
+
+// aggregatePage resolves the offset and limit arguments of an aggregate tool, capping the limit and
+// rejecting a negative offset. A positive defaultLimit is used when no limit is passed; otherwise the cap is.
+func (s *Server) aggregatePage(request mcp.CallToolRequest, defaultLimit int) (int, int, error) {
+	offset := request.GetInt("offset", 0)
+	if offset < 0 {
+		return 0, 0, errNegativeOffset
+	}
+
+	if defaultLimit <= 0 {
+		defaultLimit = s.aggregateMaxResults
+	}
+
+	limit := request.GetInt("limit", defaultLimit)
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	if limit > s.aggregateMaxResults {
+		limit = s.aggregateMaxResults
+	}
+
+	return offset, limit, nil
+}
+
+// aggregateRows trims rows to the requested page, returning the page, its row count and its paging
+// metadata. Aggregate handlers ask the engine for every row (limit 0) so the whole ranking can be paged
+// through, then return one page of it here.
+func aggregateRows[T any](rows []T, offset, limit int) ([]T, int, *query.PageInfo) {
+	rows, page := query.Paginate(rows, offset, limit)
+	return rows, len(rows), &page
+}
+
+// searchOptions builds the engine search options from the shared search tool arguments
+func (s *Server) searchOptions(request mcp.CallToolRequest) query.SearchOptions {
+	category := strings.TrimSpace(request.GetString("category", ""))
+	switch {
+	case strings.EqualFold(category, "all"):
+		category = ""
+	case category == "":
+		category = s.defaultCategory
+	}
+
+	return query.SearchOptions{
+		Category:          category,
+		ReferenceFdcId:    request.GetInt("relative_to_reference", 0),
+		IncludeSequence:   request.GetBool("include_sequence", false),
+		IncludeDerivation: request.GetBool("include_derivation", false),
+		EnergyUnit:        request.GetString("energy_unit", query.EnergyUnitKcal),
+
+		MergeDuplicateNutrients: request.GetBool("merge_duplicate_nutrients", false),
+		MergeStrategy:           request.GetString("merge_strategy", query.MergeMostDataPoints),
+
+		NotableOnly:       request.GetBool("notable_only", false),
+		NotablePercentile: request.GetFloat("notable_percentile", s.notablePercentile),
+
+		ReturnNearestOnEmpty: request.GetBool("return_nearest_on_empty", false),
+		IncludeScores:        request.GetBool("include_scores", false),
+		IncludeHighlights:    request.GetBool("include_highlights", false),
+		Exact:                request.GetBool("exact", false),
+		MinScore:             request.GetFloat("min_score", 0),
+		SortBy:               request.GetString("sort_by", query.SortByRelevance),
+		Preparation:          request.GetString("preparation", query.PreparationAny),
+		Fuzzy:                request.GetBool("fuzzy", false),
+	}
+}
+
+// found reports the found flag for a successful search with count results
+func (s *Server) found(count int) bool {
+	if s.foundSemantics == FoundSemanticsQuerySucceeded {
+		return true
+	}
+	return count > 0
+}
+
+// structuredResult returns both structured content and a JSON text fallback for maximum compatibility
+func (s *Server) structuredResult(ctx context.Context, handler string, response any) (*mcp.CallToolResult, error) {
+	responseJSON, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		s.log.ErrorContext(ctx, handler+": Failed to marshal response", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal response: %v", err)), nil
+	}
+
+	s.log.DebugContext(ctx, handler+": Returning structured result",
+		"response_size", len(responseJSON))
+
+	return mcp.NewToolResultStructured(response, string(responseJSON)), nil
+}
+
+// ServeHTTP serves the MCP server over HTTP with authentication until ctx is canceled, then drains
+// in-flight requests for up to the shutdown timeout
+func (s *Server) ServeHTTP(ctx context.Context, addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	s.log.Info("Starting MCP server", "addr", addr, "tls", s.tlsEnabled())
+	return s.serve(ctx, &http.Server{Handler: s.Handler()}, listener)
+}
+
+// Handler builds the HTTP handler exposing the health, MCP and admin endpoints
+func (s *Server) Handler() http.Handler {
+	// Create a custom HTTP handler that includes authentication
+	mux := http.NewServeMux()
+
+	// Liveness and readiness endpoints (no auth required)
+	mux.HandleFunc("/health", s.handleHealth)
+	mux.HandleFunc("/ready", s.handleReady)
 
 	// Create the streamable HTTP server
 	streamableServer := server.NewStreamableHTTPServer(
 		s.mcpServer,
 		server.WithEndpointPath("/mcp"),
-		server.WithStateLess(true), // Stateless for better OpenAI compatibility
+		server.WithStateLess(s.stateless), // Stateless by default for better OpenAI compatibility
 	)
 
 	// MCP endpoint with authentication and enhanced error logging
 	mux.HandleFunc("/mcp", func(w http.ResponseWriter, r *http.Request) {
+		// Tag the call with the client's request ID, or a new one, so its log lines can be correlated
+		requestID := r.Header.Get(config.RequestIDHeader)
+		if !config.ValidRequestID(requestID) {
+			requestID = config.NewRequestID()
+		}
+		w.Header().Set(config.RequestIDHeader, requestID)
+		ctx := config.WithRequestID(r.Context(), requestID)
+		r = r.WithContext(ctx)
+
 		// Add recovery middleware for better error handling
 		defer func() {
 			if recovery := recover(); recovery != nil {
-				s.log.Error("MCP endpoint panic recovered",
+				s.log.ErrorContext(ctx, "MCP endpoint panic recovered",
 					"panic", recovery,
 					"method", r.Method,
 					"url", r.URL.String(),
@@ -179,19 +1243,35 @@ func (s *Server) ServeHTTP(addr string) error {
 			}
 		}()
 
-		s.log.Debug("MCP request received",
+		s.log.DebugContext(ctx, "MCP request received",
 			"method", r.Method,
 			"url", r.URL.String(),
 			"content_type", r.Header.Get("Content-Type"),
 			"content_length", r.ContentLength,
 			"remote_addr", r.RemoteAddr)
 
+		// Reject clients that exceed their rate limit before doing any work for them
+		if s.rateLimiter != nil {
+			if allowed, wait := s.rateLimiter.allow(s.rateLimitKey(r)); !allowed {
+				w.Header().Set("Retry-After", retryAfterSeconds(wait))
+				w.WriteHeader(http.StatusTooManyRequests)
+				w.Write([]byte("Too Many Requests"))
+				s.log.WarnContext(ctx, "Rate limited MCP request", "remote_addr", r.RemoteAddr, "retry_after", wait)
+				return
+			}
+		}
+
 		// Check authentication for all non-health endpoints
 		if !s.auth.IsAuthorized(r) {
 			s.auth.SetUnauthorizedHeaders(w)
 			w.WriteHeader(http.StatusUnauthorized)
 			w.Write([]byte("Unauthorized"))
-			s.log.Warn("Unauthorized MCP request", "remote_addr", r.RemoteAddr, "user_agent", r.UserAgent())
+			s.log.WarnContext(ctx, "Unauthorized MCP request", "remote_addr", r.RemoteAddr, "user_agent", r.UserAgent())
+			return
+		}
+
+		// Refuse bodies over the size limit before the transport buffers them
+		if !s.readLimitedBody(w, r) {
 			return
 		}
 
@@ -201,74 +1281,207 @@ func (s *Server) ServeHTTP(addr string) error {
 		// Forward to the streamable HTTP server
 		streamableServer.ServeHTTP(recorder, r)
 
-		s.log.Debug("MCP response sent",
+		s.log.DebugContext(ctx, "MCP response sent",
 			"status_code", recorder.statusCode,
 			"response_size", recorder.bytesWritten,
 			"content_type", recorder.Header().Get("Content-Type"))
 	})
 
-	s.log.Info("Starting MCP server", "addr", addr)
-	return http.ListenAndServe(addr, mux)
+	// Normalization refresh endpoint with authentication
+	mux.HandleFunc("/refresh-normalization", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		if !s.auth.IsAuthorized(r) {
+			s.auth.SetUnauthorizedHeaders(w)
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte("Unauthorized"))
+			s.log.Warn("Unauthorized normalization refresh request", "remote_addr", r.RemoteAddr, "user_agent", r.UserAgent())
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := s.queryEngine.RefreshNormalization(r.Context()); err != nil {
+			s.log.Error("Normalization refresh failed", "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"status": "error",
+				"error":  err.Error(),
+			})
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "refreshed",
+		})
+	})
+
+	return mux
 }
 
 // ServeStdio serves the MCP server over stdio (no auth required for local use)
 func (s *Server) ServeStdio() error {
 	s.log.Info("Starting MCP server in stdio mode")
+	s.enableStdioCorrelationLogging()
 	return server.ServeStdio(s.mcpServer)
 }
 
 func (s *Server) handleFoodSearch(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	s.log.Debug("handleFoodSearch: Starting tool call",
+	s.log.DebugContext(ctx, "handleFoodSearch: Starting tool call",
 		"arguments", request.GetArguments())
 
 	// Extract arguments
 	name, err := request.RequireString("name")
 	if err != nil {
-		s.log.Warn("handleFoodSearch: Missing 'name' parameter", "error", err)
+		s.log.WarnContext(ctx, "handleFoodSearch: Missing 'name' parameter", "error", err)
 		return mcp.NewToolResultError(fmt.Sprintf("Missing required parameter 'name': %v", err)), nil
 	}
 
 	// Validate minimum lengths
 	if len(name) < 1 {
-		s.log.Warn("handleFoodSearch: Invalid 'name' parameter", "length", len(name))
+		s.log.WarnContext(ctx, "handleFoodSearch: Invalid 'name' parameter", "length", len(name))
 		return mcp.NewToolResultError("Parameter 'name' must be at least 1 character long"), nil
 	}
 
-	limitFloat := request.GetFloat("limit", 3.0)
-	limit := int(limitFloat)
-	if limit <= 0 {
-		limit = 3
+	limit := s.searchLimits.Clamp(int(request.GetFloat("limit", float64(s.searchLimits.Default))))
+
+	perServing := request.GetBool("per_serving", false)
+	portionLabel := request.GetString("portion_label", "")
+
+	verbosity := request.GetString("verbosity", query.VerbosityFull)
+	if err := query.ValidateVerbosity(verbosity); err != nil {
+		s.log.WarnContext(ctx, "handleFoodSearch: Invalid 'verbosity' parameter", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid parameter 'verbosity': %v", err)), nil
 	}
-	if limit > 10 {
-		limit = 10
+
+	if err := query.ValidateSortBy(request.GetString("sort_by", query.SortByRelevance)); err != nil {
+		s.log.WarnContext(ctx, "handleFoodSearch: Invalid 'sort_by' parameter", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid parameter 'sort_by': %v", err)), nil
 	}
 
-	s.log.Debug("MCP search_foundation_foods_by_name called",
+	offset := request.GetInt("offset", 0)
+	if offset < 0 {
+		s.log.WarnContext(ctx, "handleFoodSearch: Invalid 'offset' parameter", "error", errNegativeOffset)
+		return mcp.NewToolResultError(errNegativeOffset.Error()), nil
+	}
+
+	s.log.DebugContext(ctx, "MCP search_foundation_foods_by_name called",
 		"name", name,
-		"limit", limit)
+		"offset", offset,
+		"limit", limit,
+		"per_serving", perServing,
+		"portion_label", portionLabel,
+		"verbosity", verbosity)
+
+	// A cursor resumes an earlier search where its previous page ended
+	opts := s.searchOptions(request)
+	opts.Offset = offset
+	sessionID, cursorsEnabled := s.cursorSessionID(ctx)
+	if token := request.GetString("cursor", ""); token != "" {
+		if !cursorsEnabled {
+			return mcp.NewToolResultError("Parameter 'cursor' is only supported when the server runs in stateful mode"), nil
+		}
+
+		cursor, err := s.cursors.resolve(token, sessionID)
+		if err != nil {
+			s.log.WarnContext(ctx, "handleFoodSearch: Invalid 'cursor' parameter", "error", err)
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid parameter 'cursor': %v", err)), nil
+		}
+		if cursor.name != name {
+			return mcp.NewToolResultError("Invalid parameter 'cursor': it was issued for a different 'name'"), nil
+		}
+
+		limit, opts = cursor.limit, cursor.opts
+	}
 
 	// Execute search
-	products, err := s.queryEngine.SearchFoodsByName(ctx, name, limit)
+	ctx, partial := s.budgetContext(ctx)
+	products, total, err := s.queryEngine.SearchFoodsByNameWithTotal(ctx, name, limit, opts)
 	if err != nil {
-		s.log.Error("Food search failed", "error", err)
+		s.log.ErrorContext(ctx, "Food search failed", "error", err)
 		return mcp.NewToolResultError(fmt.Sprintf("Search failed: %v", err)), nil
 	}
 
+	var nextCursor string
+	if cursorsEnabled {
+		nextCursor, err = s.nextSearchCursor(sessionID, name, limit, opts, len(products), total)
+		if err != nil {
+			s.log.ErrorContext(ctx, "handleFoodSearch: Failed to issue cursor", "error", err)
+			return mcp.NewToolResultError(fmt.Sprintf("Search failed: %v", err)), nil
+		}
+	}
+
+	// Scale nutrients to a serving when requested
+	if perServing {
+		for i, product := range products {
+			portion, err := query.SelectPortion(product, portionLabel)
+			if err != nil {
+				s.log.DebugContext(ctx, "handleFoodSearch: Leaving food per 100 g", "fdcId", product.FdcId, "reason", err)
+				continue
+			}
+			products[i] = query.ScaleFoodToPortion(product, *portion)
+		}
+	}
+
+	// Trim nested nutrient metadata to the requested verbosity
+	for i, product := range products {
+		products[i] = query.ApplyVerbosity(product, verbosity)
+	}
+
+	// Round gram weights for display only, after scaling used them at full precision
+	if request.GetBool("round_gram_weights", true) {
+		for i, product := range products {
+			products[i] = query.RoundFoodGramWeights(product)
+		}
+	}
+
+	// Project down to the requested fields when the caller asked for a whitelist
+	if responseFields := request.GetStringSlice("response_fields", nil); len(responseFields) > 0 {
+		knownFields, unknownFields := splitFoodFields(responseFields)
+		if len(unknownFields) > 0 {
+			s.log.WarnContext(ctx, "handleFoodSearch: Ignoring unknown response fields", "fields", unknownFields)
+		}
+
+		projected, err := projectFoods(products, knownFields)
+		if err != nil {
+			s.log.ErrorContext(ctx, "handleFoodSearch: Failed to project response fields", "error", err)
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to project response fields: %v", err)), nil
+		}
+
+		return s.structuredResult(ctx, "handleFoodSearch", ProjectedSearchProductsResponse{
+			Found:         s.found(len(projected)),
+			Count:         len(projected),
+			Products:      projected,
+			Total:         total,
+			UnknownFields: unknownFields,
+			NextCursor:    nextCursor,
+
+			PartialDueToBudget: partial(),
+		})
+	}
+
 	// Prepare structured response
 	response := query.SearchProductsResponse{
-		Found:    len(products) > 0,
-		Count:    len(products),
-		Products: products,
+		Found:      s.found(len(products)),
+		Count:      len(products),
+		Products:   products,
+		Total:      total,
+		NextCursor: nextCursor,
+
+		PartialDueToBudget: partial(),
 	}
 
 	// Create fallback text for backwards compatibility
 	responseJSON, err := json.MarshalIndent(response, "", "  ")
 	if err != nil {
-		s.log.Error("handleFoodSearch: Failed to marshal response", "error", err)
+		s.log.ErrorContext(ctx, "handleFoodSearch: Failed to marshal response", "error", err)
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal response: %v", err)), nil
 	}
 
-	s.log.Debug("handleFoodSearch: Returning structured result",
+	s.log.DebugContext(ctx, "handleFoodSearch: Returning structured result",
 		"found", response.Found,
 		"count", response.Count,
 		"response_size", len(responseJSON))
@@ -278,54 +1491,97 @@ func (s *Server) handleFoodSearch(ctx context.Context, request mcp.CallToolReque
 }
 
 func (s *Server) handleSimplifiedFoodSearch(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	s.log.Debug("handleSimplifiedFoodSearch: Starting tool call",
+	s.log.DebugContext(ctx, "handleSimplifiedFoodSearch: Starting tool call",
 		"arguments", request.GetArguments())
 
 	// Extract arguments
 	name, err := request.RequireString("name")
 	if err != nil {
-		s.log.Warn("handleSimplifiedFoodSearch: Missing 'name' parameter", "error", err)
+		s.log.WarnContext(ctx, "handleSimplifiedFoodSearch: Missing 'name' parameter", "error", err)
 		return mcp.NewToolResultError(fmt.Sprintf("Missing required parameter 'name': %v", err)), nil
 	}
 
 	// Validate minimum lengths
 	if len(name) < 1 {
-		s.log.Warn("handleSimplifiedFoodSearch: Invalid 'name' parameter", "length", len(name))
+		s.log.WarnContext(ctx, "handleSimplifiedFoodSearch: Invalid 'name' parameter", "length", len(name))
 		return mcp.NewToolResultError("Parameter 'name' must be at least 1 character long"), nil
 	}
 
-	limitFloat := request.GetFloat("limit", 3.0)
-	limit := int(limitFloat)
-	if limit <= 0 {
-		limit = 3
+	limit := s.searchLimits.Clamp(int(request.GetFloat("limit", float64(s.searchLimits.Default))))
+
+	portionGrams, portionLabel, err := simplifiedPortionArgs(request)
+	if err != nil {
+		s.log.WarnContext(ctx, "handleSimplifiedFoodSearch: Invalid portion parameters", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid portion parameters: %v", err)), nil
+	}
+
+	if err := query.ValidateMergeStrategy(request.GetString("merge_strategy", "")); err != nil {
+		s.log.WarnContext(ctx, "handleSimplifiedFoodSearch: Invalid 'merge_strategy' parameter", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid parameter 'merge_strategy': %v", err)), nil
 	}
-	if limit > 10 {
-		limit = 10
+
+	if err := query.ValidateEnergyUnit(request.GetString("energy_unit", "")); err != nil {
+		s.log.WarnContext(ctx, "handleSimplifiedFoodSearch: Invalid 'energy_unit' parameter", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid parameter 'energy_unit': %v", err)), nil
 	}
 
 	// Extract nutrients_to_include parameter
 	nutrientsToInclude := request.GetStringSlice("nutrients_to_include", query.DefaultNutrients)
 
-	s.log.Debug("MCP search_foundation_foods_and_return_nutrients called",
+	s.log.DebugContext(ctx, "MCP search_foundation_foods_and_return_nutrients called",
 		"name", name,
 		"limit", limit,
 		"nutrients_count", len(nutrientsToInclude))
 
 	// Execute simplified search
-	response, err := s.queryEngine.SearchFoodsByNameSimplified(ctx, name, limit, nutrientsToInclude)
+	ctx, partial := s.budgetContext(ctx)
+	response, err := s.queryEngine.SearchFoodsByNameSimplified(ctx, name, limit, nutrientsToInclude, s.searchOptions(request))
 	if err != nil {
-		s.log.Error("Simplified food search failed", "error", err)
+		s.log.ErrorContext(ctx, "Simplified food search failed", "error", err)
 		return mcp.NewToolResultError(fmt.Sprintf("Search failed: %v", err)), nil
 	}
+	response.Found = s.found(response.Count)
+	response.PartialDueToBudget = partial()
+
+	// Scale before rounding so the portion's full-precision gram weight is used
+	s.scaleSimplifiedResponse(ctx, response, portionGrams, portionLabel)
+
+	if request.GetBool("round_gram_weights", true) {
+		query.RoundSimplifiedGramWeights(response)
+	}
+
+	// Render the nutrient data as a markdown table or CSV rows in the text content when requested
+	switch request.GetString("format", formatJSON) {
+	case formatMarkdown:
+		markdown := renderSimplifiedMarkdown(response)
+
+		s.log.DebugContext(ctx, "handleSimplifiedFoodSearch: Returning markdown result",
+			"count", response.Count,
+			"response_size", len(markdown))
+
+		return mcp.NewToolResultStructured(response, markdown), nil
+	case formatCSV:
+		csvText, err := renderSimplifiedCSV(response)
+		if err != nil {
+			s.log.ErrorContext(ctx, "handleSimplifiedFoodSearch: Failed to render CSV", "error", err)
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to render CSV: %v", err)), nil
+		}
+
+		s.log.DebugContext(ctx, "handleSimplifiedFoodSearch: Returning CSV result",
+			"count", response.Count,
+			"response_size", len(csvText))
+
+		return mcp.NewToolResultStructured(response, csvText), nil
+	}
 
 	// Create fallback text for backwards compatibility
 	responseJSON, err := json.MarshalIndent(response, "", "  ")
 	if err != nil {
-		s.log.Error("handleSimplifiedFoodSearch: Failed to marshal response", "error", err)
+		s.log.ErrorContext(ctx, "handleSimplifiedFoodSearch: Failed to marshal response", "error", err)
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal response: %v", err)), nil
 	}
 
-	s.log.Debug("handleSimplifiedFoodSearch: Returning structured result",
+	s.log.DebugContext(ctx, "handleSimplifiedFoodSearch: Returning structured result",
 		"found", response.Found,
 		"count", response.Count,
 		"foods_count", len(response.Foods),
@@ -336,55 +1592,98 @@ func (s *Server) handleSimplifiedFoodSearch(ctx context.Context, request mcp.Cal
 }
 
 func (s *Server) handleSimplifiedFixedFoodSearch(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	s.log.Debug("handleSimplifiedFixedFoodSearch: Starting tool call",
+	s.log.DebugContext(ctx, "handleSimplifiedFixedFoodSearch: Starting tool call",
 		"arguments", request.GetArguments())
 
 	// Extract arguments
 	name, err := request.RequireString("name")
 	if err != nil {
-		s.log.Warn("handleSimplifiedFixedFoodSearch: Missing 'name' parameter", "error", err)
+		s.log.WarnContext(ctx, "handleSimplifiedFixedFoodSearch: Missing 'name' parameter", "error", err)
 		return mcp.NewToolResultError(fmt.Sprintf("Missing required parameter 'name': %v", err)), nil
 	}
 
 	// Validate minimum lengths
 	if len(name) < 1 {
-		s.log.Warn("handleSimplifiedFixedFoodSearch: Invalid 'name' parameter", "length", len(name))
+		s.log.WarnContext(ctx, "handleSimplifiedFixedFoodSearch: Invalid 'name' parameter", "length", len(name))
 		return mcp.NewToolResultError("Parameter 'name' must be at least 1 character long"), nil
 	}
 
-	limitFloat := request.GetFloat("limit", 3.0)
-	limit := int(limitFloat)
-	if limit <= 0 {
-		limit = 3
+	limit := s.searchLimits.Clamp(int(request.GetFloat("limit", float64(s.searchLimits.Default))))
+
+	portionGrams, portionLabel, err := simplifiedPortionArgs(request)
+	if err != nil {
+		s.log.WarnContext(ctx, "handleSimplifiedFixedFoodSearch: Invalid portion parameters", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid portion parameters: %v", err)), nil
+	}
+
+	if err := query.ValidateMergeStrategy(request.GetString("merge_strategy", "")); err != nil {
+		s.log.WarnContext(ctx, "handleSimplifiedFixedFoodSearch: Invalid 'merge_strategy' parameter", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid parameter 'merge_strategy': %v", err)), nil
 	}
-	if limit > 10 {
-		limit = 10
+
+	if err := query.ValidateEnergyUnit(request.GetString("energy_unit", "")); err != nil {
+		s.log.WarnContext(ctx, "handleSimplifiedFixedFoodSearch: Invalid 'energy_unit' parameter", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid parameter 'energy_unit': %v", err)), nil
 	}
 
 	// Always use default nutrients - no customization allowed
 	nutrientsToInclude := query.DefaultNutrients
 
-	s.log.Debug("MCP search_foundation_foods_and_return_nutrients_simplified called",
+	s.log.DebugContext(ctx, "MCP search_foundation_foods_and_return_nutrients_simplified called",
 		"name", name,
 		"limit", limit,
 		"nutrients_count", len(nutrientsToInclude),
 		"fixed_nutrients", true)
 
 	// Execute simplified search with fixed default nutrients
-	response, err := s.queryEngine.SearchFoodsByNameSimplified(ctx, name, limit, nutrientsToInclude)
+	ctx, partial := s.budgetContext(ctx)
+	response, err := s.queryEngine.SearchFoodsByNameSimplified(ctx, name, limit, nutrientsToInclude, s.searchOptions(request))
 	if err != nil {
-		s.log.Error("Simplified fixed food search failed", "error", err)
+		s.log.ErrorContext(ctx, "Simplified fixed food search failed", "error", err)
 		return mcp.NewToolResultError(fmt.Sprintf("Search failed: %v", err)), nil
 	}
+	response.Found = s.found(response.Count)
+	response.PartialDueToBudget = partial()
+
+	// Scale before rounding so the portion's full-precision gram weight is used
+	s.scaleSimplifiedResponse(ctx, response, portionGrams, portionLabel)
+
+	if request.GetBool("round_gram_weights", true) {
+		query.RoundSimplifiedGramWeights(response)
+	}
+
+	// Render the nutrient data as a markdown table or CSV rows in the text content when requested
+	switch request.GetString("format", formatJSON) {
+	case formatMarkdown:
+		markdown := renderSimplifiedMarkdown(response)
+
+		s.log.DebugContext(ctx, "handleSimplifiedFixedFoodSearch: Returning markdown result",
+			"count", response.Count,
+			"response_size", len(markdown))
+
+		return mcp.NewToolResultStructured(response, markdown), nil
+	case formatCSV:
+		csvText, err := renderSimplifiedCSV(response)
+		if err != nil {
+			s.log.ErrorContext(ctx, "handleSimplifiedFixedFoodSearch: Failed to render CSV", "error", err)
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to render CSV: %v", err)), nil
+		}
+
+		s.log.DebugContext(ctx, "handleSimplifiedFixedFoodSearch: Returning CSV result",
+			"count", response.Count,
+			"response_size", len(csvText))
+
+		return mcp.NewToolResultStructured(response, csvText), nil
+	}
 
 	// Create fallback text for backwards compatibility
 	responseJSON, err := json.MarshalIndent(response, "", "  ")
 	if err != nil {
-		s.log.Error("handleSimplifiedFixedFoodSearch: Failed to marshal response", "error", err)
+		s.log.ErrorContext(ctx, "handleSimplifiedFixedFoodSearch: Failed to marshal response", "error", err)
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal response: %v", err)), nil
 	}
 
-	s.log.Debug("handleSimplifiedFixedFoodSearch: Returning structured result",
+	s.log.DebugContext(ctx, "handleSimplifiedFixedFoodSearch: Returning structured result",
 		"found", response.Found,
 		"count", response.Count,
 		"foods_count", len(response.Foods),
@@ -2,13 +2,22 @@ package mcpgo
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
 	"testing"
 
+	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/noot-app/foundation-foods-mcp-server/internal/auth"
 	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
 	"github.com/noot-app/foundation-foods-mcp-server/internal/query"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewServer(t *testing.T) {
@@ -36,18 +45,419 @@ func TestNewServer(t *testing.T) {
 	})
 }
 
+func TestServer_searchOptions(t *testing.T) {
+	logger := config.NewTestLogger(io.Discard, "debug")
+	mockEngine := &testQueryEngine{data: &query.FoundationFoodsData{}}
+	authenticator := auth.NewBearerTokenAuth("test-token")
+
+	server := NewServer(mockEngine, authenticator, logger,
+		WithDefaultCategory("Vegetables and Vegetable Products"))
+
+	testCases := []struct {
+		name     string
+		args     map[string]any
+		expected string
+	}{
+		{
+			name:     "scopes to the default category",
+			args:     map[string]any{"name": "beans"},
+			expected: "Vegetables and Vegetable Products",
+		},
+		{
+			name:     "category all escapes the default scope",
+			args:     map[string]any{"name": "beans", "category": "all"},
+			expected: "",
+		},
+		{
+			name:     "explicit category overrides the default",
+			args:     map[string]any{"name": "beans", "category": "Legumes and Legume Products"},
+			expected: "Legumes and Legume Products",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request := mcp.CallToolRequest{}
+			request.Params.Arguments = tc.args
+
+			_, err := server.handleFoodSearch(context.Background(), request)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, mockEngine.lastSearchOptions.Category)
+		})
+	}
+}
+
+func TestServer_handleSimplifiedFoodSearch_EnergyUnit(t *testing.T) {
+	logger := config.NewTestLogger(io.Discard, "debug")
+	mockEngine := &testQueryEngine{data: &query.FoundationFoodsData{}}
+	server := NewServer(mockEngine, auth.NewBearerTokenAuth("test-token"), logger)
+
+	call := func(args map[string]any) *mcp.CallToolResult {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := server.handleSimplifiedFoodSearch(context.Background(), request)
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("defaults to kcal", func(t *testing.T) {
+		result := call(map[string]any{"name": "almonds"})
+
+		assert.False(t, result.IsError)
+		assert.Equal(t, query.EnergyUnitKcal, mockEngine.lastSearchOptions.EnergyUnit)
+	})
+
+	t.Run("passes the requested unit to the engine", func(t *testing.T) {
+		for _, unit := range []string{query.EnergyUnitKJ, query.EnergyUnitBoth} {
+			result := call(map[string]any{"name": "almonds", "energy_unit": unit})
+
+			assert.False(t, result.IsError)
+			assert.Equal(t, unit, mockEngine.lastSearchOptions.EnergyUnit)
+		}
+	})
+
+	t.Run("rejects unknown units", func(t *testing.T) {
+		result := call(map[string]any{"name": "almonds", "energy_unit": "calories"})
+
+		assert.True(t, result.IsError)
+	})
+}
+
+func TestServer_HealthEndpoint(t *testing.T) {
+	logger := config.NewTestLogger(io.Discard, "debug")
+	mockEngine := &testQueryEngine{data: &query.FoundationFoodsData{
+		FoundationFoods: []query.FoundationFood{{Description: "Milk, whole", FdcId: 1}},
+	}}
+	handler := NewServer(mockEngine, auth.NewBearerTokenAuth("test-token"), logger).Handler()
+
+	t.Run("GET returns healthy status", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"status":"healthy"`)
+	})
+
+	t.Run("HEAD returns status without body", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodHead, "/health", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Body.String())
+	})
+
+	t.Run("other methods are rejected", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/health", nil))
+
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+
+	t.Run("stays live when the engine can't answer searches", func(t *testing.T) {
+		brokenEngine := &testQueryEngine{data: &query.FoundationFoodsData{}}
+		brokenHandler := NewServer(brokenEngine, auth.NewBearerTokenAuth("test-token"), logger).Handler()
+
+		rec := httptest.NewRecorder()
+		brokenHandler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"status":"healthy"`)
+	})
+}
+
+func TestServer_ReadyEndpoint(t *testing.T) {
+	logger := config.NewTestLogger(io.Discard, "debug")
+	mockEngine := &testQueryEngine{data: &query.FoundationFoodsData{
+		FoundationFoods: []query.FoundationFood{{Description: "Milk, whole", FdcId: 1}},
+	}}
+	handler := NewServer(mockEngine, auth.NewBearerTokenAuth("test-token"), logger).Handler()
+
+	t.Run("GET returns ready status", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"status":"ready"`)
+		assert.Contains(t, rec.Body.String(), `"probe_results":1`)
+	})
+
+	t.Run("HEAD returns status without body", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodHead, "/ready", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Body.String())
+	})
+
+	t.Run("other methods are rejected", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/ready", nil))
+
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+
+	t.Run("unsearchable engine is not ready", func(t *testing.T) {
+		brokenEngine := &testQueryEngine{data: &query.FoundationFoodsData{}}
+		brokenHandler := NewServer(brokenEngine, auth.NewBearerTokenAuth("test-token"), logger).Handler()
+
+		rec := httptest.NewRecorder()
+		brokenHandler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"status":"not_ready"`)
+		assert.Contains(t, rec.Body.String(), `"probe_results":0`)
+	})
+
+	t.Run("engine health failure is not ready", func(t *testing.T) {
+		unloadedEngine := &testQueryEngine{healthErr: errors.New("no data loaded")}
+		unloadedHandler := NewServer(unloadedEngine, auth.NewBearerTokenAuth("test-token"), logger,
+			WithHealthProbeQuery("")).Handler()
+
+		rec := httptest.NewRecorder()
+		unloadedHandler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"error":"no data loaded"`)
+	})
+
+	t.Run("empty probe query only checks data is loaded", func(t *testing.T) {
+		brokenEngine := &testQueryEngine{data: &query.FoundationFoodsData{}}
+		probelessHandler := NewServer(brokenEngine, auth.NewBearerTokenAuth("test-token"), logger,
+			WithHealthProbeQuery("")).Handler()
+
+		rec := httptest.NewRecorder()
+		probelessHandler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NotContains(t, rec.Body.String(), "probe_results")
+	})
+}
+
+func TestServer_RefreshNormalizationEndpoint(t *testing.T) {
+	logger := config.NewTestLogger(io.Discard, "debug")
+	mockEngine := &testQueryEngine{data: &query.FoundationFoodsData{}}
+	server := NewServer(mockEngine, auth.NewBearerTokenAuth("test-token"), logger)
+	handler := server.Handler()
+
+	t.Run("rejects unauthenticated requests", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/refresh-normalization", nil)
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Equal(t, 0, mockEngine.refreshCount)
+	})
+
+	t.Run("rejects non-POST requests", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/refresh-normalization", nil)
+		req.Header.Set("Authorization", "Bearer test-token")
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+
+	t.Run("refreshes normalization when authorized", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/refresh-normalization", nil)
+		req.Header.Set("Authorization", "Bearer test-token")
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, 1, mockEngine.refreshCount)
+		assert.Contains(t, rec.Body.String(), "refreshed")
+	})
+}
+
+func TestServer_AggregateResultCap(t *testing.T) {
+	logger := config.NewTestLogger(io.Discard, "debug")
+	nutrients := make([]query.NutrientCategoryDifference, 5)
+	for i := range nutrients {
+		nutrients[i] = query.NutrientCategoryDifference{Name: fmt.Sprintf("Nutrient %d", i)}
+	}
+
+	mockEngine := &testQueryEngine{data: &query.FoundationFoodsData{}}
+	server := NewServer(mockEngine, auth.NewBearerTokenAuth("test-token"), logger,
+		WithAggregateMaxResults(2))
+
+	callTool := func(args map[string]any) *query.FoodVsCategoryResponse {
+		mockEngine.categoryComparison = &query.FoodVsCategoryResponse{
+			FdcId:     1,
+			Nutrients: append([]query.NutrientCategoryDifference(nil), nutrients...),
+		}
+
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+
+		result, err := server.handleFoodVsCategory(context.Background(), request)
+		require.NoError(t, err)
+		require.False(t, result.IsError)
+
+		response, ok := result.StructuredContent.(*query.FoodVsCategoryResponse)
+		require.True(t, ok)
+		return response
+	}
+
+	t.Run("caps rows at the configured maximum", func(t *testing.T) {
+		response := callTool(map[string]any{"fdcId": 1, "limit": 100})
+
+		require.Len(t, response.Nutrients, 2)
+		require.NotNil(t, response.Page)
+		assert.Equal(t, 5, response.Page.Total)
+		assert.Equal(t, 2, response.Page.Limit)
+		assert.True(t, response.Page.HasMore)
+		require.NotNil(t, response.Page.NextOffset)
+		assert.Equal(t, 2, *response.Page.NextOffset)
+	})
+
+	t.Run("pages through remaining rows", func(t *testing.T) {
+		response := callTool(map[string]any{"fdcId": 1, "offset": 4})
+
+		require.Len(t, response.Nutrients, 1)
+		assert.Equal(t, "Nutrient 4", response.Nutrients[0].Name)
+		assert.False(t, response.Page.HasMore)
+	})
+
+	t.Run("ranking tools page past the cap", func(t *testing.T) {
+		foods := make([]query.NutrientRankedFood, 5)
+		for i := range foods {
+			foods[i] = query.NutrientRankedFood{FdcId: i + 1}
+		}
+		mockEngine.nutrientRanking = &query.NutrientRankingResponse{Nutrient: "Protein", Foods: foods}
+
+		rank := func(args map[string]any) *query.NutrientRankingResponse {
+			request := mcp.CallToolRequest{}
+			request.Params.Arguments = args
+
+			result, err := server.handleFindFoodsHighestInNutrient(context.Background(), request)
+			require.NoError(t, err)
+			require.False(t, result.IsError)
+
+			response, ok := result.StructuredContent.(*query.NutrientRankingResponse)
+			require.True(t, ok)
+			return response
+		}
+
+		first := rank(map[string]any{"nutrient": "Protein", "limit": 100})
+		require.Len(t, first.Foods, 2)
+		assert.Equal(t, 2, first.Count)
+		require.NotNil(t, first.Page)
+		assert.Equal(t, 5, first.Page.Total)
+		require.NotNil(t, first.Page.NextOffset)
+
+		last := rank(map[string]any{"nutrient": "Protein", "offset": 4})
+		require.Len(t, last.Foods, 1)
+		assert.Equal(t, 5, last.Foods[0].FdcId)
+		assert.False(t, last.Page.HasMore)
+	})
+
+	t.Run("ingredient search honors the offset", func(t *testing.T) {
+		foods := make([]query.IngredientMatch, 5)
+		for i := range foods {
+			foods[i] = query.IngredientMatch{FdcId: i + 1, MatchedIngredient: "tomato"}
+		}
+		mockEngine.ingredientSearch = &query.IngredientSearchResponse{Ingredient: "tomato", Foods: foods}
+
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]any{"ingredient": "tomato", "offset": 2}
+
+		result, err := server.handleFindFoodsContainingIngredient(context.Background(), request)
+		require.NoError(t, err)
+		require.False(t, result.IsError)
+
+		response, ok := result.StructuredContent.(*query.IngredientSearchResponse)
+		require.True(t, ok)
+		require.Len(t, response.Foods, 2)
+		assert.Equal(t, 3, response.Foods[0].FdcId)
+		assert.True(t, response.Found)
+		require.NotNil(t, response.Page)
+		assert.Equal(t, 5, response.Page.Total)
+		require.NotNil(t, response.Page.NextOffset)
+		assert.Equal(t, 4, *response.Page.NextOffset)
+	})
+
+	t.Run("every paging tool rejects a negative offset", func(t *testing.T) {
+		handlers := map[string]struct {
+			handle func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error)
+			args   map[string]any
+		}{
+			"food_vs_category":                  {server.handleFoodVsCategory, map[string]any{"fdcId": 1}},
+			"rank_by_protein_density":           {server.handleRankByProteinDensity, map[string]any{}},
+			"top_nutrient_differences":          {server.handleTopNutrientDifferences, map[string]any{"fdcIdA": 1, "fdcIdB": 2}},
+			"find_foods_highest_in_nutrient":    {server.handleFindFoodsHighestInNutrient, map[string]any{"nutrient": "Protein"}},
+			"find_foods_with_nutrient_in_range": {server.handleFindFoodsWithNutrientInRange, map[string]any{"nutrient": "Protein", "min": 1.0}},
+			"multi_nutrient_sources":            {server.handleMultiNutrientSources, map[string]any{"nutrients": []any{"Protein"}}},
+			"rank_foods_by_nutrients":           {server.handleRankFoodsByNutrients, map[string]any{"nutrients": []any{"Protein"}}},
+			"find_foods_containing_ingredient":  {server.handleFindFoodsContainingIngredient, map[string]any{"ingredient": "tomato"}},
+			"search_foundation_foods_by_name":   {server.handleFoodSearch, map[string]any{"name": "milk"}},
+		}
+
+		for name, tool := range handlers {
+			t.Run(name, func(t *testing.T) {
+				request := mcp.CallToolRequest{}
+				request.Params.Arguments = tool.args
+				request.Params.Arguments.(map[string]any)["offset"] = -1
+
+				result, err := tool.handle(context.Background(), request)
+
+				require.NoError(t, err)
+				require.True(t, result.IsError)
+				text, ok := result.Content[0].(mcp.TextContent)
+				require.True(t, ok)
+				assert.Equal(t, "Parameter 'offset' must not be negative", text.Text)
+			})
+		}
+	})
+}
+
 // testQueryEngine is a mock implementation for testing
 type testQueryEngine struct {
-	data *query.FoundationFoodsData
+	data              *query.FoundationFoodsData
+	lastSearchOptions query.SearchOptions
+	lastNutrients     []string
+	refreshCount      int
+	healthErr         error
+
+	categoryComparison *query.FoodVsCategoryResponse
+	simplified         *query.SimplifiedNutrientResponse
+	nutrientRanking    *query.NutrientRankingResponse
+	ingredientSearch   *query.IngredientSearchResponse
+	lastVectorLimit    int
+	searchCount        int
+}
+
+func (t *testQueryEngine) SearchFoodsByName(ctx context.Context, query string, limit int, opts query.SearchOptions) ([]query.FoundationFood, error) {
+	t.lastSearchOptions = opts
+	t.searchCount++
+
+	foods := t.data.FoundationFoods
+	if opts.Offset >= len(foods) {
+		return nil, nil
+	}
+	foods = foods[opts.Offset:]
+	if limit > 0 && len(foods) > limit {
+		foods = foods[:limit]
+	}
+	// Like the real engine, return a fresh slice so handlers can't rewrite the fixture
+	return slices.Clone(foods), nil
 }
 
-func (t *testQueryEngine) SearchFoodsByName(ctx context.Context, query string, limit int) ([]query.FoundationFood, error) {
-	return t.data.FoundationFoods, nil
+func (t *testQueryEngine) SearchFoodsByNameWithTotal(ctx context.Context, name string, limit int, opts query.SearchOptions) ([]query.FoundationFood, int, error) {
+	foods, err := t.SearchFoodsByName(ctx, name, limit, opts)
+	return foods, len(t.data.FoundationFoods), err
 }
 
-func (t *testQueryEngine) SearchFoodsByNameSimplified(ctx context.Context, query string, limit int, nutrientsToInclude []string) (*query.SimplifiedNutrientResponse, error) {
-	// Return nil for now to avoid type errors during development
-	return nil, nil
+func (t *testQueryEngine) SearchFoodsByNameSimplified(ctx context.Context, name string, limit int, nutrientsToInclude []string, opts query.SearchOptions) (*query.SimplifiedNutrientResponse, error) {
+	t.lastSearchOptions = opts
+	t.lastNutrients = nutrientsToInclude
+	if t.simplified != nil {
+		return t.simplified, nil
+	}
+	return &query.SimplifiedNutrientResponse{Foods: []query.SimplifiedFood{}}, nil
 }
 
 func (t *testQueryEngine) GetFoodByFdcId(ctx context.Context, fdcId int) (*query.FoundationFood, error) {
@@ -60,5 +470,393 @@ func (t *testQueryEngine) GetFoodByFdcId(ctx context.Context, fdcId int) (*query
 }
 
 func (t *testQueryEngine) Health(ctx context.Context) error {
+	return t.healthErr
+}
+
+func (t *testQueryEngine) SearchCacheStats() query.SearchCacheStats {
+	return query.SearchCacheStats{}
+}
+
+func (t *testQueryEngine) CompareFoodToCategory(ctx context.Context, fdcId int) (*query.FoodVsCategoryResponse, error) {
+	return t.categoryComparison, nil
+}
+
+func (t *testQueryEngine) ResolveFoods(ctx context.Context, names []string, opts query.SearchOptions) (*query.ResolveFoodsResponse, error) {
+	return nil, nil
+}
+
+func (t *testQueryEngine) RefreshNormalization(ctx context.Context) error {
+	t.refreshCount++
 	return nil
 }
+
+func (t *testQueryEngine) NutrientsForHouseholdPortion(ctx context.Context, fdcId int, measure string) (*query.HouseholdPortionResponse, error) {
+	return nil, nil
+}
+
+func (t *testQueryEngine) NutrientsWithUpperLimits(ctx context.Context, fdcId int, measure string) (*query.UpperLimitsResponse, error) {
+	return nil, nil
+}
+
+func (t *testQueryEngine) DailyIntakeSummary(ctx context.Context, entries []query.IntakeEntry, demographic string, opts query.SearchOptions) (*query.DailyIntakeSummaryResponse, error) {
+	return nil, nil
+}
+
+func (t *testQueryEngine) NetCarbs(ctx context.Context, fdcId int, measure string, subtractSugarAlcohols bool) (*query.NetCarbsResponse, error) {
+	return nil, nil
+}
+
+func (t *testQueryEngine) SearchWithAlternatives(ctx context.Context, name string, alternatives int, opts query.SearchOptions) (*query.SearchWithAlternativesResponse, error) {
+	return nil, nil
+}
+
+func (t *testQueryEngine) MacroPercentages(ctx context.Context, name string, opts query.SearchOptions) (*query.MacroPercentagesResponse, error) {
+	return nil, nil
+}
+
+func (t *testQueryEngine) ListUnits(ctx context.Context) (*query.UnitListResponse, error) {
+	return nil, nil
+}
+
+func (t *testQueryEngine) GetFoodByNdbNumber(ctx context.Context, ndbNumber int) (*query.NdbNumberLookupResponse, error) {
+	return nil, nil
+}
+
+func (t *testQueryEngine) DatasetInfo(ctx context.Context) (*query.DatasetInfoResponse, error) {
+	return nil, nil
+}
+
+func (t *testQueryEngine) NutrientVectors(ctx context.Context, name string, limit int, nutrientNames []string, opts query.SearchOptions) (*query.NutrientVectorsResponse, error) {
+	t.lastVectorLimit = limit
+	return &query.NutrientVectorsResponse{}, nil
+}
+
+func (t *testQueryEngine) NutrientsPer200Kcal(ctx context.Context, name string, opts query.SearchOptions) (*query.EnergyBasisResponse, error) {
+	return nil, nil
+}
+
+func (t *testQueryEngine) GetFoodWithInputs(ctx context.Context, fdcId int, depth int) (*query.FoodWithInputsResponse, error) {
+	return nil, nil
+}
+
+func (t *testQueryEngine) GetFoodsByFdcIds(ctx context.Context, fdcIds []int) (*query.FoodsByFdcIdsResponse, error) {
+	return nil, nil
+}
+
+func (t *testQueryEngine) ExplainFoodSearch(ctx context.Context, name, description string, fdcId int, opts query.SearchOptions) (*query.SearchExplanation, error) {
+	return nil, nil
+}
+
+func (t *testQueryEngine) GetFoodInputFoods(ctx context.Context, fdcId int) (*query.FoodInputFoodsResponse, error) {
+	return nil, nil
+}
+
+func (t *testQueryEngine) NutrientHistogram(ctx context.Context, nutrientName string, buckets int, opts query.SearchOptions) (*query.NutrientHistogramResponse, error) {
+	return nil, nil
+}
+
+func (t *testQueryEngine) FindFoodsByNutrient(ctx context.Context, nutrientName string, limit int, opts query.SearchOptions) (*query.NutrientRankingResponse, error) {
+	if t.nutrientRanking == nil {
+		return nil, nil
+	}
+
+	response := *t.nutrientRanking
+	if limit > 0 && len(response.Foods) > limit {
+		response.Foods = response.Foods[:limit]
+	}
+	response.Count = len(response.Foods)
+	return &response, nil
+}
+
+func (t *testQueryEngine) FindFoodsByNutrientRange(ctx context.Context, nutrientName string, minAmount, maxAmount *float64, limit int, opts query.SearchOptions) (*query.NutrientRangeResponse, error) {
+	return nil, nil
+}
+
+func (t *testQueryEngine) MultiNutrientSources(ctx context.Context, nutrientNames []string, percentile float64, limit int, opts query.SearchOptions) (*query.MultiNutrientSourcesResponse, error) {
+	return nil, nil
+}
+
+func (t *testQueryEngine) RankFoodsByNutrients(ctx context.Context, nutrientNames []string, weights []float64, limit int, opts query.SearchOptions) (*query.CompositeRankingResponse, error) {
+	return nil, nil
+}
+
+func (t *testQueryEngine) RankByProteinDensity(ctx context.Context, limit int, opts query.SearchOptions) (*query.ProteinDensityResponse, error) {
+	return nil, nil
+}
+
+func (t *testQueryEngine) NutrientHistory(ctx context.Context, fdcId int, nutrientName string) (*query.NutrientHistoryResponse, error) {
+	return nil, nil
+}
+
+func (t *testQueryEngine) TopNutrientDifferences(ctx context.Context, fdcIdA, fdcIdB int, limit int, rankBy string) (*query.NutrientDifferencesResponse, error) {
+	return nil, nil
+}
+
+func (t *testQueryEngine) CompareFoods(ctx context.Context, fdcIds []int, nutrientNames []string) (*query.CompareFoodsResponse, error) {
+	return nil, nil
+}
+
+func (t *testQueryEngine) FindFoodsContainingIngredient(ctx context.Context, ingredient string, limit int, opts query.SearchOptions) (*query.IngredientSearchResponse, error) {
+	if t.ingredientSearch == nil {
+		return nil, nil
+	}
+
+	response := *t.ingredientSearch
+	if limit > 0 && len(response.Foods) > limit {
+		response.Foods = response.Foods[:limit]
+	}
+	response.Count = len(response.Foods)
+	return &response, nil
+}
+
+func (t *testQueryEngine) CanonicalizeFoodName(ctx context.Context, name string, minConfidence float64, opts query.SearchOptions) (*query.ResolvedFood, error) {
+	return nil, nil
+}
+
+func TestServer_StatelessMode(t *testing.T) {
+	logger := config.NewTestLogger(io.Discard, "debug")
+	initialize := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"test","version":"1.0.0"}}}`
+
+	testCases := []struct {
+		name          string
+		opts          []Option
+		expectSession bool
+	}{
+		{name: "stateless by default", opts: nil, expectSession: false},
+		{name: "stateful when disabled", opts: []Option{WithStateless(false)}, expectSession: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockEngine := &testQueryEngine{data: &query.FoundationFoodsData{}}
+			handler := NewServer(mockEngine, auth.NewBearerTokenAuth("test-token"), logger, tc.opts...).Handler()
+
+			req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(initialize))
+			req.Header.Set("Authorization", "Bearer test-token")
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			if tc.expectSession {
+				assert.NotEmpty(t, rec.Header().Get("Mcp-Session-Id"))
+			} else {
+				assert.Empty(t, rec.Header().Get("Mcp-Session-Id"))
+			}
+		})
+	}
+}
+
+func TestServer_FoundSemantics(t *testing.T) {
+	logger := config.NewTestLogger(io.Discard, "debug")
+
+	testCases := []struct {
+		name          string
+		opts          []Option
+		expectedFound bool
+	}{
+		{name: "has_results by default", opts: nil, expectedFound: false},
+		{name: "has_results", opts: []Option{WithFoundSemantics(FoundSemanticsHasResults)}, expectedFound: false},
+		{name: "query_succeeded", opts: []Option{WithFoundSemantics(FoundSemanticsQuerySucceeded)}, expectedFound: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockEngine := &testQueryEngine{data: &query.FoundationFoodsData{}}
+			server := NewServer(mockEngine, auth.NewBearerTokenAuth("test-token"), logger, tc.opts...)
+
+			request := mcp.CallToolRequest{}
+			request.Params.Arguments = map[string]any{"name": "xyz123nonexistent"}
+
+			result, err := server.handleFoodSearch(context.Background(), request)
+
+			require.NoError(t, err)
+			response, ok := result.StructuredContent.(query.SearchProductsResponse)
+			require.True(t, ok)
+			assert.Equal(t, 0, response.Count)
+			assert.Equal(t, tc.expectedFound, response.Found)
+		})
+	}
+}
+
+func TestServer_SearchOffset(t *testing.T) {
+	logger := config.NewTestLogger(io.Discard, "debug")
+	mockEngine := &testQueryEngine{data: &query.FoundationFoodsData{
+		FoundationFoods: []query.FoundationFood{
+			{Description: "Milk, whole", FdcId: 1},
+			{Description: "Milk, lowfat", FdcId: 2},
+			{Description: "Milk, nonfat", FdcId: 3},
+		},
+	}}
+	server := NewServer(mockEngine, auth.NewBearerTokenAuth("test-token"), logger)
+
+	search := func(t *testing.T, offset int) query.SearchProductsResponse {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]any{"name": "milk", "limit": 2, "offset": offset}
+
+		result, err := server.handleFoodSearch(context.Background(), request)
+
+		require.NoError(t, err)
+		require.False(t, result.IsError)
+		response, ok := result.StructuredContent.(query.SearchProductsResponse)
+		require.True(t, ok)
+		return response
+	}
+
+	t.Run("skips results before the offset and reports the total", func(t *testing.T) {
+		response := search(t, 2)
+
+		assert.Equal(t, 2, mockEngine.lastSearchOptions.Offset)
+		require.Len(t, response.Products, 1)
+		assert.Equal(t, 3, response.Products[0].FdcId)
+		assert.Equal(t, 3, response.Total)
+	})
+
+	t.Run("returns an empty page past the end", func(t *testing.T) {
+		response := search(t, 5)
+
+		assert.Empty(t, response.Products)
+		assert.Equal(t, 0, response.Count)
+		assert.Equal(t, 3, response.Total)
+	})
+}
+
+func TestServer_NamesOnlySearch(t *testing.T) {
+	logger := config.NewTestLogger(io.Discard, "debug")
+	mockEngine := &testQueryEngine{data: &query.FoundationFoodsData{
+		FoundationFoods: []query.FoundationFood{
+			{
+				Description:   "Milk, whole",
+				FdcId:         1,
+				FoodCategory:  query.FoodCategory{Description: "Dairy and Egg Products"},
+				FoodNutrients: []query.FoodNutrient{{Nutrient: query.Nutrient{Name: "Protein", UnitName: "g"}, Amount: 3.3}},
+			},
+		},
+	}}
+	server := NewServer(mockEngine, auth.NewBearerTokenAuth("test-token"), logger)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]any{"name": "milk", "category": "Dairy and Egg Products"}
+
+	result, err := server.handleNamesOnlyFoodSearch(context.Background(), request)
+
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.Equal(t, "Dairy and Egg Products", mockEngine.lastSearchOptions.Category)
+
+	response, ok := result.StructuredContent.(NamesOnlySearchResponse)
+	require.True(t, ok)
+	assert.True(t, response.Found)
+	assert.Equal(t, []FoodNameMatch{{FdcId: 1, Description: "Milk, whole", FoodCategory: "Dairy and Egg Products"}}, response.Foods)
+
+	// Nutrients are left out of the payload entirely
+	encoded, err := json.Marshal(response.Foods[0])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"fdcId":1,"description":"Milk, whole","foodCategory":"Dairy and Egg Products"}`, string(encoded))
+}
+
+func TestServer_NutrientsToInclude(t *testing.T) {
+	logger := config.NewTestLogger(io.Discard, "debug")
+	mockEngine := &testQueryEngine{data: &query.FoundationFoodsData{}}
+	server := NewServer(mockEngine, auth.NewBearerTokenAuth("test-token"), logger)
+
+	// Arguments decoded from JSON arrive as []any
+	t.Run("passes the requested nutrients through", func(t *testing.T) {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]any{"name": "milk", "nutrients_to_include": []any{"Protein", "Calcium, Ca"}}
+
+		result, err := server.handleSimplifiedFoodSearch(context.Background(), request)
+
+		require.NoError(t, err)
+		require.False(t, result.IsError)
+		assert.Equal(t, []string{"Protein", "Calcium, Ca"}, mockEngine.lastNutrients)
+	})
+
+	t.Run("falls back to the default nutrients", func(t *testing.T) {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]any{"name": "milk"}
+
+		_, err := server.handleSimplifiedFoodSearch(context.Background(), request)
+
+		require.NoError(t, err)
+		assert.Equal(t, query.DefaultNutrients, mockEngine.lastNutrients)
+	})
+
+	t.Run("fixed tool always uses the default nutrients", func(t *testing.T) {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]any{"name": "milk", "nutrients_to_include": []any{"Protein"}}
+
+		_, err := server.handleSimplifiedFixedFoodSearch(context.Background(), request)
+
+		require.NoError(t, err)
+		assert.Equal(t, query.DefaultNutrients, mockEngine.lastNutrients)
+	})
+}
+
+func TestServer_RoundGramWeights(t *testing.T) {
+	logger := config.NewTestLogger(io.Discard, "debug")
+	mockEngine := &testQueryEngine{data: &query.FoundationFoodsData{
+		FoundationFoods: []query.FoundationFood{{
+			Description: "Almonds, raw",
+			FdcId:       1,
+			FoodNutrients: []query.FoodNutrient{
+				{Nutrient: query.Nutrient{Name: "Protein", UnitName: "g"}, Amount: 10},
+			},
+			FoodPortions: []query.FoodPortion{
+				{MeasureUnit: query.MeasureUnit{Name: "oz"}, GramWeight: 30.46},
+			},
+		}},
+	}}
+	server := NewServer(mockEngine, auth.NewBearerTokenAuth("test-token"), logger)
+
+	search := func(t *testing.T, arguments map[string]any) query.FoundationFood {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = arguments
+
+		result, err := server.handleFoodSearch(context.Background(), request)
+
+		require.NoError(t, err)
+		response, ok := result.StructuredContent.(query.SearchProductsResponse)
+		require.True(t, ok)
+		require.Len(t, response.Products, 1)
+		return response.Products[0]
+	}
+
+	t.Run("rounds displayed gram weights but scales at full precision", func(t *testing.T) {
+		food := search(t, map[string]any{"name": "almonds", "per_serving": true})
+
+		require.NotNil(t, food.ServingPortion)
+		assert.Equal(t, 30.5, food.ServingPortion.GramWeight)
+		assert.Equal(t, 30.5, food.FoodPortions[0].GramWeight)
+		assert.InDelta(t, 3.046, food.FoodNutrients[0].Amount, 0.000001)
+	})
+
+	t.Run("keeps full precision when disabled", func(t *testing.T) {
+		food := search(t, map[string]any{"name": "almonds", "round_gram_weights": false})
+
+		assert.Equal(t, 30.46, food.FoodPortions[0].GramWeight)
+	})
+
+	// Rounding must not leak into the engine's data
+	assert.Equal(t, 30.46, mockEngine.data.FoundationFoods[0].FoodPortions[0].GramWeight)
+}
+
+func TestServer_ServerInfo(t *testing.T) {
+	logger := config.NewTestLogger(io.Discard, "debug")
+	mockEngine := &testQueryEngine{data: &query.FoundationFoodsData{}}
+	server := NewServer(mockEngine, auth.NewBearerTokenAuth("test-token"), logger,
+		WithServerInfo("Acme Nutrition", "2.3.4"))
+
+	message := server.mcpServer.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"test","version":"1.0.0"}}}`))
+	encoded, err := json.Marshal(message)
+	require.NoError(t, err)
+
+	var response struct {
+		Result mcp.InitializeResult `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(encoded, &response))
+
+	assert.Equal(t, "Acme Nutrition", response.Result.ServerInfo.Name)
+	assert.Equal(t, "2.3.4", response.Result.ServerInfo.Version)
+}
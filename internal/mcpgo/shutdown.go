@@ -0,0 +1,60 @@
+package mcpgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// defaultShutdownTimeout is how long ServeHTTP waits for in-flight requests once it is asked to stop
+const defaultShutdownTimeout = 10 * time.Second
+
+// WithShutdownTimeout sets how long ServeHTTP drains in-flight requests after its context is canceled before
+// closing the remaining connections. Non-positive values keep the default.
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(s *Server) {
+		if timeout > 0 {
+			s.shutdownTimeout = timeout
+		}
+	}
+}
+
+// serve runs httpServer on listener until ctx is canceled, then shuts it down gracefully. It returns nil when
+// every in-flight request finished within the shutdown timeout.
+func (s *Server) serve(ctx context.Context, httpServer *http.Server, listener net.Listener) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		if s.tlsEnabled() {
+			serveErr <- httpServer.ServeTLS(listener, s.tlsCertFile, s.tlsKeyFile)
+			return
+		}
+		serveErr <- httpServer.Serve(listener)
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	s.log.Info("Shutting down MCP server, draining in-flight requests", "timeout", s.shutdownTimeout)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		s.log.Warn("MCP server did not drain in time, closing remaining connections", "error", err)
+		_ = httpServer.Close()
+		return fmt.Errorf("shutdown: %w", err)
+	}
+
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	s.log.Info("MCP server stopped")
+	return nil
+}
@@ -0,0 +1,94 @@
+package mcpgo
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/auth"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_GracefulShutdown(t *testing.T) {
+	logger := config.NewTestLogger(io.Discard, "debug")
+
+	// start serves a handler that blocks until release is closed, returning the URL and serve's result
+	start := func(t *testing.T, server *Server, release <-chan struct{}) (context.CancelFunc, string, <-chan error, <-chan struct{}) {
+		t.Helper()
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+
+		started := make(chan struct{})
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+			w.WriteHeader(http.StatusOK)
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() {
+			done <- server.serve(ctx, &http.Server{Handler: handler}, listener)
+		}()
+
+		return cancel, "http://" + listener.Addr().String(), done, started
+	}
+
+	t.Run("drains in-flight requests and returns nil", func(t *testing.T) {
+		server := NewServer(&testQueryEngine{}, auth.NewBearerTokenAuth("test-token"), logger)
+		release := make(chan struct{})
+		cancel, url, done, started := start(t, server, release)
+
+		status := make(chan int, 1)
+		go func() {
+			resp, err := http.Get(url)
+			if err != nil {
+				status <- 0
+				return
+			}
+			resp.Body.Close()
+			status <- resp.StatusCode
+		}()
+
+		<-started
+		cancel()
+
+		// Shutdown waits for the in-flight request instead of returning
+		select {
+		case err := <-done:
+			t.Fatalf("serve returned before the in-flight request finished: %v", err)
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		close(release)
+		assert.Equal(t, http.StatusOK, <-status)
+		assert.NoError(t, <-done)
+	})
+
+	t.Run("gives up after the shutdown timeout", func(t *testing.T) {
+		server := NewServer(&testQueryEngine{}, auth.NewBearerTokenAuth("test-token"), logger,
+			WithShutdownTimeout(50*time.Millisecond))
+		release := make(chan struct{})
+		defer close(release)
+		cancel, url, done, started := start(t, server, release)
+
+		go func() {
+			if resp, err := http.Get(url); err == nil {
+				resp.Body.Close()
+			}
+		}()
+
+		<-started
+		cancel()
+
+		err := <-done
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
@@ -0,0 +1,67 @@
+package mcpgo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/query"
+)
+
+// withSimplifiedPortionParams declares the portion_grams and portion_label options of the simplified search tools
+func withSimplifiedPortionParams() mcp.ToolOption {
+	return func(tool *mcp.Tool) {
+		mcp.WithNumber("portion_grams",
+			mcp.Description("Scale every nutrient amount from per 100 g to this many grams. Each food's 'basis' then says what the amounts refer to. Cannot be combined with 'portion_label'."),
+			mcp.Min(0),
+		)(tool)
+		mcp.WithString("portion_label",
+			mcp.Description("Scale every nutrient amount to the food's portion with this measure unit name or abbreviation, e.g. 'cup' or 'tbsp'. Foods without a matching portion stay per 100 g, with 'basis' saying so."),
+		)(tool)
+	}
+}
+
+// simplifiedPortionArgs reads and validates the portion_grams and portion_label arguments
+func simplifiedPortionArgs(request mcp.CallToolRequest) (grams float64, label string, err error) {
+	gramsArg, err := optionalFloat(request, "portion_grams")
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid parameter 'portion_grams': %w", err)
+	}
+	label = strings.TrimSpace(request.GetString("portion_label", ""))
+
+	if gramsArg != nil {
+		if *gramsArg <= 0 {
+			return 0, "", fmt.Errorf("parameter 'portion_grams' must be greater than 0")
+		}
+		if label != "" {
+			return 0, "", fmt.Errorf("parameters 'portion_grams' and 'portion_label' cannot be combined")
+		}
+		grams = *gramsArg
+	}
+
+	return grams, label, nil
+}
+
+// scaleSimplifiedResponse scales each food's nutrients to grams, or to its portion matching label, and marks
+// the basis used. It does nothing when neither is set.
+func (s *Server) scaleSimplifiedResponse(ctx context.Context, response *query.SimplifiedNutrientResponse, grams float64, label string) {
+	switch {
+	case grams > 0:
+		basis := strconv.FormatFloat(grams, 'f', -1, 64) + " g"
+		for i := range response.Foods {
+			query.ScaleSimplifiedFood(&response.Foods[i], grams, basis)
+		}
+	case label != "":
+		for i := range response.Foods {
+			portion, err := query.SelectSimplifiedPortion(response.Foods[i], label)
+			if err != nil {
+				s.log.DebugContext(ctx, "scaleSimplifiedResponse: Leaving food per 100 g", "food", response.Foods[i].Name, "reason", err)
+				response.Foods[i].Basis = query.DefaultBasis
+				continue
+			}
+			query.ScaleSimplifiedFood(&response.Foods[i], portion.GramWeight, query.PortionBasis(*portion))
+		}
+	}
+}
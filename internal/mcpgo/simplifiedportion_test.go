@@ -0,0 +1,87 @@
+package mcpgo
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/auth"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_SimplifiedPortionScaling(t *testing.T) {
+	logger := config.NewTestLogger(io.Discard, "debug")
+
+	newResponse := func() *query.SimplifiedNutrientResponse {
+		return &query.SimplifiedNutrientResponse{
+			Found: true,
+			Count: 2,
+			Foods: []query.SimplifiedFood{
+				{
+					Name:      "Milk, whole",
+					Nutrients: []query.SimplifiedNutrient{{Name: "Protein", Unit: "g", Amount: 3.3}},
+					FoodPortions: []query.SimplifiedFoodPortion{
+						{Value: 1, MeasureUnit: query.SimplifiedMeasureUnit{Name: "cup", Abbreviation: "c"}, GramWeight: 244.04},
+					},
+				},
+				{
+					Name:      "Salt, table",
+					Nutrients: []query.SimplifiedNutrient{{Name: "Sodium, Na", Unit: "mg", Amount: 38758}},
+				},
+			},
+		}
+	}
+
+	call := func(t *testing.T, arguments map[string]any) (*mcp.CallToolResult, *query.SimplifiedNutrientResponse) {
+		mockEngine := &testQueryEngine{simplified: newResponse()}
+		server := NewServer(mockEngine, auth.NewBearerTokenAuth("test-token"), logger)
+
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = arguments
+		result, err := server.handleSimplifiedFoodSearch(context.Background(), request)
+		require.NoError(t, err)
+		return result, mockEngine.simplified
+	}
+
+	t.Run("scales to portion_grams", func(t *testing.T) {
+		result, response := call(t, map[string]any{"name": "milk", "portion_grams": float64(50)})
+
+		require.False(t, result.IsError)
+		assert.InDelta(t, 1.65, response.Foods[0].Nutrients[0].Amount, 0.0001)
+		assert.Equal(t, "50 g", response.Foods[0].Basis)
+		assert.InDelta(t, 19379, response.Foods[1].Nutrients[0].Amount, 0.0001)
+	})
+
+	t.Run("scales to the portion matching portion_label", func(t *testing.T) {
+		result, response := call(t, map[string]any{"name": "milk", "portion_label": "Cup"})
+
+		require.False(t, result.IsError)
+		assert.InDelta(t, 3.3*2.4404, response.Foods[0].Nutrients[0].Amount, 0.0001)
+		assert.Equal(t, "1 cup (244 g)", response.Foods[0].Basis)
+		// Rounding for display happens after scaling
+		assert.Equal(t, 244.0, response.Foods[0].FoodPortions[0].GramWeight)
+
+		assert.Equal(t, 38758.0, response.Foods[1].Nutrients[0].Amount)
+		assert.Equal(t, query.DefaultBasis, response.Foods[1].Basis)
+	})
+
+	t.Run("leaves amounts per 100 g by default", func(t *testing.T) {
+		result, response := call(t, map[string]any{"name": "milk"})
+
+		require.False(t, result.IsError)
+		assert.Equal(t, 3.3, response.Foods[0].Nutrients[0].Amount)
+		assert.Empty(t, response.Foods[0].Basis)
+	})
+
+	t.Run("rejects invalid portion arguments", func(t *testing.T) {
+		result, _ := call(t, map[string]any{"name": "milk", "portion_grams": float64(0)})
+		assert.True(t, result.IsError)
+
+		result, _ = call(t, map[string]any{"name": "milk", "portion_grams": float64(50), "portion_label": "cup"})
+		assert.True(t, result.IsError)
+	})
+}
@@ -0,0 +1,38 @@
+package mcpgo
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// enableStdioCorrelationLogging logs each incoming JSON-RPC method and id and the id of the
+// outgoing response so stdio interactions can be correlated. It only takes effect at debug level
+// and logs through the stdio logger, which writes to stderr.
+func (s *Server) enableStdioCorrelationLogging() {
+	if !s.log.Enabled(context.Background(), slog.LevelDebug) {
+		return
+	}
+
+	s.hooks.AddBeforeAny(func(ctx context.Context, id any, method mcp.MCPMethod, message any) {
+		s.log.Debug("stdio request received",
+			"method", method,
+			"id", id)
+	})
+
+	s.hooks.AddOnSuccess(func(ctx context.Context, id any, method mcp.MCPMethod, message any, result any) {
+		s.log.Debug("stdio response sent",
+			"method", method,
+			"id", id,
+			"error", false)
+	})
+
+	s.hooks.AddOnError(func(ctx context.Context, id any, method mcp.MCPMethod, message any, err error) {
+		s.log.Debug("stdio response sent",
+			"method", method,
+			"id", id,
+			"error", true,
+			"error_message", err.Error())
+	})
+}
@@ -0,0 +1,53 @@
+package mcpgo
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/auth"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_StdioCorrelationLogging(t *testing.T) {
+	t.Run("logs request and response ids at debug level", func(t *testing.T) {
+		var logs bytes.Buffer
+		logger := config.NewTestLogger(&logs, "debug")
+		mockEngine := &testQueryEngine{data: &query.FoundationFoodsData{}}
+		s := NewServer(mockEngine, auth.NewBearerTokenAuth("test-token"), logger)
+
+		s.enableStdioCorrelationLogging()
+
+		stdin := strings.NewReader(`{"jsonrpc":"2.0","id":42,"method":"ping"}` + "\n")
+		var stdout bytes.Buffer
+
+		err := server.NewStdioServer(s.mcpServer).Listen(context.Background(), stdin, &stdout)
+		require.NoError(t, err)
+
+		assert.Contains(t, stdout.String(), `"id":42`)
+		assert.Contains(t, logs.String(), `msg="stdio request received" method=ping id=42`)
+		assert.Contains(t, logs.String(), `msg="stdio response sent" method=ping id=42 error=false`)
+	})
+
+	t.Run("does not log correlation above debug level", func(t *testing.T) {
+		var logs bytes.Buffer
+		logger := config.NewTestLogger(&logs, "info")
+		mockEngine := &testQueryEngine{data: &query.FoundationFoodsData{}}
+		s := NewServer(mockEngine, auth.NewBearerTokenAuth("test-token"), logger)
+
+		s.enableStdioCorrelationLogging()
+
+		stdin := strings.NewReader(`{"jsonrpc":"2.0","id":7,"method":"ping"}` + "\n")
+
+		err := server.NewStdioServer(s.mcpServer).Listen(context.Background(), stdin, io.Discard)
+		require.NoError(t, err)
+
+		assert.NotContains(t, logs.String(), "stdio request received")
+	})
+}
@@ -0,0 +1,46 @@
+package mcpgo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// WithStrictArgs makes tool calls fail when they pass an argument the tool doesn't define, instead of
+// silently ignoring it
+func WithStrictArgs(strict bool) Option {
+	return func(s *Server) {
+		s.strictArgs = strict
+	}
+}
+
+// strictArgsHandler wraps a tool handler so calls with unknown arguments return a tool error listing
+// the accepted parameters
+func strictArgsHandler(tool mcp.Tool, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	accepted := make([]string, 0, len(tool.InputSchema.Properties))
+	for name := range tool.InputSchema.Properties {
+		accepted = append(accepted, name)
+	}
+	sort.Strings(accepted)
+
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var unknown []string
+		for name := range request.GetArguments() {
+			if _, ok := tool.InputSchema.Properties[name]; !ok {
+				unknown = append(unknown, name)
+			}
+		}
+
+		if len(unknown) > 0 {
+			sort.Strings(unknown)
+			return mcp.NewToolResultError(fmt.Sprintf("Unknown argument(s) for tool '%s': %s. Accepted parameters: %s",
+				tool.Name, strings.Join(unknown, ", "), strings.Join(accepted, ", "))), nil
+		}
+
+		return handler(ctx, request)
+	}
+}
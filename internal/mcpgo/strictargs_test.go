@@ -0,0 +1,59 @@
+package mcpgo
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/auth"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func callTool(t *testing.T, server *Server, message string) *mcp.CallToolResult {
+	t.Helper()
+
+	response := server.mcpServer.HandleMessage(context.Background(), []byte(message))
+	encoded, err := json.Marshal(response)
+	require.NoError(t, err)
+
+	var decoded struct {
+		Result mcp.CallToolResult `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	return &decoded.Result
+}
+
+func TestServer_StrictArgs(t *testing.T) {
+	logger := config.NewTestLogger(io.Discard, "debug")
+	mockEngine := &testQueryEngine{data: &query.FoundationFoodsData{
+		FoundationFoods: []query.FoundationFood{{Description: "Milk, whole", FdcId: 1}},
+	}}
+	message := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"search_foundation_foods_by_name","arguments":{"name":"milk","limite":5}}}`
+
+	t.Run("rejects unknown arguments in strict mode", func(t *testing.T) {
+		server := NewServer(mockEngine, auth.NewBearerTokenAuth("test-token"), logger, WithStrictArgs(true))
+
+		result := callTool(t, server, message)
+
+		require.True(t, result.IsError)
+		require.Len(t, result.Content, 1)
+		text, ok := result.Content[0].(mcp.TextContent)
+		require.True(t, ok)
+		assert.Contains(t, text.Text, "Unknown argument(s) for tool 'search_foundation_foods_by_name': limite")
+		assert.Contains(t, text.Text, "Accepted parameters:")
+		assert.Contains(t, text.Text, "name")
+	})
+
+	t.Run("ignores unknown arguments by default", func(t *testing.T) {
+		server := NewServer(mockEngine, auth.NewBearerTokenAuth("test-token"), logger)
+
+		result := callTool(t, server, message)
+
+		assert.False(t, result.IsError)
+	})
+}
@@ -0,0 +1,14 @@
+package mcpgo
+
+// WithTLS serves HTTPS with the given PEM certificate and key files. Empty paths keep plaintext HTTP.
+func WithTLS(certFile, keyFile string) Option {
+	return func(s *Server) {
+		s.tlsCertFile = certFile
+		s.tlsKeyFile = keyFile
+	}
+}
+
+// tlsEnabled reports whether ServeHTTP serves HTTPS
+func (s *Server) tlsEnabled() bool {
+	return s.tlsCertFile != "" && s.tlsKeyFile != ""
+}
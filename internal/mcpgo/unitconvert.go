@@ -0,0 +1,48 @@
+package mcpgo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/noot-app/foundation-foods-mcp-server/internal/query"
+)
+
+func (s *Server) handleConvertNutrientUnit(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.log.DebugContext(ctx, "handleConvertNutrientUnit: Starting tool call",
+		"arguments", request.GetArguments())
+
+	amount, err := request.RequireFloat("amount")
+	if err != nil {
+		s.log.WarnContext(ctx, "handleConvertNutrientUnit: Missing 'amount' parameter", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Missing required parameter 'amount': %v", err)), nil
+	}
+
+	fromUnit, err := request.RequireString("from_unit")
+	if err != nil {
+		s.log.WarnContext(ctx, "handleConvertNutrientUnit: Missing 'from_unit' parameter", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Missing required parameter 'from_unit': %v", err)), nil
+	}
+
+	toUnit, err := request.RequireString("to_unit")
+	if err != nil {
+		s.log.WarnContext(ctx, "handleConvertNutrientUnit: Missing 'to_unit' parameter", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Missing required parameter 'to_unit': %v", err)), nil
+	}
+
+	nutrient := request.GetString("nutrient", "")
+
+	s.log.DebugContext(ctx, "MCP convert_nutrient_unit called",
+		"amount", amount,
+		"from_unit", fromUnit,
+		"to_unit", toUnit,
+		"nutrient", nutrient)
+
+	response, err := query.ConvertNutrientUnit(amount, fromUnit, toUnit, nutrient)
+	if err != nil {
+		s.log.WarnContext(ctx, "Unit conversion failed", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Conversion failed: %v", err)), nil
+	}
+
+	return s.structuredResult(ctx, "handleConvertNutrientUnit", response)
+}
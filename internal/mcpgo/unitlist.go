@@ -0,0 +1,23 @@
+package mcpgo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func (s *Server) handleListUnits(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.log.DebugContext(ctx, "handleListUnits: Starting tool call",
+		"arguments", request.GetArguments())
+
+	s.log.DebugContext(ctx, "MCP list_units called")
+
+	response, err := s.queryEngine.ListUnits(ctx)
+	if err != nil {
+		s.log.WarnContext(ctx, "List units failed", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("List units failed: %v", err)), nil
+	}
+
+	return s.structuredResult(ctx, "handleListUnits", response)
+}
@@ -0,0 +1,33 @@
+package mcpgo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func (s *Server) handleNutrientsWithUpperLimits(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.log.DebugContext(ctx, "handleNutrientsWithUpperLimits: Starting tool call",
+		"arguments", request.GetArguments())
+
+	fdcId, err := request.RequireInt("fdcId")
+	if err != nil {
+		s.log.WarnContext(ctx, "handleNutrientsWithUpperLimits: Missing 'fdcId' parameter", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Missing required parameter 'fdcId': %v", err)), nil
+	}
+
+	measure := request.GetString("measure", "")
+
+	s.log.DebugContext(ctx, "MCP nutrients_with_upper_limits called",
+		"fdcId", fdcId,
+		"measure", measure)
+
+	response, err := s.queryEngine.NutrientsWithUpperLimits(ctx, fdcId, measure)
+	if err != nil {
+		s.log.WarnContext(ctx, "Upper limits lookup failed", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Upper limits failed: %v", err)), nil
+	}
+
+	return s.structuredResult(ctx, "handleNutrientsWithUpperLimits", response)
+}
@@ -0,0 +1,43 @@
+package mcpgo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func (s *Server) handleNutrientVectors(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.log.DebugContext(ctx, "handleNutrientVectors: Starting tool call",
+		"arguments", request.GetArguments())
+
+	name, err := request.RequireString("name")
+	if err != nil {
+		s.log.WarnContext(ctx, "handleNutrientVectors: Missing 'name' parameter", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Missing required parameter 'name': %v", err)), nil
+	}
+
+	if strings.TrimSpace(name) == "" {
+		return mcp.NewToolResultError("Parameter 'name' must be at least 1 character long"), nil
+	}
+
+	limit := s.searchLimits.Clamp(request.GetInt("limit", s.searchLimits.Default))
+
+	nutrients := request.GetStringSlice("nutrients", nil)
+	opts := s.searchOptions(request)
+
+	s.log.DebugContext(ctx, "MCP nutrient_vectors called",
+		"name", name,
+		"limit", limit,
+		"nutrients_count", len(nutrients),
+		"category", opts.Category)
+
+	response, err := s.queryEngine.NutrientVectors(ctx, name, limit, nutrients, opts)
+	if err != nil {
+		s.log.WarnContext(ctx, "Nutrient vectors failed", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Nutrient vectors failed: %v", err)), nil
+	}
+
+	return s.structuredResult(ctx, "handleNutrientVectors", response)
+}
@@ -0,0 +1,54 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// SearchWithAlternatives returns the best match for a query plus up to alternatives runner-up matches,
+// each with its relevance score and a note on why it matched
+func (e *Engine) SearchWithAlternatives(ctx context.Context, query string, alternatives int, opts SearchOptions) (*SearchWithAlternativesResponse, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.data == nil {
+		return nil, fmt.Errorf("foundation Foods data not loaded")
+	}
+
+	if alternatives < 0 {
+		alternatives = 0
+	}
+
+	normalizedQuery := normalizeString(query)
+	queryWords := strings.Fields(normalizedQuery)
+
+	results := e.scoreFoods(ctx, query, opts)
+	response := &SearchWithAlternativesResponse{
+		Query:        query,
+		Found:        len(results) > 0,
+		Alternatives: []ScoredMatch{},
+	}
+
+	for i, result := range results {
+		if i > alternatives {
+			break
+		}
+
+		match := ScoredMatch{
+			FdcId:       result.Food.FdcId,
+			Description: result.Food.Description,
+			Category:    result.Food.FoodCategory.Description,
+			Score:       result.Score,
+			Why:         explainMatch(normalizeString(result.Food.Description), normalizedQuery, queryWords),
+		}
+
+		if i == 0 {
+			response.BestMatch = &match
+			continue
+		}
+		response.Alternatives = append(response.Alternatives, match)
+	}
+
+	return response, nil
+}
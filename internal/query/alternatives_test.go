@@ -0,0 +1,70 @@
+package query
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_SearchWithAlternatives(t *testing.T) {
+	testData := &FoundationFoodsData{
+		FoundationFoods: []FoundationFood{
+			{Description: "Cheese, cheddar", FdcId: 1, FoodCategory: FoodCategory{Description: "Dairy and Egg Products"}},
+			{Description: "Cheese, parmesan, grated", FdcId: 2, FoodCategory: FoodCategory{Description: "Dairy and Egg Products"}},
+			{Description: "Cheddar", FdcId: 3, FoodCategory: FoodCategory{Description: "Dairy and Egg Products"}},
+			{Description: "Broccoli, raw", FdcId: 4},
+		},
+	}
+
+	engine := &Engine{
+		data:   testData,
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+
+	t.Run("returns the best match and scored alternatives", func(t *testing.T) {
+		result, err := engine.SearchWithAlternatives(context.Background(), "cheese cheddar", 4, SearchOptions{})
+
+		require.NoError(t, err)
+		assert.True(t, result.Found)
+		require.NotNil(t, result.BestMatch)
+		assert.Equal(t, 1, result.BestMatch.FdcId)
+		assert.Equal(t, "exact match", result.BestMatch.Why)
+
+		require.NotEmpty(t, result.Alternatives)
+		for _, alternative := range result.Alternatives {
+			assert.Greater(t, alternative.Score, 0.0)
+			assert.LessOrEqual(t, alternative.Score, result.BestMatch.Score)
+			assert.NotEmpty(t, alternative.Why)
+		}
+		assert.Contains(t, result.Alternatives[0].Why, "words")
+	})
+
+	t.Run("limits the alternatives", func(t *testing.T) {
+		result, err := engine.SearchWithAlternatives(context.Background(), "cheese", 1, SearchOptions{})
+
+		require.NoError(t, err)
+		require.NotNil(t, result.BestMatch)
+		assert.Len(t, result.Alternatives, 1)
+	})
+
+	t.Run("returns no best match when nothing matches", func(t *testing.T) {
+		result, err := engine.SearchWithAlternatives(context.Background(), "xyz123", 4, SearchOptions{})
+
+		require.NoError(t, err)
+		assert.False(t, result.Found)
+		assert.Nil(t, result.BestMatch)
+		assert.Empty(t, result.Alternatives)
+	})
+}
+
+func TestExplainMatch(t *testing.T) {
+	assert.Equal(t, "exact match", explainMatch("milk", "milk", []string{"milk"}))
+	assert.Equal(t, "description starts with the query", explainMatch("milk whole", "milk", []string{"milk"}))
+	assert.Equal(t, "description contains the query", explainMatch("cheese cheddar", "cheddar", []string{"cheddar"}))
+	assert.Equal(t, "matched 2 of 3 words: chicken (exact), brea (prefix)",
+		explainMatch("chicken breast roasted", "chicken brea skinless", []string{"chicken", "brea", "skinless"}))
+}
@@ -0,0 +1,40 @@
+package query
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// budgetCheckInterval is how many foods a scan scores between checks of its response budget
+const budgetCheckInterval = 256
+
+// scanBudget is the deadline food scans under one context must finish by
+type scanBudget struct {
+	deadline time.Time
+	exceeded atomic.Bool
+}
+
+type scanBudgetKey struct{}
+
+// WithScanBudget returns a context whose food scans stop once budget has elapsed, keeping the matches
+// gathered so far, and a function reporting whether any scan was cut short
+func WithScanBudget(ctx context.Context, budget time.Duration) (context.Context, func() bool) {
+	b := &scanBudget{deadline: time.Now().Add(budget)}
+	return context.WithValue(ctx, scanBudgetKey{}, b), b.exceeded.Load
+}
+
+// BudgetExceeded reports whether the scan budget of ctx has run out, recording that a scan was cut short.
+// A context without a budget never runs out.
+func BudgetExceeded(ctx context.Context) bool {
+	b, ok := ctx.Value(scanBudgetKey{}).(*scanBudget)
+	if !ok {
+		return false
+	}
+	if time.Now().Before(b.deadline) {
+		return false
+	}
+
+	b.exceeded.Store(true)
+	return true
+}
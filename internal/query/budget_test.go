@@ -0,0 +1,59 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_SearchWithinBudget(t *testing.T) {
+	foods := make([]FoundationFood, 4*budgetCheckInterval)
+	for i := range foods {
+		foods[i] = FoundationFood{Description: fmt.Sprintf("Milk, sample %d", i), FdcId: i + 1}
+	}
+
+	engine := &Engine{
+		data:   &FoundationFoodsData{FoundationFoods: foods},
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+
+	t.Run("returns the matches found before the budget ran out", func(t *testing.T) {
+		ctx, partial := WithScanBudget(context.Background(), 0)
+
+		_, count, err := engine.SearchFoodsByNameWithTotal(ctx, "milk", 1, SearchOptions{})
+
+		require.NoError(t, err)
+		assert.True(t, partial())
+		assert.Equal(t, budgetCheckInterval-1, count)
+	})
+
+	t.Run("scans everything within a generous budget", func(t *testing.T) {
+		ctx, partial := WithScanBudget(context.Background(), time.Minute)
+
+		_, count, err := engine.SearchFoodsByNameWithTotal(ctx, "milk", 1, SearchOptions{})
+
+		require.NoError(t, err)
+		assert.False(t, partial())
+		assert.Equal(t, len(foods), count)
+	})
+
+	t.Run("skips the fuzzy fallback once out of budget", func(t *testing.T) {
+		ctx, partial := WithScanBudget(context.Background(), 0)
+
+		results, err := engine.SearchFoodsByName(ctx, "mlik", 3, SearchOptions{ReturnNearestOnEmpty: true})
+
+		require.NoError(t, err)
+		assert.True(t, partial())
+		assert.Empty(t, results)
+	})
+
+	t.Run("never runs out without a budget", func(t *testing.T) {
+		assert.False(t, BudgetExceeded(context.Background()))
+	})
+}
@@ -0,0 +1,113 @@
+package query
+
+import (
+	"container/list"
+	"slices"
+	"sync"
+)
+
+// searchCacheKey identifies a search by its normalized query, clamped limit and options
+type searchCacheKey struct {
+	query string
+	limit int
+	opts  SearchOptions
+}
+
+// searchCacheEntry is a cached search result in the LRU list
+type searchCacheEntry struct {
+	key   searchCacheKey
+	foods []FoundationFood
+	total int
+}
+
+// searchCache is a fixed-size LRU cache of search results. It is safe for concurrent use.
+type searchCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // front is most recently used
+	entries  map[searchCacheKey]*list.Element
+	hits     int64
+	misses   int64
+}
+
+// newSearchCache returns a cache holding up to capacity searches, or nil when capacity is not positive
+func newSearchCache(capacity int) *searchCache {
+	if capacity <= 0 {
+		return nil
+	}
+	return &searchCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[searchCacheKey]*list.Element, capacity),
+	}
+}
+
+// get returns a copy of the cached result for key and its match total, counting the hit or miss
+func (c *searchCache) get(key searchCacheKey) ([]FoundationFood, int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		c.misses++
+		return nil, 0, false
+	}
+
+	c.hits++
+	c.order.MoveToFront(element)
+	entry := element.Value.(*searchCacheEntry)
+	return slices.Clone(entry.foods), entry.total, true
+}
+
+// put stores a copy of a search result and its match total, evicting the least recently used one when full
+func (c *searchCache) put(key searchCacheKey, foods []FoundationFood, total int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*searchCacheEntry)
+		entry.foods, entry.total = slices.Clone(foods), total
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&searchCacheEntry{key: key, foods: slices.Clone(foods), total: total})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*searchCacheEntry).key)
+	}
+}
+
+// clear drops every cached result, keeping the hit and miss counters
+func (c *searchCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	clear(c.entries)
+}
+
+// stats returns the cache's size, capacity and counters
+func (c *searchCache) stats() SearchCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return SearchCacheStats{Size: c.order.Len(), Capacity: c.capacity, Hits: c.hits, Misses: c.misses}
+}
+
+// WithSearchCacheSize caches the results of up to size recent searches (0 disables the cache). The cache
+// is cleared whenever the dataset is swapped.
+func WithSearchCacheSize(size int) EngineOption {
+	return func(e *Engine) {
+		e.searchCache = newSearchCache(size)
+	}
+}
+
+// SearchCacheStats reports the search cache's size and hit counters; all zero when the cache is disabled
+func (e *Engine) SearchCacheStats() SearchCacheStats {
+	if e.searchCache == nil {
+		return SearchCacheStats{}
+	}
+	return e.searchCache.stats()
+}
@@ -0,0 +1,116 @@
+package query
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCachedTestEngine(capacity int) *Engine {
+	engine := &Engine{
+		logger:      config.NewTestLogger(io.Discard, "debug"),
+		searchCache: newSearchCache(capacity),
+	}
+	engine.swapData(&FoundationFoodsData{
+		FoundationFoods: []FoundationFood{
+			{Description: "Milk, whole", FdcId: 1},
+			{Description: "Eggs, whole", FdcId: 2},
+			{Description: "Bread, white", FdcId: 3},
+		},
+	})
+	return engine
+}
+
+func TestEngine_SearchCache(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("serves a repeated query from the cache without rescanning", func(t *testing.T) {
+		engine := newCachedTestEngine(8)
+
+		first, err := engine.SearchFoodsByName(ctx, "milk", 3, SearchOptions{})
+		require.NoError(t, err)
+		require.Len(t, first, 1)
+
+		// A rescan would see the renamed food; the cached result still has the original description
+		engine.data.FoundationFoods[0].Description = "Renamed"
+
+		second, err := engine.SearchFoodsByName(ctx, "  MILK ", 3, SearchOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, first, second)
+		assert.Equal(t, SearchCacheStats{Size: 1, Capacity: 8, Hits: 1, Misses: 1}, engine.SearchCacheStats())
+	})
+
+	t.Run("keys on the limit and options", func(t *testing.T) {
+		engine := newCachedTestEngine(8)
+
+		_, err := engine.SearchFoodsByName(ctx, "whole", 3, SearchOptions{})
+		require.NoError(t, err)
+		_, err = engine.SearchFoodsByName(ctx, "whole", 1, SearchOptions{})
+		require.NoError(t, err)
+		_, err = engine.SearchFoodsByName(ctx, "whole", 3, SearchOptions{IncludeScores: true})
+		require.NoError(t, err)
+
+		stats := engine.SearchCacheStats()
+		assert.Equal(t, int64(0), stats.Hits)
+		assert.Equal(t, 3, stats.Size)
+	})
+
+	t.Run("returns copies callers can modify", func(t *testing.T) {
+		engine := newCachedTestEngine(8)
+
+		first, err := engine.SearchFoodsByName(ctx, "milk", 3, SearchOptions{})
+		require.NoError(t, err)
+		first[0] = FoundationFood{Description: "Overwritten"}
+
+		second, err := engine.SearchFoodsByName(ctx, "milk", 3, SearchOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "Milk, whole", second[0].Description)
+	})
+
+	t.Run("is cleared when the dataset is swapped", func(t *testing.T) {
+		engine := newCachedTestEngine(8)
+
+		_, err := engine.SearchFoodsByName(ctx, "milk", 3, SearchOptions{})
+		require.NoError(t, err)
+
+		engine.swapData(&FoundationFoodsData{
+			FoundationFoods: []FoundationFood{{Description: "Milk, lowfat", FdcId: 4}},
+		})
+
+		results, err := engine.SearchFoodsByName(ctx, "milk", 3, SearchOptions{})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, 4, results[0].FdcId)
+		assert.Equal(t, int64(0), engine.SearchCacheStats().Hits)
+	})
+
+	t.Run("evicts the least recently used search", func(t *testing.T) {
+		engine := newCachedTestEngine(2)
+
+		for _, query := range []string{"milk", "eggs", "milk", "bread"} {
+			_, err := engine.SearchFoodsByName(ctx, query, 3, SearchOptions{})
+			require.NoError(t, err)
+		}
+
+		// "eggs" was the least recently used when "bread" was added
+		_, err := engine.SearchFoodsByName(ctx, "eggs", 3, SearchOptions{})
+		require.NoError(t, err)
+
+		stats := engine.SearchCacheStats()
+		assert.Equal(t, 2, stats.Size)
+		assert.Equal(t, int64(1), stats.Hits)
+		assert.Equal(t, int64(4), stats.Misses)
+	})
+
+	t.Run("is disabled without a size", func(t *testing.T) {
+		engine := newCachedTestEngine(0)
+
+		_, err := engine.SearchFoodsByName(ctx, "milk", 3, SearchOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, SearchCacheStats{}, engine.SearchCacheStats())
+	})
+}
@@ -0,0 +1,144 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// nutrientAverage holds the running mean of a nutrient across the foods of a category
+type nutrientAverage struct {
+	Name      string
+	Unit      string
+	Mean      float64
+	FoodCount int
+}
+
+// nutrientKey builds a case-insensitive key for a nutrient so kcal and kJ energy stay separate
+func nutrientKey(name, unit string) string {
+	return strings.ToLower(strings.TrimSpace(name)) + "|" + strings.ToLower(strings.TrimSpace(unit))
+}
+
+// categoryAveragesFor returns the per-nutrient averages for a category, computing and caching them on first use.
+// The caller must hold the read lock.
+func (e *Engine) categoryAveragesFor(category string) map[string]nutrientAverage {
+	cacheKey := normalizeCategory(category)
+
+	e.categoryMu.Lock()
+	defer e.categoryMu.Unlock()
+
+	if averages, ok := e.categoryAverages[cacheKey]; ok {
+		return averages
+	}
+
+	sums := make(map[string]*nutrientAverage)
+	for _, food := range e.data.FoundationFoods {
+		if normalizeCategory(food.FoodCategory.Description) != cacheKey {
+			continue
+		}
+
+		// Count each nutrient at most once per food
+		seen := make(map[string]bool)
+		for _, nutrient := range food.FoodNutrients {
+			key := nutrientKey(nutrient.Nutrient.Name, nutrient.Nutrient.UnitName)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			sum, ok := sums[key]
+			if !ok {
+				sum = &nutrientAverage{Name: nutrient.Nutrient.Name, Unit: nutrient.Nutrient.UnitName}
+				sums[key] = sum
+			}
+			sum.Mean += nutrient.Amount
+			sum.FoodCount++
+		}
+	}
+
+	averages := make(map[string]nutrientAverage, len(sums))
+	for key, sum := range sums {
+		sum.Mean /= float64(sum.FoodCount)
+		averages[key] = *sum
+	}
+
+	if e.categoryAverages == nil {
+		e.categoryAverages = make(map[string]map[string]nutrientAverage)
+	}
+	e.categoryAverages[cacheKey] = averages
+
+	e.logger.Debug("Computed category averages",
+		"category", category,
+		"nutrient_count", len(averages))
+
+	return averages
+}
+
+// CompareFoodToCategory compares a food's nutrients against the averages of its category
+func (e *Engine) CompareFoodToCategory(ctx context.Context, fdcId int) (*FoodVsCategoryResponse, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	food, err := e.getFoodByFdcId(fdcId)
+	if err != nil {
+		return nil, err
+	}
+
+	category := food.FoodCategory.Description
+	if strings.TrimSpace(category) == "" {
+		return nil, fmt.Errorf("food with FDC ID %d has no category", fdcId)
+	}
+
+	averages := e.categoryAveragesFor(category)
+
+	response := &FoodVsCategoryResponse{
+		FdcId:       food.FdcId,
+		Description: food.Description,
+		Category:    category,
+		Nutrients:   make([]NutrientCategoryDifference, 0, len(food.FoodNutrients)),
+	}
+
+	seen := make(map[string]bool)
+	for _, nutrient := range food.FoodNutrients {
+		key := nutrientKey(nutrient.Nutrient.Name, nutrient.Nutrient.UnitName)
+		average, ok := averages[key]
+		if !ok || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		difference := nutrient.Amount - average.Mean
+
+		// Percentage deviation is left at zero when the category mean is zero
+		var percentDifference float64
+		if average.Mean != 0 {
+			percentDifference = difference / average.Mean * 100
+		}
+
+		response.Nutrients = append(response.Nutrients, NutrientCategoryDifference{
+			Name:              nutrient.Nutrient.Name,
+			Unit:              nutrient.Nutrient.UnitName,
+			Amount:            nutrient.Amount,
+			CategoryMean:      average.Mean,
+			Difference:        difference,
+			PercentDifference: percentDifference,
+			CategoryFoodCount: average.FoodCount,
+		})
+	}
+
+	// Largest deviations first so the most notable differences lead the response
+	sort.SliceStable(response.Nutrients, func(i, j int) bool {
+		return abs(response.Nutrients[i].PercentDifference) > abs(response.Nutrients[j].PercentDifference)
+	})
+
+	return response, nil
+}
+
+// abs returns the absolute value of a float
+func abs(v float64) float64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,92 @@
+package query
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_CompareFoodToCategory(t *testing.T) {
+	cheese := FoodCategory{Description: "Dairy and Egg Products"}
+	testData := &FoundationFoodsData{
+		FoundationFoods: []FoundationFood{
+			{
+				Description:  "Cheese, feta",
+				FdcId:        1,
+				FoodCategory: cheese,
+				FoodNutrients: []FoodNutrient{
+					{Nutrient: Nutrient{Name: "Sodium, Na", UnitName: "mg"}, Amount: 900},
+					{Nutrient: Nutrient{Name: "Protein", UnitName: "g"}, Amount: 15},
+				},
+			},
+			{
+				Description:  "Cheese, mozzarella",
+				FdcId:        2,
+				FoodCategory: cheese,
+				FoodNutrients: []FoodNutrient{
+					{Nutrient: Nutrient{Name: "Sodium, Na", UnitName: "mg"}, Amount: 300},
+					{Nutrient: Nutrient{Name: "Protein", UnitName: "g"}, Amount: 25},
+					{Nutrient: Nutrient{Name: "Calcium, Ca", UnitName: "mg"}, Amount: 500},
+				},
+			},
+			{
+				Description:  "Bread, white",
+				FdcId:        3,
+				FoodCategory: FoodCategory{Description: "Baked Products"},
+				FoodNutrients: []FoodNutrient{
+					{Nutrient: Nutrient{Name: "Sodium, Na", UnitName: "mg"}, Amount: 5000},
+				},
+			},
+		},
+	}
+
+	engine := &Engine{
+		data:   testData,
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+
+	ctx := context.Background()
+
+	t.Run("computes differences against the category mean", func(t *testing.T) {
+		result, err := engine.CompareFoodToCategory(ctx, 1)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Cheese, feta", result.Description)
+		assert.Equal(t, "Dairy and Egg Products", result.Category)
+		require.Len(t, result.Nutrients, 2) // Calcium is not reported by feta
+
+		byName := make(map[string]NutrientCategoryDifference)
+		for _, nutrient := range result.Nutrients {
+			byName[nutrient.Name] = nutrient
+		}
+
+		sodium := byName["Sodium, Na"]
+		assert.Equal(t, 600.0, sodium.CategoryMean) // Bread is in another category
+		assert.Equal(t, 300.0, sodium.Difference)
+		assert.InDelta(t, 50.0, sodium.PercentDifference, 0.001)
+		assert.Equal(t, 2, sodium.CategoryFoodCount)
+
+		protein := byName["Protein"]
+		assert.Equal(t, 20.0, protein.CategoryMean)
+		assert.Equal(t, -5.0, protein.Difference)
+		assert.InDelta(t, -25.0, protein.PercentDifference, 0.001)
+	})
+
+	t.Run("caches category averages", func(t *testing.T) {
+		_, err := engine.CompareFoodToCategory(ctx, 2)
+		require.NoError(t, err)
+
+		assert.Contains(t, engine.categoryAverages, "dairy and egg products")
+	})
+
+	t.Run("returns error for unknown food", func(t *testing.T) {
+		result, err := engine.CompareFoodToCategory(ctx, 999)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+}
@@ -0,0 +1,66 @@
+package query
+
+import (
+	"context"
+	"fmt"
+)
+
+// Bounds on how many foods CompareFoods puts side by side
+const (
+	MinCompareFoods = 2
+	MaxCompareFoods = 5
+)
+
+// CompareFoods puts foods side by side, one row per nutrient with each food's amount and unit per 100 g in
+// the order the FDC IDs were given, null where a food lacks the nutrient. Rows follow nutrientNames
+// (DefaultNutrients when empty); names no compared food reports are left out and listed in
+// unmatchedFilters.
+func (e *Engine) CompareFoods(ctx context.Context, fdcIds []int, nutrientNames []string) (*CompareFoodsResponse, error) {
+	if len(fdcIds) < MinCompareFoods || len(fdcIds) > MaxCompareFoods {
+		return nil, fmt.Errorf("expected %d to %d FDC IDs, got %d", MinCompareFoods, MaxCompareFoods, len(fdcIds))
+	}
+
+	nutrientNames = sanitizeNutrientFilters(nutrientNames)
+	if len(nutrientNames) == 0 {
+		nutrientNames = DefaultNutrients
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	foods := make([]FoundationFood, 0, len(fdcIds))
+	for _, fdcId := range fdcIds {
+		food, err := e.getFoodByFdcId(fdcId)
+		if err != nil {
+			return nil, err
+		}
+		foods = append(foods, *food)
+	}
+
+	response := &CompareFoodsResponse{
+		Foods:     make([]FoodSummary, 0, len(foods)),
+		Nutrients: make([]NutrientComparison, 0, len(nutrientNames)),
+	}
+	for _, food := range foods {
+		response.Foods = append(response.Foods, FoodSummary{FdcId: food.FdcId, Description: food.Description})
+	}
+
+	for _, name := range nutrientNames {
+		row := NutrientComparison{Nutrient: name, Amounts: make([]*NutrientAmount, len(foods))}
+		reported := false
+		for i := range foods {
+			if nutrient, ok := e.matchNutrient(&foods[i], name); ok {
+				row.Amounts[i] = &NutrientAmount{Amount: nutrient.Amount, Unit: nutrient.Nutrient.UnitName}
+				reported = true
+			}
+		}
+
+		if !reported {
+			response.UnmatchedFilters = append(response.UnmatchedFilters, name)
+			continue
+		}
+		response.Nutrients = append(response.Nutrients, row)
+	}
+
+	return response, nil
+}
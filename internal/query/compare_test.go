@@ -0,0 +1,75 @@
+package query
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_CompareFoods(t *testing.T) {
+	testData := &FoundationFoodsData{
+		FoundationFoods: []FoundationFood{
+			{
+				Description: "Milk, whole",
+				FdcId:       1,
+				FoodNutrients: []FoodNutrient{
+					{Nutrient: Nutrient{Name: "Total lipid (fat)", UnitName: "g"}, Amount: 3.25},
+					{Nutrient: Nutrient{Name: "Calcium, Ca", UnitName: "mg"}, Amount: 113},
+				},
+			},
+			{
+				Description: "Milk, reduced fat, 2%",
+				FdcId:       2,
+				FoodNutrients: []FoodNutrient{
+					{Nutrient: Nutrient{Name: "Total lipid (fat)", UnitName: "g"}, Amount: 1.98},
+				},
+			},
+		},
+	}
+
+	engine := &Engine{
+		data:   testData,
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+	ctx := context.Background()
+
+	t.Run("returns one row per nutrient with nulls where a food lacks it", func(t *testing.T) {
+		result, err := engine.CompareFoods(ctx, []int{1, 2}, []string{"Total lipid (fat)", "Calcium, Ca", "Iron, Fe"})
+
+		require.NoError(t, err)
+		assert.Equal(t, []FoodSummary{{FdcId: 1, Description: "Milk, whole"}, {FdcId: 2, Description: "Milk, reduced fat, 2%"}}, result.Foods)
+		require.Len(t, result.Nutrients, 2)
+
+		fat := result.Nutrients[0]
+		assert.Equal(t, "Total lipid (fat)", fat.Nutrient)
+		assert.Equal(t, []*NutrientAmount{{Amount: 3.25, Unit: "g"}, {Amount: 1.98, Unit: "g"}}, fat.Amounts)
+
+		calcium := result.Nutrients[1]
+		assert.Equal(t, &NutrientAmount{Amount: 113, Unit: "mg"}, calcium.Amounts[0])
+		assert.Nil(t, calcium.Amounts[1])
+
+		assert.Equal(t, []string{"Iron, Fe"}, result.UnmatchedFilters)
+	})
+
+	t.Run("defaults to the default nutrient set", func(t *testing.T) {
+		result, err := engine.CompareFoods(ctx, []int{2, 1}, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.Foods[0].FdcId)
+		require.Len(t, result.Nutrients, 2)
+		assert.Equal(t, "Total lipid (fat)", result.Nutrients[0].Nutrient)
+		assert.Equal(t, "Calcium, Ca", result.Nutrients[1].Nutrient)
+	})
+
+	t.Run("rejects too few foods and unknown FDC IDs", func(t *testing.T) {
+		_, err := engine.CompareFoods(ctx, []int{1}, nil)
+		assert.ErrorContains(t, err, "expected 2 to 5")
+
+		_, err = engine.CompareFoods(ctx, []int{1, 999}, nil)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,107 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// RankFoodsByNutrients ranks foods by the weighted sum of their nutrient amounts, each first divided by the
+// dataset's largest amount of that nutrient so units don't dominate. Weights parallel nutrientNames; nil
+// weighs every nutrient 1 and a negative weight favors foods low in the nutrient. Foods lacking any of the
+// nutrients are excluded, and ties are broken by description.
+func (e *Engine) RankFoodsByNutrients(ctx context.Context, nutrientNames []string, weights []float64, limit int, opts SearchOptions) (*CompositeRankingResponse, error) {
+	if len(nutrientNames) == 0 {
+		return nil, fmt.Errorf("at least one nutrient name is required")
+	}
+	for _, name := range nutrientNames {
+		if strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("nutrient names must not be empty")
+		}
+	}
+	if weights == nil {
+		weights = make([]float64, len(nutrientNames))
+		for i := range weights {
+			weights[i] = 1
+		}
+	}
+	if len(weights) != len(nutrientNames) {
+		return nil, fmt.Errorf("got %d weights for %d nutrients", len(weights), len(nutrientNames))
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.data == nil {
+		return nil, fmt.Errorf("foundation Foods data not loaded")
+	}
+
+	distributions := e.nutrientDistributionsFor()
+
+	var foods []CompositeRankedFood
+	for _, food := range e.data.FoundationFoods {
+		if !matchesCategory(food, opts.Category) {
+			continue
+		}
+
+		components := make([]NutrientComponent, 0, len(nutrientNames))
+		var score float64
+		for i, name := range nutrientNames {
+			nutrient, ok := e.matchNutrient(&food, name)
+			if !ok {
+				break
+			}
+
+			// Distributions are sorted, so the dataset max is the last amount
+			var normalized float64
+			amounts := distributions[nutrientKey(nutrient.Nutrient.Name, nutrient.Nutrient.UnitName)]
+			if datasetMax := amounts[len(amounts)-1]; datasetMax > 0 {
+				normalized = nutrient.Amount / datasetMax
+			}
+
+			component := NutrientComponent{
+				Name:         nutrient.Nutrient.Name,
+				Amount:       nutrient.Amount,
+				Unit:         nutrient.Nutrient.UnitName,
+				Normalized:   normalized,
+				Weight:       weights[i],
+				Contribution: normalized * weights[i],
+			}
+			components = append(components, component)
+			score += component.Contribution
+		}
+		if len(components) < len(nutrientNames) {
+			continue
+		}
+
+		foods = append(foods, CompositeRankedFood{
+			FdcId:       food.FdcId,
+			Description: food.Description,
+			Category:    food.FoodCategory.Description,
+			Score:       score,
+			Components:  components,
+		})
+	}
+
+	sort.Slice(foods, func(i, j int) bool {
+		if foods[i].Score != foods[j].Score {
+			return foods[i].Score > foods[j].Score
+		}
+		return foods[i].Description < foods[j].Description
+	})
+
+	if limit > 0 && len(foods) > limit {
+		foods = foods[:limit]
+	}
+	if foods == nil {
+		foods = []CompositeRankedFood{}
+	}
+
+	return &CompositeRankingResponse{
+		Nutrients: nutrientNames,
+		Weights:   weights,
+		Count:     len(foods),
+		Foods:     foods,
+	}, nil
+}
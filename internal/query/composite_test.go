@@ -0,0 +1,72 @@
+package query
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_RankFoodsByNutrients(t *testing.T) {
+	proteinAndFat := func(protein, fat float64) []FoodNutrient {
+		return []FoodNutrient{
+			{Nutrient: Nutrient{Name: "Protein", UnitName: "g"}, Amount: protein},
+			{Nutrient: Nutrient{Name: "Total lipid (fat)", UnitName: "g"}, Amount: fat},
+		}
+	}
+
+	testData := &FoundationFoodsData{
+		FoundationFoods: []FoundationFood{
+			{Description: "Cheese, cheddar", FdcId: 1, FoodNutrients: proteinAndFat(25, 34)},
+			{Description: "Chicken, breast, raw", FdcId: 2, FoodNutrients: proteinAndFat(22.5, 2.6)},
+			{Description: "Fish, cod, raw", FdcId: 3, FoodNutrients: proteinAndFat(18, 0.6)},
+			{Description: "Butter, salted", FdcId: 4, FoodNutrients: proteinAndFat(0.9, 81)},
+			{Description: "Sugar, granulated", FdcId: 5},
+		},
+	}
+
+	engine := &Engine{
+		data:   testData,
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+	ctx := context.Background()
+
+	ids := func(foods []CompositeRankedFood) []int {
+		result := make([]int, len(foods))
+		for i, food := range foods {
+			result[i] = food.FdcId
+		}
+		return result
+	}
+
+	t.Run("favors high protein and low fat with a negative fat weight", func(t *testing.T) {
+		result, err := engine.RankFoodsByNutrients(ctx, []string{"Protein", "Total lipid (fat)"}, []float64{1, -1}, 10, SearchOptions{})
+
+		require.NoError(t, err)
+		assert.Equal(t, []int{2, 3, 1, 4}, ids(result.Foods))
+
+		chicken := result.Foods[0]
+		require.Len(t, chicken.Components, 2)
+		assert.InDelta(t, 0.9, chicken.Components[0].Normalized, 0.0001)
+		assert.InDelta(t, 0.9, chicken.Components[0].Contribution, 0.0001)
+		assert.InDelta(t, 2.6/81, chicken.Components[1].Normalized, 0.0001)
+		assert.InDelta(t, -2.6/81, chicken.Components[1].Contribution, 0.0001)
+		assert.InDelta(t, 0.9-2.6/81, chicken.Score, 0.0001)
+	})
+
+	t.Run("weighs every nutrient 1 by default", func(t *testing.T) {
+		result, err := engine.RankFoodsByNutrients(ctx, []string{"Protein", "Total lipid (fat)"}, nil, 1, SearchOptions{})
+
+		require.NoError(t, err)
+		assert.Equal(t, []float64{1, 1}, result.Weights)
+		assert.Equal(t, []int{1}, ids(result.Foods))
+	})
+
+	t.Run("rejects mismatched weights", func(t *testing.T) {
+		_, err := engine.RankFoodsByNutrients(ctx, []string{"Protein"}, []float64{1, 2}, 10, SearchOptions{})
+		assert.Error(t, err)
+	})
+}
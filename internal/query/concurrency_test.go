@@ -0,0 +1,140 @@
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newConcurrencyTestData builds a dataset whose descriptions carry a generation marker
+func newConcurrencyTestData(generation int) *FoundationFoodsData {
+	foods := make([]FoundationFood, 0, 50)
+	for i := 0; i < 50; i++ {
+		foods = append(foods, FoundationFood{
+			Description:  fmt.Sprintf("Milk, variety %d, generation %d", i, generation),
+			FdcId:        generation*1000 + i,
+			FoodCategory: FoodCategory{Description: "Dairy and Egg Products"},
+			FoodNutrients: []FoodNutrient{
+				{Nutrient: Nutrient{Name: "Protein", UnitName: "g"}, Amount: float64(i)},
+			},
+		})
+	}
+	return &FoundationFoodsData{FoundationFoods: foods}
+}
+
+func TestEngine_ConcurrentReadsDuringSwap(t *testing.T) {
+	engine := &Engine{logger: config.NewTestLogger(io.Discard, "info")}
+	engine.swapData(newConcurrencyTestData(0))
+
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+
+	// Hammer every read path while the dataset is being swapped
+	for worker := 0; worker < 8; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+
+				results, err := engine.SearchFoodsByName(ctx, "milk", 5, SearchOptions{})
+				assert.NoError(t, err)
+				assert.NotEmpty(t, results)
+
+				_, err = engine.SearchFoodsByNameSimplified(ctx, "milk", 5, nil, SearchOptions{})
+				assert.NoError(t, err)
+
+				_, err = engine.ResolveFoods(ctx, []string{"milk", "variety"}, SearchOptions{})
+				assert.NoError(t, err)
+
+				assert.NoError(t, engine.Health(ctx))
+			}
+		}()
+	}
+
+	for generation := 1; generation <= 20; generation++ {
+		engine.swapData(newConcurrencyTestData(generation))
+	}
+	assert.NoError(t, engine.RefreshNormalization(ctx))
+
+	close(stop)
+	wg.Wait()
+
+	food, err := engine.GetFoodByFdcId(ctx, 20000)
+	assert.NoError(t, err)
+	assert.Equal(t, "Milk, variety 0, generation 20", food.Description)
+}
+
+func TestEngine_ConcurrentReadsDuringReload(t *testing.T) {
+	logger := config.NewTestLogger(io.Discard, "info")
+	ctx := context.Background()
+
+	writeGeneration := func(path string, generation int) {
+		encoded, err := json.Marshal(newConcurrencyTestData(generation))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, encoded, 0o600))
+	}
+
+	path := filepath.Join(t.TempDir(), "foods.json")
+	writeGeneration(path, 0)
+	engine, err := NewEngine(path, logger)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+
+	// Every search must see one complete generation, never a mix of two
+	for worker := 0; worker < 8; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+
+				results, err := engine.SearchFoodsByName(ctx, "milk", 10, SearchOptions{})
+				assert.NoError(t, err)
+				if assert.Len(t, results, 10) {
+					generation := results[0].FdcId / 1000
+					for _, food := range results {
+						assert.Equal(t, generation, food.FdcId/1000)
+					}
+				}
+
+				_, err = engine.SearchFoodsByNameSimplified(ctx, "milk", 5, nil, SearchOptions{})
+				assert.NoError(t, err)
+				assert.NoError(t, engine.Health(ctx))
+			}
+		}()
+	}
+
+	for generation := 1; generation <= 10; generation++ {
+		writeGeneration(path, generation)
+		assert.NoError(t, engine.Reload(ctx))
+	}
+
+	close(stop)
+	wg.Wait()
+
+	food, err := engine.GetFoodByFdcId(ctx, 10000)
+	assert.NoError(t, err)
+	assert.Equal(t, "Milk, variety 0, generation 10", food.Description)
+}
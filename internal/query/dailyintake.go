@@ -0,0 +1,224 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Demographics with built-in daily intake targets
+const (
+	DemographicAdultMale   = "adult_male"
+	DemographicAdultFemale = "adult_female"
+)
+
+// IntakeTarget is the recommended daily intake of a nutrient: its Recommended Dietary Allowance, or its
+// Adequate Intake where no RDA is set
+type IntakeTarget struct {
+	Nutrient string  `json:"nutrient"`
+	Amount   float64 `json:"amount"`
+	Unit     string  `json:"unit"`
+}
+
+// DailyIntakeTargets are the adult (19-50 years) Dietary Reference Intakes by demographic, keyed to the
+// nutrient names used in the dataset. Where the DRIs split that range (magnesium) the 31-50 value is used.
+// Energy is left out since it depends on body size and activity.
+var DailyIntakeTargets = map[string][]IntakeTarget{
+	DemographicAdultMale: {
+		{Nutrient: "Protein", Amount: 56, Unit: "g"},
+		{Nutrient: "Carbohydrate, by difference", Amount: 130, Unit: "g"},
+		{Nutrient: "Fiber, total dietary", Amount: 38, Unit: "g"},
+		{Nutrient: "Calcium, Ca", Amount: 1000, Unit: "mg"},
+		{Nutrient: "Iron, Fe", Amount: 8, Unit: "mg"},
+		{Nutrient: "Magnesium, Mg", Amount: 420, Unit: "mg"},
+		{Nutrient: "Phosphorus, P", Amount: 700, Unit: "mg"},
+		{Nutrient: "Potassium, K", Amount: 3400, Unit: "mg"},
+		{Nutrient: "Sodium, Na", Amount: 1500, Unit: "mg"},
+		{Nutrient: "Zinc, Zn", Amount: 11, Unit: "mg"},
+		{Nutrient: "Copper, Cu", Amount: 900, Unit: "µg"},
+		{Nutrient: "Manganese, Mn", Amount: 2.3, Unit: "mg"},
+		{Nutrient: "Selenium, Se", Amount: 55, Unit: "µg"},
+		{Nutrient: "Iodine, I", Amount: 150, Unit: "µg"},
+		{Nutrient: "Molybdenum, Mo", Amount: 45, Unit: "µg"},
+		{Nutrient: "Vitamin A, RAE", Amount: 900, Unit: "µg"},
+		{Nutrient: "Vitamin C, total ascorbic acid", Amount: 90, Unit: "mg"},
+		{Nutrient: "Vitamin D (D2 + D3)", Amount: 15, Unit: "µg"},
+		{Nutrient: "Vitamin E (alpha-tocopherol)", Amount: 15, Unit: "mg"},
+		{Nutrient: "Vitamin K (phylloquinone)", Amount: 120, Unit: "µg"},
+		{Nutrient: "Thiamin", Amount: 1.2, Unit: "mg"},
+		{Nutrient: "Riboflavin", Amount: 1.3, Unit: "mg"},
+		{Nutrient: "Niacin", Amount: 16, Unit: "mg"},
+		{Nutrient: "Vitamin B-6", Amount: 1.3, Unit: "mg"},
+		{Nutrient: "Folate, total", Amount: 400, Unit: "µg"},
+		{Nutrient: "Vitamin B-12", Amount: 2.4, Unit: "µg"},
+		{Nutrient: "Pantothenic acid", Amount: 5, Unit: "mg"},
+		{Nutrient: "Biotin", Amount: 30, Unit: "µg"},
+		{Nutrient: "Choline, total", Amount: 550, Unit: "mg"},
+	},
+	DemographicAdultFemale: {
+		{Nutrient: "Protein", Amount: 46, Unit: "g"},
+		{Nutrient: "Carbohydrate, by difference", Amount: 130, Unit: "g"},
+		{Nutrient: "Fiber, total dietary", Amount: 25, Unit: "g"},
+		{Nutrient: "Calcium, Ca", Amount: 1000, Unit: "mg"},
+		{Nutrient: "Iron, Fe", Amount: 18, Unit: "mg"},
+		{Nutrient: "Magnesium, Mg", Amount: 320, Unit: "mg"},
+		{Nutrient: "Phosphorus, P", Amount: 700, Unit: "mg"},
+		{Nutrient: "Potassium, K", Amount: 2600, Unit: "mg"},
+		{Nutrient: "Sodium, Na", Amount: 1500, Unit: "mg"},
+		{Nutrient: "Zinc, Zn", Amount: 8, Unit: "mg"},
+		{Nutrient: "Copper, Cu", Amount: 900, Unit: "µg"},
+		{Nutrient: "Manganese, Mn", Amount: 1.8, Unit: "mg"},
+		{Nutrient: "Selenium, Se", Amount: 55, Unit: "µg"},
+		{Nutrient: "Iodine, I", Amount: 150, Unit: "µg"},
+		{Nutrient: "Molybdenum, Mo", Amount: 45, Unit: "µg"},
+		{Nutrient: "Vitamin A, RAE", Amount: 700, Unit: "µg"},
+		{Nutrient: "Vitamin C, total ascorbic acid", Amount: 75, Unit: "mg"},
+		{Nutrient: "Vitamin D (D2 + D3)", Amount: 15, Unit: "µg"},
+		{Nutrient: "Vitamin E (alpha-tocopherol)", Amount: 15, Unit: "mg"},
+		{Nutrient: "Vitamin K (phylloquinone)", Amount: 90, Unit: "µg"},
+		{Nutrient: "Thiamin", Amount: 1.1, Unit: "mg"},
+		{Nutrient: "Riboflavin", Amount: 1.1, Unit: "mg"},
+		{Nutrient: "Niacin", Amount: 14, Unit: "mg"},
+		{Nutrient: "Vitamin B-6", Amount: 1.3, Unit: "mg"},
+		{Nutrient: "Folate, total", Amount: 400, Unit: "µg"},
+		{Nutrient: "Vitamin B-12", Amount: 2.4, Unit: "µg"},
+		{Nutrient: "Pantothenic acid", Amount: 5, Unit: "mg"},
+		{Nutrient: "Biotin", Amount: 30, Unit: "µg"},
+		{Nutrient: "Choline, total", Amount: 425, Unit: "mg"},
+	},
+}
+
+// Demographics returns the demographics with daily intake targets, sorted
+func Demographics() []string {
+	demographics := make([]string, 0, len(DailyIntakeTargets))
+	for demographic := range DailyIntakeTargets {
+		demographics = append(demographics, demographic)
+	}
+	sort.Strings(demographics)
+	return demographics
+}
+
+// intakeTargetFor returns the target for a dataset nutrient name, accepting the alternative names
+// shouldIncludeNutrient accepts
+func (e *Engine) intakeTargetFor(targets []IntakeTarget, nutrientName string) (IntakeTarget, bool) {
+	dataName := strings.ToLower(strings.TrimSpace(nutrientName))
+	for _, target := range targets {
+		targetName := strings.ToLower(target.Nutrient)
+		if dataName == targetName || e.isAlternativeNutrientName(dataName, targetName) {
+			return target, true
+		}
+	}
+	return IntakeTarget{}, false
+}
+
+// DailyIntakeSummary totals the nutrients of a day's foods, each resolved to its best name match and scaled
+// to the grams eaten, and compares every total with the demographic's daily target and upper limit. Foods
+// that match nothing are reported and left out of the totals. Targeted nutrients none of the foods report
+// are included with a zero amount.
+func (e *Engine) DailyIntakeSummary(ctx context.Context, entries []IntakeEntry, demographic string, opts SearchOptions) (*DailyIntakeSummaryResponse, error) {
+	targets, ok := DailyIntakeTargets[demographic]
+	if !ok {
+		return nil, fmt.Errorf("unknown demographic %q; expected one of %s", demographic, strings.Join(Demographics(), ", "))
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("at least one food is required")
+	}
+	for _, entry := range entries {
+		if strings.TrimSpace(entry.Food) == "" {
+			return nil, fmt.Errorf("food names must not be empty")
+		}
+		if entry.Grams <= 0 {
+			return nil, fmt.Errorf("grams for %q must be positive, got %v", entry.Food, entry.Grams)
+		}
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.data == nil {
+		return nil, fmt.Errorf("foundation Foods data not loaded")
+	}
+
+	response := &DailyIntakeSummaryResponse{
+		Demographic: demographic,
+		Foods:       make([]IntakeFood, 0, len(entries)),
+		Unresolved:  []string{},
+		OverLimit:   []string{},
+	}
+
+	// Sum per dataset nutrient name and unit, in the order the nutrients are first seen
+	totals := make(map[string]*DailyNutrientTotal)
+	var order []string
+	for _, entry := range entries {
+		intakeFood := IntakeFood{Food: entry.Food, Grams: entry.Grams}
+
+		results := e.scoreFoods(ctx, entry.Food, opts)
+		if len(results) == 0 {
+			response.Foods = append(response.Foods, intakeFood)
+			response.Unresolved = append(response.Unresolved, entry.Food)
+			continue
+		}
+
+		food := results[0].Food
+		fdcId := food.FdcId
+		description := food.Description
+		intakeFood.Found = true
+		intakeFood.FdcId = &fdcId
+		intakeFood.Description = &description
+		response.Foods = append(response.Foods, intakeFood)
+
+		factor := entry.Grams / 100
+		for _, nutrient := range food.FoodNutrients {
+			key := nutrientKey(nutrient.Nutrient.Name, nutrient.Nutrient.UnitName)
+			total, ok := totals[key]
+			if !ok {
+				total = &DailyNutrientTotal{Name: nutrient.Nutrient.Name, Unit: nutrient.Nutrient.UnitName}
+				totals[key] = total
+				order = append(order, key)
+			}
+			total.Amount += nutrient.Amount * factor
+		}
+	}
+
+	targeted := make(map[string]bool, len(targets))
+	for _, key := range order {
+		total := totals[key]
+
+		if target, ok := e.intakeTargetFor(targets, total.Name); ok {
+			if amount, ok := convertNutrientAmount(total.Amount, total.Unit, target.Unit); ok {
+				remaining := target.Amount - amount
+				total.Target = &target
+				total.Remaining = &remaining
+				targeted[target.Nutrient] = true
+			}
+		}
+
+		if limit, ok := e.upperLimitFor(total.Name); ok {
+			if fraction, ok := limitFraction(total.Amount, total.Unit, limit); ok {
+				total.UpperLimit = &limit
+				total.OverUpperLimit = fraction > 1
+				if total.OverUpperLimit {
+					response.OverLimit = append(response.OverLimit, total.Name)
+				}
+			}
+		}
+
+		response.Nutrients = append(response.Nutrients, *total)
+	}
+
+	// Nothing eaten yet counts toward the remaining targets too
+	for _, target := range targets {
+		if targeted[target.Nutrient] {
+			continue
+		}
+		remaining := target.Amount
+		missing := DailyNutrientTotal{Name: target.Nutrient, Unit: target.Unit, Target: &target, Remaining: &remaining}
+		if limit, ok := e.upperLimitFor(target.Nutrient); ok {
+			missing.UpperLimit = &limit
+		}
+		response.Nutrients = append(response.Nutrients, missing)
+	}
+
+	return response, nil
+}
@@ -0,0 +1,113 @@
+package query
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_DailyIntakeSummary(t *testing.T) {
+	testData := &FoundationFoodsData{
+		FoundationFoods: []FoundationFood{
+			{
+				Description: "Milk, whole",
+				FdcId:       1,
+				FoodNutrients: []FoodNutrient{
+					{Nutrient: Nutrient{Name: "Protein", UnitName: "g"}, Amount: 3.3},
+					{Nutrient: Nutrient{Name: "Calcium, Ca", UnitName: "mg"}, Amount: 120},
+				},
+			},
+			{
+				Description: "Soy sauce",
+				FdcId:       2,
+				FoodNutrients: []FoodNutrient{
+					{Nutrient: Nutrient{Name: "Protein", UnitName: "g"}, Amount: 8.1},
+					{Nutrient: Nutrient{Name: "Sodium, Na", UnitName: "mg"}, Amount: 5500},
+				},
+			},
+		},
+	}
+
+	engine := &Engine{
+		data:   testData,
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+	ctx := context.Background()
+
+	byName := func(response *DailyIntakeSummaryResponse) map[string]DailyNutrientTotal {
+		nutrients := make(map[string]DailyNutrientTotal)
+		for _, nutrient := range response.Nutrients {
+			nutrients[nutrient.Name] = nutrient
+		}
+		return nutrients
+	}
+
+	t.Run("sums two foods against one demographic's targets", func(t *testing.T) {
+		entries := []IntakeEntry{{Food: "milk", Grams: 500}, {Food: "soy sauce", Grams: 50}}
+
+		result, err := engine.DailyIntakeSummary(ctx, entries, DemographicAdultFemale, SearchOptions{})
+
+		require.NoError(t, err)
+		assert.Empty(t, result.Unresolved)
+		require.Len(t, result.Foods, 2)
+		assert.Equal(t, 1, *result.Foods[0].FdcId)
+		assert.Equal(t, 2, *result.Foods[1].FdcId)
+		nutrients := byName(result)
+
+		// 16.5 g from the milk plus 4.05 g from the soy sauce, against a 46 g target
+		protein := nutrients["Protein"]
+		assert.InDelta(t, 20.55, protein.Amount, 0.001)
+		require.NotNil(t, protein.Target)
+		assert.Equal(t, 46.0, protein.Target.Amount)
+		assert.InDelta(t, 25.45, *protein.Remaining, 0.001)
+
+		// 2750 mg of sodium passes both the 1500 mg target and the 2300 mg upper limit
+		sodium := nutrients["Sodium, Na"]
+		assert.InDelta(t, -1250.0, *sodium.Remaining, 0.001)
+		require.NotNil(t, sodium.UpperLimit)
+		assert.True(t, sodium.OverUpperLimit)
+		assert.Equal(t, []string{"Sodium, Na"}, result.OverLimit)
+
+		// Targets the foods don't touch still count toward the day
+		iron := nutrients["Iron, Fe"]
+		assert.Equal(t, 0.0, iron.Amount)
+		assert.Equal(t, 18.0, *iron.Remaining)
+	})
+
+	t.Run("converts totals to the target's unit", func(t *testing.T) {
+		result, err := engine.DailyIntakeSummary(ctx, []IntakeEntry{{Food: "milk", Grams: 1000}}, DemographicAdultMale, SearchOptions{})
+
+		require.NoError(t, err)
+		calcium := byName(result)["Calcium, Ca"]
+		assert.InDelta(t, 1200.0, calcium.Amount, 0.001)
+		assert.InDelta(t, -200.0, *calcium.Remaining, 0.001)
+		assert.False(t, calcium.OverUpperLimit)
+	})
+
+	t.Run("reports unresolvable foods without aborting", func(t *testing.T) {
+		entries := []IntakeEntry{{Food: "milk", Grams: 100}, {Food: "zzzz", Grams: 100}}
+
+		result, err := engine.DailyIntakeSummary(ctx, entries, DemographicAdultMale, SearchOptions{})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"zzzz"}, result.Unresolved)
+		assert.False(t, result.Foods[1].Found)
+		assert.Nil(t, result.Foods[1].FdcId)
+		assert.InDelta(t, 3.3, byName(result)["Protein"].Amount, 0.001)
+	})
+
+	t.Run("rejects bad input", func(t *testing.T) {
+		_, err := engine.DailyIntakeSummary(ctx, []IntakeEntry{{Food: "milk", Grams: 100}}, "toddler", SearchOptions{})
+		assert.ErrorContains(t, err, "unknown demographic")
+
+		_, err = engine.DailyIntakeSummary(ctx, []IntakeEntry{{Food: "milk", Grams: 0}}, DemographicAdultMale, SearchOptions{})
+		assert.ErrorContains(t, err, "must be positive")
+
+		_, err = engine.DailyIntakeSummary(ctx, nil, DemographicAdultMale, SearchOptions{})
+		assert.ErrorContains(t, err, "at least one food")
+	})
+}
@@ -0,0 +1,55 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// publicationDateLayout is the M/D/YYYY format of the dataset's publicationDate fields
+const publicationDateLayout = "1/2/2006"
+
+// DatasetInfo describes the loaded dataset: its source file, release, size and the most recent
+// publication date of its foods. Comparing it before and after a reload shows whether a newer release was picked up.
+func (e *Engine) DatasetInfo(ctx context.Context) (*DatasetInfoResponse, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.data == nil {
+		return nil, fmt.Errorf("foundation Foods data not loaded")
+	}
+
+	categories := make(map[string]bool)
+	var latest time.Time
+	for _, food := range e.data.FoundationFoods {
+		if category := strings.TrimSpace(food.FoodCategory.Description); category != "" {
+			categories[strings.ToLower(category)] = true
+		}
+
+		// Foods with a missing or malformed publication date don't count towards the latest one
+		published, err := time.Parse(publicationDateLayout, strings.TrimSpace(food.PublicationDate))
+		if err == nil && published.After(latest) {
+			latest = published
+		}
+	}
+
+	response := &DatasetInfoResponse{
+		SourceFile:    filepath.Base(e.jsonFilePath),
+		Release:       e.datasetDate,
+		FoodCount:     len(e.data.FoundationFoods),
+		CategoryCount: len(categories),
+	}
+	if !latest.IsZero() {
+		response.LatestPublicationDate = latest.Format(time.DateOnly)
+	}
+
+	e.logger.DebugContext(ctx, "Computed dataset info",
+		"source_file", response.SourceFile,
+		"food_count", response.FoodCount,
+		"category_count", response.CategoryCount,
+		"latest_publication_date", response.LatestPublicationDate)
+
+	return response, nil
+}
@@ -0,0 +1,41 @@
+package query
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_DatasetInfo(t *testing.T) {
+	engine := &Engine{
+		data: &FoundationFoodsData{
+			FoundationFoods: []FoundationFood{
+				{Description: "Milk, whole", FdcId: 1, PublicationDate: "4/1/2019", FoodCategory: FoodCategory{Description: "Dairy and Egg Products"}},
+				{Description: "Cheese, cheddar", FdcId: 2, PublicationDate: "10/31/2024", FoodCategory: FoodCategory{Description: "dairy and egg products"}},
+				{Description: "Broccoli, raw", FdcId: 3, PublicationDate: "12/16/2019", FoodCategory: FoodCategory{Description: "Vegetables and Vegetable Products"}},
+				{Description: "Mystery food", FdcId: 4, PublicationDate: "not a date"},
+			},
+		},
+		jsonFilePath: "/srv/data/foundationfoods_2025-04-24.json",
+		datasetDate:  "2025-04-24",
+		logger:       config.NewTestLogger(io.Discard, "debug"),
+	}
+
+	info, err := engine.DatasetInfo(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, "foundationfoods_2025-04-24.json", info.SourceFile)
+	assert.Equal(t, "2025-04-24", info.Release)
+	assert.Equal(t, 4, info.FoodCount)
+	assert.Equal(t, 2, info.CategoryCount)
+	assert.Equal(t, "2024-10-31", info.LatestPublicationDate)
+
+	t.Run("returns an error when no data is loaded", func(t *testing.T) {
+		_, err := (&Engine{logger: config.NewTestLogger(io.Discard, "debug")}).DatasetInfo(context.Background())
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,90 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// energyKcal returns a food's energy in kcal, preferring the plain "Energy" entry over the
+// Atwater-factor variants that many foundation foods report instead
+func energyKcal(food FoundationFood) (float64, bool) {
+	var fallback float64
+	var hasFallback bool
+
+	for _, nutrient := range food.FoodNutrients {
+		name := strings.ToLower(strings.TrimSpace(nutrient.Nutrient.Name))
+		if !strings.EqualFold(strings.TrimSpace(nutrient.Nutrient.UnitName), "kcal") || !strings.HasPrefix(name, "energy") {
+			continue
+		}
+		if name == "energy" {
+			return nutrient.Amount, true
+		}
+		if !hasFallback {
+			fallback, hasFallback = nutrient.Amount, true
+		}
+	}
+
+	return fallback, hasFallback
+}
+
+// proteinGrams returns a food's protein in grams
+func proteinGrams(food FoundationFood) (float64, bool) {
+	for _, nutrient := range food.FoodNutrients {
+		if strings.EqualFold(strings.TrimSpace(nutrient.Nutrient.Name), "protein") &&
+			strings.EqualFold(strings.TrimSpace(nutrient.Nutrient.UnitName), "g") {
+			return nutrient.Amount, true
+		}
+	}
+	return 0, false
+}
+
+// RankByProteinDensity returns the foods with the most grams of protein per 100 kcal.
+// Foods missing protein or kcal energy are excluded.
+func (e *Engine) RankByProteinDensity(ctx context.Context, limit int, opts SearchOptions) (*ProteinDensityResponse, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.data == nil {
+		return nil, fmt.Errorf("foundation Foods data not loaded")
+	}
+
+	var foods []ProteinDensityFood
+	for _, food := range e.data.FoundationFoods {
+		if !matchesCategory(food, opts.Category) {
+			continue
+		}
+
+		protein, ok := proteinGrams(food)
+		if !ok {
+			continue
+		}
+		energy, ok := energyKcal(food)
+		if !ok || energy <= 0 {
+			continue
+		}
+
+		foods = append(foods, ProteinDensityFood{
+			FdcId:             food.FdcId,
+			Description:       food.Description,
+			Category:          food.FoodCategory.Description,
+			ProteinGrams:      protein,
+			EnergyKcal:        energy,
+			ProteinPer100Kcal: protein / energy * 100,
+		})
+	}
+
+	sort.SliceStable(foods, func(i, j int) bool {
+		return foods[i].ProteinPer100Kcal > foods[j].ProteinPer100Kcal
+	})
+
+	if limit > 0 && len(foods) > limit {
+		foods = foods[:limit]
+	}
+
+	return &ProteinDensityResponse{
+		Count: len(foods),
+		Foods: foods,
+	}, nil
+}
@@ -0,0 +1,76 @@
+package query
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_RankByProteinDensity(t *testing.T) {
+	testData := &FoundationFoodsData{
+		FoundationFoods: []FoundationFood{
+			{
+				Description:  "Pork, belly, raw",
+				FdcId:        1,
+				FoodCategory: FoodCategory{Description: "Pork Products"},
+				FoodNutrients: []FoodNutrient{
+					{Nutrient: Nutrient{Name: "Protein", UnitName: "g"}, Amount: 9.3},
+					{Nutrient: Nutrient{Name: "Energy", UnitName: "kcal"}, Amount: 518},
+				},
+			},
+			{
+				Description:  "Chicken, breast, skinless, raw",
+				FdcId:        2,
+				FoodCategory: FoodCategory{Description: "Poultry Products"},
+				FoodNutrients: []FoodNutrient{
+					{Nutrient: Nutrient{Name: "Energy", UnitName: "kJ"}, Amount: 502},
+					{Nutrient: Nutrient{Name: "Protein", UnitName: "g"}, Amount: 22.5},
+					{Nutrient: Nutrient{Name: "Energy (Atwater General Factors)", UnitName: "kcal"}, Amount: 120},
+				},
+			},
+			{
+				Description:  "Oil, olive",
+				FdcId:        3,
+				FoodCategory: FoodCategory{Description: "Fats and Oils"},
+				FoodNutrients: []FoodNutrient{
+					{Nutrient: Nutrient{Name: "Energy", UnitName: "kcal"}, Amount: 884},
+				},
+			},
+		},
+	}
+
+	engine := &Engine{
+		data:   testData,
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+
+	t.Run("ranks lean protein above fatty protein", func(t *testing.T) {
+		result, err := engine.RankByProteinDensity(context.Background(), 10, SearchOptions{})
+
+		require.NoError(t, err)
+		require.Len(t, result.Foods, 2)
+		assert.Equal(t, 2, result.Foods[0].FdcId)
+		assert.Equal(t, 120.0, result.Foods[0].EnergyKcal)
+		assert.InDelta(t, 18.75, result.Foods[0].ProteinPer100Kcal, 0.0001)
+		assert.Equal(t, 1, result.Foods[1].FdcId)
+	})
+
+	t.Run("filters by category", func(t *testing.T) {
+		result, err := engine.RankByProteinDensity(context.Background(), 10, SearchOptions{Category: "Pork Products"})
+
+		require.NoError(t, err)
+		require.Len(t, result.Foods, 1)
+		assert.Equal(t, 1, result.Foods[0].FdcId)
+	})
+
+	t.Run("respects the limit", func(t *testing.T) {
+		result, err := engine.RankByProteinDensity(context.Background(), 1, SearchOptions{})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Count)
+	})
+}
@@ -0,0 +1,20 @@
+package query
+
+// nutrientDerivation returns the simplified form of a nutrient's derivation, or nil when the dataset has none
+func nutrientDerivation(derivation *FoodNutrientDerivation) *NutrientDerivation {
+	if derivation == nil || (derivation.Code == "" && derivation.Description == "") {
+		return nil
+	}
+	return &NutrientDerivation{
+		Code:        derivation.Code,
+		Description: derivation.Description,
+	}
+}
+
+// sameDerivation reports whether two simplified derivations are equal, treating two missing ones as equal
+func sameDerivation(a, b *NutrientDerivation) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,77 @@
+package query
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_SearchFoodsByNameSimplified_IncludeDerivation(t *testing.T) {
+	analytical := &FoodNutrientDerivation{
+		Code:               "A",
+		Description:        "Analytical",
+		FoodNutrientSource: &FoodNutrientSource{Id: 1, Code: "1", Description: "Analytical or derived from analytical"},
+	}
+	calculated := &FoodNutrientDerivation{Code: "NC", Description: "Calculated"}
+
+	engine := &Engine{
+		data: &FoundationFoodsData{
+			FoundationFoods: []FoundationFood{
+				{
+					Description: "Almonds, raw",
+					FdcId:       1,
+					FoodNutrients: []FoodNutrient{
+						{Nutrient: Nutrient{Name: "Protein", UnitName: "g"}, Amount: 21.2, DataPoints: 6, FoodNutrientDerivation: analytical},
+						{Nutrient: Nutrient{Name: "Fiber, total dietary", UnitName: "g"}, Amount: 10, DataPoints: 2, FoodNutrientDerivation: calculated},
+						{Nutrient: Nutrient{Name: "Fiber, total dietary", UnitName: "g"}, Amount: 12, DataPoints: 8, FoodNutrientDerivation: analytical},
+						{Nutrient: Nutrient{Name: "Water", UnitName: "g"}, Amount: 4.4},
+					},
+				},
+			},
+		},
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+
+	search := func(t *testing.T, opts SearchOptions) []SimplifiedNutrient {
+		t.Helper()
+		result, err := engine.SearchFoodsByNameSimplified(context.Background(), "almonds", 1, nil, opts)
+		require.NoError(t, err)
+		require.Len(t, result.Foods, 1)
+		return result.Foods[0].Nutrients
+	}
+
+	t.Run("omits derivations by default", func(t *testing.T) {
+		for _, nutrient := range search(t, SearchOptions{}) {
+			assert.Nil(t, nutrient.Derivation, nutrient.Name)
+		}
+	})
+
+	t.Run("attaches the code and description when requested", func(t *testing.T) {
+		nutrients := search(t, SearchOptions{IncludeDerivation: true})
+
+		require.Len(t, nutrients, 4)
+		assert.Equal(t, &NutrientDerivation{Code: "A", Description: "Analytical"}, nutrients[0].Derivation)
+		assert.Equal(t, &NutrientDerivation{Code: "NC", Description: "Calculated"}, nutrients[1].Derivation)
+		// Nutrients the dataset gives no derivation for are left without one
+		assert.Nil(t, nutrients[3].Derivation)
+	})
+
+	t.Run("keeps the derivation of the merged entry", func(t *testing.T) {
+		nutrients := search(t, SearchOptions{IncludeDerivation: true, MergeDuplicateNutrients: true})
+
+		require.Len(t, nutrients, 3)
+		assert.Equal(t, "A", nutrients[1].Derivation.Code)
+	})
+
+	t.Run("drops mixed derivations from averages", func(t *testing.T) {
+		nutrients := search(t, SearchOptions{IncludeDerivation: true, MergeDuplicateNutrients: true, MergeStrategy: MergeAverage})
+
+		require.Len(t, nutrients, 3)
+		assert.Equal(t, 11.0, nutrients[1].Amount)
+		assert.Nil(t, nutrients[1].Derivation)
+	})
+}
@@ -0,0 +1,122 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// Ranking modes for TopNutrientDifferences
+const (
+	// RankByAbsolute ranks nutrients by the absolute difference of their unit-normalized amounts
+	RankByAbsolute = "absolute"
+
+	// RankByPercent ranks nutrients by the difference relative to the larger of the two amounts
+	RankByPercent = "percent"
+)
+
+// normalizedNutrient is a nutrient amount converted to a common unit
+type normalizedNutrient struct {
+	Name   string
+	Unit   string
+	Amount float64
+}
+
+// normalizedNutrients returns a food's nutrients converted to common units, keyed by name and unit.
+// When a nutrient is reported in several units (e.g. kcal and kJ energy) the first one wins.
+func normalizedNutrients(food *FoundationFood) (map[string]normalizedNutrient, []string) {
+	nutrients := make(map[string]normalizedNutrient)
+	var order []string
+
+	for _, nutrient := range food.FoodNutrients {
+		amount, unit := normalizeNutrientUnit(nutrient.Amount, nutrient.Nutrient.UnitName)
+		key := nutrientKey(nutrient.Nutrient.Name, unit)
+		if _, ok := nutrients[key]; ok {
+			continue
+		}
+		nutrients[key] = normalizedNutrient{Name: nutrient.Nutrient.Name, Unit: unit, Amount: amount}
+		order = append(order, key)
+	}
+
+	return nutrients, order
+}
+
+// TopNutrientDifferences returns the nutrients that differ most between two foods
+func (e *Engine) TopNutrientDifferences(ctx context.Context, fdcIdA, fdcIdB int, limit int, rankBy string) (*NutrientDifferencesResponse, error) {
+	if rankBy == "" {
+		rankBy = RankByAbsolute
+	}
+	if rankBy != RankByAbsolute && rankBy != RankByPercent {
+		return nil, fmt.Errorf("unknown rank_by %q, expected %q or %q", rankBy, RankByAbsolute, RankByPercent)
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	foodA, err := e.getFoodByFdcId(fdcIdA)
+	if err != nil {
+		return nil, err
+	}
+	foodB, err := e.getFoodByFdcId(fdcIdB)
+	if err != nil {
+		return nil, err
+	}
+
+	nutrientsA, orderA := normalizedNutrients(foodA)
+	nutrientsB, orderB := normalizedNutrients(foodB)
+
+	differences := make([]NutrientDifference, 0, len(orderA)+len(orderB))
+	seen := make(map[string]bool)
+	for _, key := range append(orderA, orderB...) {
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		// Nutrients reported by only one food come back as the zero value, i.e. an amount of zero
+		a, okA := nutrientsA[key]
+		b := nutrientsB[key]
+		nutrient := a
+		if !okA {
+			nutrient = b
+		}
+
+		difference := b.Amount - a.Amount
+		if difference == 0 {
+			continue
+		}
+
+		larger := abs(a.Amount)
+		if abs(b.Amount) > larger {
+			larger = abs(b.Amount)
+		}
+
+		differences = append(differences, NutrientDifference{
+			Name:              nutrient.Name,
+			Unit:              nutrient.Unit,
+			AmountA:           a.Amount,
+			AmountB:           b.Amount,
+			Difference:        difference,
+			PercentDifference: abs(difference) / larger * 100,
+		})
+	}
+
+	sort.SliceStable(differences, func(i, j int) bool {
+		if rankBy == RankByPercent {
+			return differences[i].PercentDifference > differences[j].PercentDifference
+		}
+		return abs(differences[i].Difference) > abs(differences[j].Difference)
+	})
+
+	if limit > 0 && len(differences) > limit {
+		differences = differences[:limit]
+	}
+
+	return &NutrientDifferencesResponse{
+		FoodA:     FoodSummary{FdcId: foodA.FdcId, Description: foodA.Description},
+		FoodB:     FoodSummary{FdcId: foodB.FdcId, Description: foodB.Description},
+		RankBy:    rankBy,
+		Count:     len(differences),
+		Nutrients: differences,
+	}, nil
+}
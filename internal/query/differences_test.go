@@ -0,0 +1,92 @@
+package query
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_TopNutrientDifferences(t *testing.T) {
+	testData := &FoundationFoodsData{
+		FoundationFoods: []FoundationFood{
+			{
+				Description: "Cheese, cheddar",
+				FdcId:       1,
+				FoodNutrients: []FoodNutrient{
+					{Nutrient: Nutrient{Name: "Protein", UnitName: "g"}, Amount: 24},
+					{Nutrient: Nutrient{Name: "Sodium, Na", UnitName: "mg"}, Amount: 650},
+					{Nutrient: Nutrient{Name: "Calcium, Ca", UnitName: "mg"}, Amount: 700},
+				},
+			},
+			{
+				Description: "Milk, whole",
+				FdcId:       2,
+				FoodNutrients: []FoodNutrient{
+					{Nutrient: Nutrient{Name: "Protein", UnitName: "g"}, Amount: 3.3},
+					{Nutrient: Nutrient{Name: "Sodium, Na", UnitName: "mg"}, Amount: 40},
+					{Nutrient: Nutrient{Name: "Calcium, Ca", UnitName: "mg"}, Amount: 120},
+					{Nutrient: Nutrient{Name: "Vitamin D (D2 + D3)", UnitName: "µg"}, Amount: 1.1},
+				},
+			},
+		},
+	}
+
+	engine := &Engine{
+		data:   testData,
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+
+	t.Run("ranks the largest absolute difference first", func(t *testing.T) {
+		result, err := engine.TopNutrientDifferences(context.Background(), 1, 2, 2, RankByAbsolute)
+
+		require.NoError(t, err)
+		require.Len(t, result.Nutrients, 2)
+		assert.Equal(t, "Protein", result.Nutrients[0].Name)
+		assert.InDelta(t, -20.7, result.Nutrients[0].Difference, 0.0001)
+
+		// Milligram amounts are normalized to grams before comparing
+		assert.Equal(t, "Sodium, Na", result.Nutrients[1].Name)
+		assert.Equal(t, "g", result.Nutrients[1].Unit)
+		assert.InDelta(t, 0.65, result.Nutrients[1].AmountA, 0.0001)
+	})
+
+	t.Run("treats nutrients missing from one food as zero", func(t *testing.T) {
+		result, err := engine.TopNutrientDifferences(context.Background(), 1, 2, 1, RankByPercent)
+
+		require.NoError(t, err)
+		require.Len(t, result.Nutrients, 1)
+		assert.Equal(t, "Vitamin D (D2 + D3)", result.Nutrients[0].Name)
+		assert.Equal(t, 0.0, result.Nutrients[0].AmountA)
+		assert.InDelta(t, 100, result.Nutrients[0].PercentDifference, 0.0001)
+	})
+
+	t.Run("rejects unknown ranking", func(t *testing.T) {
+		_, err := engine.TopNutrientDifferences(context.Background(), 1, 2, 5, "bogus")
+
+		assert.Error(t, err)
+	})
+
+	t.Run("returns error for unknown food", func(t *testing.T) {
+		_, err := engine.TopNutrientDifferences(context.Background(), 1, 999, 5, RankByAbsolute)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestNormalizeNutrientUnit(t *testing.T) {
+	amount, unit := normalizeNutrientUnit(250, "mg")
+	assert.InDelta(t, 0.25, amount, 1e-9)
+	assert.Equal(t, "g", unit)
+
+	amount, unit = normalizeNutrientUnit(418.4, "kJ")
+	assert.InDelta(t, 100, amount, 1e-9)
+	assert.Equal(t, "kcal", unit)
+
+	amount, unit = normalizeNutrientUnit(5, "IU")
+	assert.Equal(t, 5.0, amount)
+	assert.Equal(t, "IU", unit)
+}
@@ -0,0 +1,66 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// EnergyBasisKcal is the energy amount nutrients_per_200kcal normalizes every nutrient to
+const EnergyBasisKcal = 200.0
+
+// NutrientsPer200Kcal scales every nutrient of the best match for a name by 200/Energy(kcal), the standard
+// nutrient-density basis for comparing foods of different energy density. Matches ranked above it that
+// don't report kcal energy are skipped and listed in ExcludedFoods.
+func (e *Engine) NutrientsPer200Kcal(ctx context.Context, name string, opts SearchOptions) (*EnergyBasisResponse, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("name must not be empty")
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.data == nil {
+		return nil, fmt.Errorf("foundation Foods data not loaded")
+	}
+
+	results := e.scoreFoods(ctx, name, opts)
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no food matches %q", name)
+	}
+
+	excluded := []FoodSummary{}
+	for _, result := range results {
+		food := result.Food
+
+		energy, ok := energyKcal(food)
+		if !ok || energy <= 0 {
+			excluded = append(excluded, FoodSummary{FdcId: food.FdcId, Description: food.Description})
+			continue
+		}
+
+		factor := EnergyBasisKcal / energy
+		response := &EnergyBasisResponse{
+			FdcId:         food.FdcId,
+			Description:   food.Description,
+			EnergyKcal:    energy,
+			BasisKcal:     EnergyBasisKcal,
+			Grams:         100 * factor,
+			Nutrients:     make([]SimplifiedNutrient, 0, len(food.FoodNutrients)),
+			ExcludedFoods: excluded,
+		}
+		for _, nutrient := range food.FoodNutrients {
+			response.Nutrients = append(response.Nutrients, SimplifiedNutrient{
+				Name:       nutrient.Nutrient.Name,
+				Unit:       nutrient.Nutrient.UnitName,
+				Amount:     nutrient.Amount * factor,
+				DataPoints: nutrient.DataPoints,
+			})
+		}
+
+		return response, nil
+	}
+
+	return nil, fmt.Errorf("none of the %d foods matching %q report Energy in kcal, so they can't be expressed per %g kcal",
+		len(results), name, EnergyBasisKcal)
+}
@@ -0,0 +1,71 @@
+package query
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_NutrientsPer200Kcal(t *testing.T) {
+	testData := &FoundationFoodsData{
+		FoundationFoods: []FoundationFood{
+			{
+				Description: "Kale, raw",
+				FdcId:       1,
+				FoodNutrients: []FoodNutrient{
+					{Nutrient: Nutrient{Name: "Energy", UnitName: "kcal"}, Amount: 50},
+					{Nutrient: Nutrient{Name: "Calcium, Ca", UnitName: "mg"}, Amount: 254},
+				},
+			},
+			{
+				Description: "Kale",
+				FdcId:       2,
+				FoodNutrients: []FoodNutrient{
+					{Nutrient: Nutrient{Name: "Calcium, Ca", UnitName: "mg"}, Amount: 150},
+				},
+			},
+		},
+	}
+
+	engine := &Engine{
+		data:   testData,
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+
+	t.Run("scales calcium by 200 over the energy", func(t *testing.T) {
+		result, err := engine.NutrientsPer200Kcal(context.Background(), "kale raw", SearchOptions{})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.FdcId)
+		assert.InDelta(t, 400, result.Grams, 0.0001)
+		require.Len(t, result.Nutrients, 2)
+		assert.InDelta(t, 200, result.Nutrients[0].Amount, 0.0001)
+		assert.InDelta(t, 1016, result.Nutrients[1].Amount, 0.0001)
+		assert.Empty(t, result.ExcludedFoods)
+	})
+
+	t.Run("skips better matches without energy", func(t *testing.T) {
+		result, err := engine.NutrientsPer200Kcal(context.Background(), "kale", SearchOptions{})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.FdcId)
+		require.Len(t, result.ExcludedFoods, 1)
+		assert.Equal(t, 2, result.ExcludedFoods[0].FdcId)
+	})
+
+	t.Run("explains when no match reports energy", func(t *testing.T) {
+		engine := &Engine{
+			data:   &FoundationFoodsData{FoundationFoods: testData.FoundationFoods[1:]},
+			logger: config.NewTestLogger(io.Discard, "debug"),
+		}
+
+		_, err := engine.NutrientsPer200Kcal(context.Background(), "kale", SearchOptions{})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "report Energy in kcal")
+	})
+}
@@ -0,0 +1,41 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Energy units for SearchOptions.EnergyUnit
+const (
+	EnergyUnitKcal = "kcal"
+	EnergyUnitKJ   = "kj"
+	EnergyUnitBoth = "both"
+)
+
+// ValidateEnergyUnit reports an error for unknown energy units. An empty unit means kcal.
+func ValidateEnergyUnit(unit string) error {
+	switch unit {
+	case "", EnergyUnitKcal, EnergyUnitKJ, EnergyUnitBoth:
+		return nil
+	default:
+		return fmt.Errorf("unknown energy unit %q, expected %q, %q or %q", unit, EnergyUnitKcal, EnergyUnitKJ, EnergyUnitBoth)
+	}
+}
+
+// keepEnergyEntry reports whether a simplified response keeps a nutrient under the requested energy unit.
+// Only the "Energy" nutrient is reported in both kcal and kJ, so every other nutrient is kept.
+func keepEnergyEntry(nutrient Nutrient, energyUnit string) bool {
+	if !strings.EqualFold(strings.TrimSpace(nutrient.Name), "energy") {
+		return true
+	}
+
+	unit := strings.ToLower(strings.TrimSpace(nutrient.UnitName))
+	switch energyUnit {
+	case EnergyUnitBoth:
+		return true
+	case EnergyUnitKJ:
+		return unit == EnergyUnitKJ
+	default:
+		return unit != EnergyUnitKJ
+	}
+}
@@ -0,0 +1,77 @@
+package query
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_SearchFoodsByNameSimplified_EnergyUnit(t *testing.T) {
+	engine := &Engine{
+		data: &FoundationFoodsData{
+			FoundationFoods: []FoundationFood{
+				{
+					Description: "Almonds, raw",
+					FdcId:       1,
+					FoodNutrients: []FoodNutrient{
+						{Nutrient: Nutrient{Name: "Energy", UnitName: "kcal"}, Amount: 579},
+						{Nutrient: Nutrient{Name: "Energy", UnitName: "kJ"}, Amount: 2423},
+						{Nutrient: Nutrient{Name: "Energy (Atwater General Factors)", UnitName: "kcal"}, Amount: 620},
+						{Nutrient: Nutrient{Name: "Protein", UnitName: "g"}, Amount: 21.2},
+					},
+				},
+			},
+		},
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+
+	units := func(t *testing.T, energyUnit string) []string {
+		t.Helper()
+		result, err := engine.SearchFoodsByNameSimplified(context.Background(), "almonds", 1, []string{"Energy", "Protein"}, SearchOptions{EnergyUnit: energyUnit})
+		require.NoError(t, err)
+		require.Len(t, result.Foods, 1)
+
+		var energyUnits []string
+		for _, nutrient := range result.Foods[0].Nutrients {
+			if nutrient.Name == "Energy" {
+				energyUnits = append(energyUnits, nutrient.Unit)
+			}
+		}
+		return energyUnits
+	}
+
+	t.Run("keeps kcal by default", func(t *testing.T) {
+		assert.Equal(t, []string{"kcal"}, units(t, ""))
+		assert.Equal(t, []string{"kcal"}, units(t, EnergyUnitKcal))
+	})
+
+	t.Run("keeps kJ when asked", func(t *testing.T) {
+		assert.Equal(t, []string{"kJ"}, units(t, EnergyUnitKJ))
+	})
+
+	t.Run("keeps both when asked", func(t *testing.T) {
+		assert.Equal(t, []string{"kcal", "kJ"}, units(t, EnergyUnitBoth))
+	})
+
+	t.Run("leaves other nutrients alone", func(t *testing.T) {
+		result, err := engine.SearchFoodsByNameSimplified(context.Background(), "almonds", 1, nil, SearchOptions{EnergyUnit: EnergyUnitKJ})
+		require.NoError(t, err)
+
+		names := []string{}
+		for _, nutrient := range result.Foods[0].Nutrients {
+			names = append(names, nutrient.Name+" "+nutrient.Unit)
+		}
+		assert.Equal(t, []string{"Energy kJ", "Energy (Atwater General Factors) kcal", "Protein g"}, names)
+	})
+}
+
+func TestValidateEnergyUnit(t *testing.T) {
+	for _, unit := range []string{"", EnergyUnitKcal, EnergyUnitKJ, EnergyUnitBoth} {
+		assert.NoError(t, ValidateEnergyUnit(unit), unit)
+	}
+	assert.ErrorContains(t, ValidateEnergyUnit("calories"), "unknown energy unit")
+}
@@ -1,110 +1,558 @@
 package query
 
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"io/fs"
 	"log/slog"
 	"os"
+	"runtime"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 )
 
 // Engine implements the QueryEngine interface for Foundation Foods data
 type Engine struct {
+	// mu guards data and every index derived from it; read methods take the read lock
+	mu     sync.RWMutex
 	data   *FoundationFoodsData
 	logger *slog.Logger
+
+	// jsonFilePath is the dataset file in datasetFS, re-read by Reload; reloadMu keeps reloads from overlapping
+	jsonFilePath string
+	datasetFS    fs.FS
+	reloadMu     sync.Mutex
+
+	// maxFoods caps how many foods are loaded from the dataset (0 means no cap)
+	maxFoods int
+
+	// rebuildConcurrency bounds the goroutines rebuilding derived indexes on a swap (0 means 1)
+	rebuildConcurrency int
+
+	// dropInvalidPortions removes portions without a positive, finite gram weight at load time
+	dropInvalidPortions bool
+
+	// upperLimits holds the Tolerable Upper Intake Levels by lowercased nutrient name (nil uses DefaultUpperLimits)
+	upperLimits map[string]UpperLimit
+
+	// synonyms maps normalized query words to the synonym phrases they also match (nil uses DefaultSynonyms);
+	// synonymsFile, when set, is re-read into synonyms by RefreshNormalization
+	synonyms     map[string][][]string
+	synonymsFile string
+
+	// weights tunes relevance scoring (nil uses DefaultScoringWeights); weightsFile, when set, is re-read
+	// into weights by RefreshNormalization
+	weights     *ScoringWeights
+	weightsFile string
+
+	// limits bounds how many foods a name search returns (nil uses DefaultSearchLimits)
+	limits *SearchLimits
+
+	// datasetDate labels the current dataset release; history holds older releases loaded from historyFiles
+	datasetDate  string
+	historyFiles []string
+	history      []*datasetSnapshot
+
+	// normalizedDescriptions holds the precomputed normalized form of each food description
+	normMu          sync.Mutex
+	normalizedForms []string
+
+	// categoryAverages caches per-category nutrient means keyed by lowercased category
+	categoryMu       sync.Mutex
+	categoryAverages map[string]map[string]nutrientAverage
+
+	// nutrientDistributions caches each nutrient's sorted amounts across the dataset for percentile ranks
+	percentileMu          sync.Mutex
+	nutrientDistributions map[string][]float64
+
+	// ingredientIndex maps normalized input food words to the foods listing them
+	ingredientMu    sync.Mutex
+	ingredientIndex map[string][]ingredientPosting
+
+	// units caches the distinct nutrient and portion units with their usage counts
+	unitsMu sync.Mutex
+	units   *UnitListResponse
+
+	// searchCache holds recent search results (nil when caching is disabled)
+	searchCache *searchCache
+}
+
+// EngineOption configures optional Engine behavior
+type EngineOption func(*Engine)
+
+// WithMaxFoods limits how many foods are loaded from the dataset (0 loads everything)
+func WithMaxFoods(maxFoods int) EngineOption {
+	return func(e *Engine) {
+		e.maxFoods = maxFoods
+	}
+}
+
+// WithDatasetFS reads the dataset from fsys instead of the operating system's filesystem, e.g. a dataset
+// embedded into the binary
+func WithDatasetFS(fsys fs.FS) EngineOption {
+	return func(e *Engine) {
+		if fsys != nil {
+			e.datasetFS = fsys
+		}
+	}
 }
 
 // NewEngine creates a new query engine and loads the Foundation Foods data
-func NewEngine(jsonFilePath string, logger *slog.Logger) (*Engine, error) {
-	logger.Info("Loading Foundation Foods data", "path", jsonFilePath)
+func NewEngine(jsonFilePath string, logger *slog.Logger, opts ...EngineOption) (*Engine, error) {
+	engine := &Engine{logger: logger, jsonFilePath: jsonFilePath, datasetFS: osFS{}}
+	for _, opt := range opts {
+		opt(engine)
+	}
+
+	inputs, err := engine.loadNormalizationInputs()
+	if err != nil {
+		return nil, err
+	}
+	engine.applyNormalizationInputs(inputs)
+
+	foundationFoodsData, err := engine.loadData()
+	if err != nil {
+		return nil, err
+	}
+
+	engine.datasetDate = datasetDate(jsonFilePath)
+
+	for _, path := range engine.historyFiles {
+		snapshot, err := loadSnapshot(path)
+		if err != nil {
+			return nil, err
+		}
+		engine.history = append(engine.history, snapshot)
+
+		logger.Info("History dataset loaded",
+			"path", path,
+			"dataset", snapshot.date,
+			"food_count", len(snapshot.foods))
+	}
 
-	// Read the JSON file
-	data, err := os.ReadFile(jsonFilePath)
+	engine.swapData(foundationFoodsData)
+
+	return engine, nil
+}
+
+// loadData reads and prepares the dataset at jsonFilePath, applying the load-time options
+func (e *Engine) loadData() (*FoundationFoodsData, error) {
+	e.logger.Info("Loading Foundation Foods data", "path", e.jsonFilePath)
+
+	// Open the JSON file, decompressing it on the fly when gzipped
+	file, err := openDataset(e.datasetFS, e.jsonFilePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read Foundation Foods data file: %w", err)
 	}
+	defer file.Close()
 
-	// Parse the JSON
-	var foundationFoodsData FoundationFoodsData
-	if err := json.Unmarshal(data, &foundationFoodsData); err != nil {
+	// Stream-parse the JSON
+	data, err := decodeFoundationFoods(file, e.maxFoods)
+	if err != nil {
 		return nil, fmt.Errorf("failed to parse Foundation Foods JSON data: %w", err)
 	}
 
-	logger.Info("Foundation Foods data loaded successfully",
-		"food_count", len(foundationFoodsData.FoundationFoods))
+	if e.maxFoods > 0 {
+		e.logger.Warn("Foundation Foods dataset is sampled, search results will be partial",
+			"max_foods_to_load", e.maxFoods)
+	}
+
+	e.logger.Info("Foundation Foods data loaded successfully",
+		"food_count", len(data.FoundationFoods))
 
-	return &Engine{
-		data:   &foundationFoodsData,
-		logger: logger,
-	}, nil
+	if e.dropInvalidPortions {
+		dropped := dropInvalidPortions(data.FoundationFoods, e.logger)
+		e.logger.Info("Dropped portions with an invalid gram weight",
+			"portion_count", dropped)
+	}
+
+	return data, nil
+}
+
+// Reload re-reads the dataset file and swaps it in. Searches keep using the old dataset until the new one
+// is loaded and indexed; when loading fails the old dataset stays in place.
+func (e *Engine) Reload(ctx context.Context) error {
+	e.reloadMu.Lock()
+	defer e.reloadMu.Unlock()
+
+	data, err := e.loadData()
+	if err != nil {
+		return err
+	}
+
+	e.mu.RLock()
+	oldCount := 0
+	if e.data != nil {
+		oldCount = len(e.data.FoundationFoods)
+	}
+	e.mu.RUnlock()
+
+	e.swapData(data)
+
+	e.logger.InfoContext(ctx, "Foundation Foods data reloaded",
+		"path", e.jsonFilePath,
+		"old_food_count", oldCount,
+		"new_food_count", len(data.FoundationFoods))
+
+	return nil
+}
+
+// swapData atomically replaces the dataset, rebuilding derived indexes before taking the write lock
+// so concurrent searches keep using the old snapshot until the swap completes
+func (e *Engine) swapData(data *FoundationFoodsData) {
+	start := time.Now()
+	indexes := buildDerivedIndexes(data.FoundationFoods, e.rebuildWorkers())
+
+	// HeapSys never shrinks, so it approximates the peak heap size the rebuild needed
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	e.logger.Info("Derived indexes rebuilt",
+		"food_count", len(data.FoundationFoods),
+		"workers", e.rebuildWorkers(),
+		"duration", time.Since(start),
+		"heap_alloc_bytes", mem.HeapAlloc,
+		"heap_sys_bytes", mem.HeapSys)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.data = data
+	e.normalizedForms = indexes.normalized
+
+	e.categoryMu.Lock()
+	e.categoryAverages = nil
+	e.categoryMu.Unlock()
+
+	e.ingredientMu.Lock()
+	e.ingredientIndex = indexes.ingredientIndex
+	e.ingredientMu.Unlock()
+
+	e.percentileMu.Lock()
+	e.nutrientDistributions = nil
+	e.percentileMu.Unlock()
+
+	e.unitsMu.Lock()
+	e.units = nil
+	e.unitsMu.Unlock()
+
+	if e.searchCache != nil {
+		e.searchCache.clear()
+	}
+}
+
+// gzipMagic is the header every gzip stream starts with
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// datasetFile is an open dataset whose reads may go through a gzip decompressor
+type datasetFile struct {
+	io.Reader
+	closers []io.Closer
+}
+
+// Close closes the decompressor, if any, and then the file
+func (f *datasetFile) Close() error {
+	var firstErr error
+	for _, closer := range f.closers {
+		if err := closer.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// osFS opens files from the operating system's filesystem. Unlike os.DirFS it accepts any path the
+// os package does, absolute or relative, so configured dataset paths keep working unchanged.
+type osFS struct{}
+
+// Open opens the named file with os.Open
+func (osFS) Open(name string) (fs.File, error) {
+	return os.Open(name)
+}
+
+// openDataset opens a dataset file in fsys for streaming. Gzipped files are recognized by their magic bytes,
+// whatever their extension, and decompressed as they are read; anything else is read as is.
+func openDataset(fsys fs.FS, path string) (io.ReadCloser, error) {
+	file, err := fsys.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	buffered := bufio.NewReader(file)
+	header, err := buffered.Peek(len(gzipMagic))
+	if err != nil && err != io.EOF {
+		file.Close()
+		return nil, err
+	}
+	if !bytes.Equal(header, gzipMagic) {
+		return &datasetFile{Reader: buffered, closers: []io.Closer{file}}, nil
+	}
+
+	decompressed, err := gzip.NewReader(buffered)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("invalid gzip data: %w", err)
+	}
+	return &datasetFile{Reader: decompressed, closers: []io.Closer{decompressed, file}}, nil
+}
+
+// decodeFoundationFoods stream-parses the dataset, stopping after maxFoods foods when maxFoods > 0
+func decodeFoundationFoods(r io.Reader, maxFoods int) (*FoundationFoodsData, error) {
+	decoder := json.NewDecoder(r)
+
+	if err := expectDelim(decoder, '{'); err != nil {
+		return nil, err
+	}
+
+	data := &FoundationFoodsData{}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		key, ok := token.(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected token %v", token)
+		}
+
+		// Skip any top-level field that isn't the foods array
+		if key != "FoundationFoods" {
+			var skipped json.RawMessage
+			if err := decoder.Decode(&skipped); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if err := expectDelim(decoder, '['); err != nil {
+			return nil, err
+		}
+
+		for decoder.More() {
+			if maxFoods > 0 && len(data.FoundationFoods) >= maxFoods {
+				return data, nil
+			}
+
+			var food FoundationFood
+			if err := decoder.Decode(&food); err != nil {
+				return nil, err
+			}
+			data.FoundationFoods = append(data.FoundationFoods, food)
+		}
+
+		if err := expectDelim(decoder, ']'); err != nil {
+			return nil, err
+		}
+	}
+
+	return data, nil
+}
+
+// expectDelim reads the next token and checks that it is the given JSON delimiter
+func expectDelim(decoder *json.Decoder, delim json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("expected %q but found %v", delim, token)
+	}
+	return nil
 }
 
 // SearchFoodsByName searches for foods by their description using intelligent scoring
-func (e *Engine) SearchFoodsByName(ctx context.Context, query string, limit int) ([]FoundationFood, error) {
+func (e *Engine) SearchFoodsByName(ctx context.Context, query string, limit int, opts SearchOptions) ([]FoundationFood, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	foods, _, err := e.searchFoodsByName(ctx, query, limit, opts)
+	return foods, err
+}
+
+// SearchFoodsByNameWithTotal searches like SearchFoodsByName and also returns how many foods matched
+// across all pages, counted from the same scoring pass, so callers paging through results know whether
+// more pages exist
+func (e *Engine) SearchFoodsByNameWithTotal(ctx context.Context, query string, limit int, opts SearchOptions) ([]FoundationFood, int, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.searchFoodsByName(ctx, query, limit, opts)
+}
+
+// searchFoodsByName implements SearchFoodsByNameWithTotal; the caller must hold the read lock
+func (e *Engine) searchFoodsByName(ctx context.Context, query string, limit int, opts SearchOptions) ([]FoundationFood, int, error) {
 	if e.data == nil {
-		return nil, fmt.Errorf("foundation Foods data not loaded")
+		return nil, 0, fmt.Errorf("foundation Foods data not loaded")
 	}
 
-	if limit <= 0 {
-		limit = 3
+	if err := ValidateSortBy(opts.SortBy); err != nil {
+		return nil, 0, err
 	}
-	if limit > 10 {
-		limit = 10
+
+	limit = e.searchLimits().Clamp(limit)
+
+	var cacheKey searchCacheKey
+	if e.searchCache != nil {
+		cacheKey = searchCacheKey{query: normalizeString(query), limit: limit, opts: opts}
+		if foods, total, ok := e.searchCache.get(cacheKey); ok {
+			e.logger.DebugContext(ctx, "Search served from cache",
+				"query", query,
+				"results_returned", len(foods))
+			return foods, total, nil
+		}
 	}
 
-	e.logger.Debug("Searching Foundation Foods",
+	e.logger.DebugContext(ctx, "Searching Foundation Foods",
 		"query", query,
 		"limit", limit,
+		"category", opts.Category,
 		"total_foods", len(e.data.FoundationFoods))
 
-	// Normalize the search query
-	normalizedQuery := normalizeString(query)
-	queryWords := strings.Fields(normalizedQuery)
+	foods, total := e.rankedFoods(ctx, query, limit, opts)
 
-	var results []SearchResult
+	// A search cut short by the response budget is partial, so it isn't worth repeating
+	if e.searchCache != nil && !BudgetExceeded(ctx) {
+		e.searchCache.put(cacheKey, foods, total)
+	}
 
-	// Search through all foods
-	for _, food := range e.data.FoundationFoods {
-		score := calculateRelevanceScore(food.Description, normalizedQuery, queryWords)
-		if score > 0 {
-			results = append(results, SearchResult{
-				Food:  food,
-				Score: score,
-			})
-		}
+	return foods, total, nil
+}
+
+// rankedFoods scores the dataset against the query and returns the requested page of matches along with
+// the number of matches across all pages; the caller must hold the read lock
+func (e *Engine) rankedFoods(ctx context.Context, query string, limit int, opts SearchOptions) ([]FoundationFood, int) {
+	results := e.scoreFoods(ctx, query, opts)
+	e.sortResults(results, opts.SortBy)
+	total := len(results)
+
+	// Fall back to the nearest fuzzy matches rather than returning nothing
+	if len(results) == 0 && opts.ReturnNearestOnEmpty && !opts.Exact && opts.Offset == 0 && !BudgetExceeded(ctx) {
+		foods := e.nearestFoods(query, limit, opts)
+
+		e.logger.DebugContext(ctx, "Search matched nothing, returning fuzzy fallbacks",
+			"query", query,
+			"results_returned", len(foods))
+
+		return foods, total
 	}
 
-	// Sort by score (highest first)
-	sort.Slice(results, func(i, j int) bool {
-		return results[i].Score > results[j].Score
-	})
+	// Skip the ranked results already returned on earlier pages
+	if opts.Offset > 0 {
+		if opts.Offset >= len(results) {
+			results = nil
+		} else {
+			results = results[opts.Offset:]
+		}
+	}
 
 	// Extract top results
+	queryWords := strings.Fields(normalizeString(query))
 	var foods []FoundationFood
 	for i, result := range results {
 		if i >= limit {
 			break
 		}
-		foods = append(foods, result.Food)
 
-		e.logger.Debug("Search result",
-			"rank", i+1,
+		food := result.Food
+		if opts.IncludeScores {
+			score := result.Score
+			food.Score = &score
+		}
+		if opts.IncludeHighlights {
+			food.Highlights = matchHighlights(food.Description, queryWords, opts.Fuzzy, e.synonymTable())
+		}
+		foods = append(foods, food)
+
+		e.logger.DebugContext(ctx, "Search result",
+			"rank", opts.Offset+i+1,
 			"score", result.Score,
 			"description", result.Food.Description)
 	}
 
-	e.logger.Debug("Search complete",
+	e.logger.DebugContext(ctx, "Search complete",
 		"query", query,
 		"results_found", len(results),
 		"results_returned", len(foods))
 
-	return foods, nil
+	return foods, total
+}
+
+// scoreFoods scores every food against the query and returns the matches sorted by score (highest first)
+func (e *Engine) scoreFoods(ctx context.Context, query string, opts SearchOptions) []SearchResult {
+	// Normalize the search query
+	normalizedQuery := normalizeString(query)
+	queryWords := strings.Fields(normalizedQuery)
+
+	var results []SearchResult
+
+	normalizedDescriptions := e.normalizedDescriptions()
+	synonyms := e.synonymTable()
+	weights := e.scoringWeights()
+
+	// Search through all foods, keeping what was scored so far if the response budget runs out
+	for i, food := range e.data.FoundationFoods {
+		if (i+1)%budgetCheckInterval == 0 && BudgetExceeded(ctx) {
+			e.logger.WarnContext(ctx, "Search stopped at response budget",
+				"query", query,
+				"scanned", i,
+				"food_count", len(e.data.FoundationFoods))
+			break
+		}
+
+		if !matchesCategory(food, opts.Category) {
+			continue
+		}
+
+		// Exact mode skips relevance scoring: a food either has the query as its description or doesn't match
+		if opts.Exact {
+			if normalizedDescriptions[i] == normalizedQuery {
+				results = append(results, SearchResult{Food: food, Score: weights.ExactDescription})
+			}
+			continue
+		}
+
+		score := weights.scoreDescription(normalizedDescriptions[i], normalizedQuery, queryWords, opts.Fuzzy, synonyms, nil)
+		score = adjustScoreForPreparation(normalizedDescriptions[i], opts.Preparation, score, nil)
+		if score > 0 && score >= opts.MinScore {
+			results = append(results, SearchResult{
+				Food:  food,
+				Score: score,
+			})
+		}
+	}
+
+	// Sort by score (highest first), breaking ties by description and then FDC ID so equal scores
+	// always come back in the same order
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		if results[i].Food.Description != results[j].Food.Description {
+			return results[i].Food.Description < results[j].Food.Description
+		}
+		return results[i].Food.FdcId < results[j].Food.FdcId
+	})
+
+	return results
 }
 
 // GetFoodByFdcId retrieves a specific food by its FDC ID
 func (e *Engine) GetFoodByFdcId(ctx context.Context, fdcId int) (*FoundationFood, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.getFoodByFdcId(fdcId)
+}
+
+// getFoodByFdcId implements GetFoodByFdcId; the caller must hold the read lock
+func (e *Engine) getFoodByFdcId(fdcId int) (*FoundationFood, error) {
 	if e.data == nil {
 		return nil, fmt.Errorf("foundation Foods data not loaded")
 	}
@@ -120,6 +568,9 @@ func (e *Engine) GetFoodByFdcId(ctx context.Context, fdcId int) (*FoundationFood
 
 // Health checks if the query engine is ready and operational
 func (e *Engine) Health(ctx context.Context) error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
 	if e.data == nil {
 		return fmt.Errorf("foundation Foods data not loaded")
 	}
@@ -132,27 +583,49 @@ func (e *Engine) Health(ctx context.Context) error {
 }
 
 // SearchFoodsByNameSimplified searches for foods and returns simplified nutrient information
-func (e *Engine) SearchFoodsByNameSimplified(ctx context.Context, query string, limit int, nutrientsToInclude []string) (*SimplifiedNutrientResponse, error) {
+func (e *Engine) SearchFoodsByNameSimplified(ctx context.Context, query string, limit int, nutrientsToInclude []string, opts SearchOptions) (*SimplifiedNutrientResponse, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
 	// Use the existing search functionality
-	foods, err := e.SearchFoodsByName(ctx, query, limit)
+	foods, _, err := e.searchFoodsByName(ctx, query, limit, opts)
 	if err != nil {
 		return nil, err
 	}
 
+	nutrientsToInclude = sanitizeNutrientFilters(nutrientsToInclude)
+
+	// Resolve the reference food that nutrient ratios are expressed against
+	var reference *FoundationFood
+	var referenceAmounts map[string]float64
+	if opts.ReferenceFdcId > 0 {
+		reference, err = e.getFoodByFdcId(opts.ReferenceFdcId)
+		if err != nil {
+			return nil, fmt.Errorf("reference food: %w", err)
+		}
+		referenceAmounts = nutrientAmounts(*reference)
+	}
+
 	// Convert to simplified format
 	simplifiedFoods := make([]SimplifiedFood, 0, len(foods))
 	for _, food := range foods {
 		simplifiedFood := SimplifiedFood{
-			Name:         food.Description,
-			Nutrients:    make([]SimplifiedNutrient, 0, len(food.FoodNutrients)),
-			FoodPortions: make([]SimplifiedFoodPortion, 0, len(food.FoodPortions)),
+			Name:          food.Description,
+			FdcId:         food.FdcId,
+			Nutrients:     make([]SimplifiedNutrient, 0, len(food.FoodNutrients)),
+			FoodPortions:  make([]SimplifiedFoodPortion, 0, len(food.FoodPortions)),
+			FuzzyFallback: food.FuzzyFallback,
 		}
 
 		// Convert nutrients to simplified format with filtering
 		for _, nutrient := range food.FoodNutrients {
-			// Skip Energy in kJ - we only want kcal
-			if strings.ToLower(strings.TrimSpace(nutrient.Nutrient.Name)) == "energy" &&
-				strings.ToLower(strings.TrimSpace(nutrient.Nutrient.UnitName)) == "kj" {
+			// Keep only the Energy entry in the requested unit (kcal unless kJ or both were asked for)
+			if !keepEnergyEntry(nutrient.Nutrient, opts.EnergyUnit) {
+				continue
+			}
+
+			// Hide nutrients the food isn't a notable source of when only notable nutrients are requested
+			if opts.NotableOnly && e.percentileRank(nutrient.Nutrient.Name, nutrient.Nutrient.UnitName, nutrient.Amount) < opts.notablePercentile() {
 				continue
 			}
 
@@ -164,12 +637,27 @@ func (e *Engine) SearchFoodsByNameSimplified(ctx context.Context, query string,
 					Amount:     nutrient.Amount,
 					DataPoints: nutrient.DataPoints,
 				}
+
+				if opts.IncludeDerivation {
+					simplifiedNutrient.Derivation = nutrientDerivation(nutrient.FoodNutrientDerivation)
+				}
+
+				// Nutrients the reference lacks (or has none of) are left without a ratio
+				if referenceAmount, ok := referenceAmounts[nutrientKey(nutrient.Nutrient.Name, nutrient.Nutrient.UnitName)]; ok && referenceAmount != 0 {
+					ratio := nutrient.Amount / referenceAmount
+					simplifiedNutrient.RelativeToReference = &ratio
+				}
+
 				simplifiedFood.Nutrients = append(simplifiedFood.Nutrients, simplifiedNutrient)
 			}
 		}
 
-		// Convert food portions to simplified format
-		for _, portion := range food.FoodPortions {
+		if opts.MergeDuplicateNutrients {
+			simplifiedFood.Nutrients = mergeDuplicateNutrients(simplifiedFood.Nutrients, opts.MergeStrategy)
+		}
+
+		// Convert food portions to simplified format in USDA's intended display order
+		for _, portion := range sortedPortions(food.FoodPortions) {
 			simplifiedPortion := SimplifiedFoodPortion{
 				Value: portion.Value,
 				MeasureUnit: SimplifiedMeasureUnit{
@@ -179,17 +667,51 @@ func (e *Engine) SearchFoodsByNameSimplified(ctx context.Context, query string,
 				GramWeight: portion.GramWeight,
 				Amount:     portion.Amount,
 			}
+			if opts.IncludeSequence {
+				simplifiedPortion.SequenceNumber = portion.SequenceNumber
+			}
 			simplifiedFood.FoodPortions = append(simplifiedFood.FoodPortions, simplifiedPortion)
 		}
 
 		simplifiedFoods = append(simplifiedFoods, simplifiedFood)
 	}
 
-	return &SimplifiedNutrientResponse{
+	response := &SimplifiedNutrientResponse{
 		Found: len(simplifiedFoods) > 0,
 		Count: len(simplifiedFoods),
 		Foods: simplifiedFoods,
-	}, nil
+	}
+	if len(foods) > 0 {
+		response.UnmatchedFilters = e.unmatchedNutrientFilters(foods, nutrientsToInclude)
+	}
+	if reference != nil {
+		response.Reference = &ReferenceFood{
+			FdcId:       reference.FdcId,
+			Description: reference.Description,
+		}
+	}
+
+	return response, nil
+}
+
+// nutrientAmounts indexes a food's nutrient amounts by name and unit, keeping the first entry of each
+func nutrientAmounts(food FoundationFood) map[string]float64 {
+	amounts := make(map[string]float64, len(food.FoodNutrients))
+	for _, nutrient := range food.FoodNutrients {
+		key := nutrientKey(nutrient.Nutrient.Name, nutrient.Nutrient.UnitName)
+		if _, ok := amounts[key]; !ok {
+			amounts[key] = nutrient.Amount
+		}
+	}
+	return amounts
+}
+
+// matchesCategory reports whether a food belongs to the given category (an empty category matches everything)
+func matchesCategory(food FoundationFood, category string) bool {
+	if strings.TrimSpace(category) == "" {
+		return true
+	}
+	return normalizeCategory(food.FoodCategory.Description) == normalizeCategory(category)
 }
 
 // normalizeString normalizes a string for better searching
@@ -203,12 +725,34 @@ func normalizeString(s string) string {
 	s = strings.ReplaceAll(s, "(", "")
 	s = strings.ReplaceAll(s, ")", "")
 
+	// Spell out ampersands so "Dairy & Egg" and "Dairy and Egg" normalize the same
+	if strings.Contains(s, "&") {
+		s = strings.Join(strings.Fields(strings.ReplaceAll(s, "&", " and ")), " ")
+	}
+
 	return s
 }
 
+// normalizeCategory normalizes a category description for comparison
+func normalizeCategory(category string) string {
+	return normalizeString(category)
+}
+
 // calculateRelevanceScore calculates how relevant a food description is to a search query
 func calculateRelevanceScore(description, normalizedQuery string, queryWords []string) float64 {
-	normalizedDesc := normalizeString(description)
+	return scoreNormalizedDescription(normalizeString(description), normalizedQuery, queryWords)
+}
+
+// scoreNormalizedDescription scores an already normalized description against a search query
+func scoreNormalizedDescription(normalizedDesc, normalizedQuery string, queryWords []string) float64 {
+	return DefaultScoringWeights.scoreDescription(normalizedDesc, normalizedQuery, queryWords, false, nil, nil)
+}
+
+// scoreDescription implements scoreNormalizedDescription with these weights. A query word that matches no
+// description word exactly, as a plural, by prefix or as a substring earns SynonymWord when one of its
+// synonyms is in the description, and with fuzzy set, a partial score for a close misspelling. Each
+// contribution is recorded in breakdown unless it is nil.
+func (w ScoringWeights) scoreDescription(normalizedDesc, normalizedQuery string, queryWords []string, fuzzy bool, synonyms map[string][][]string, breakdown *ScoreBreakdown) float64 {
 	descWords := strings.Fields(normalizedDesc)
 
 	// No match if no words to compare
@@ -220,17 +764,20 @@ func calculateRelevanceScore(description, normalizedQuery string, queryWords []s
 
 	// 1. Exact match (highest priority)
 	if normalizedDesc == normalizedQuery {
-		score += 1000
+		score += w.ExactDescription
+		breakdown.add("exact description match", w.ExactDescription, score)
 	}
 
 	// 2. Query appears as substring at the beginning of description
 	if strings.HasPrefix(normalizedDesc, normalizedQuery) {
-		score += 500
+		score += w.DescriptionPrefix
+		breakdown.add("description starts with the query", w.DescriptionPrefix, score)
 	}
 
 	// 3. Query appears as substring anywhere
 	if strings.Contains(normalizedDesc, normalizedQuery) {
-		score += 100
+		score += w.DescriptionSubstring
+		breakdown.add("description contains the query", w.DescriptionSubstring, score)
 	}
 
 	// 4. Word-level matching
@@ -239,30 +786,23 @@ func calculateRelevanceScore(description, normalizedQuery string, queryWords []s
 
 	for _, queryWord := range queryWords {
 		bestWordScore := 0.0
+		bestMatch, bestDescWord := "", ""
 
 		for i, descWord := range descWords {
-			wordScore := 0.0
-
-			// Exact word match
-			if descWord == queryWord {
-				wordScore = 50
-				// Bonus for position (earlier words are more important)
-				if i < 3 {
-					wordScore += float64(3-i) * 10
-				}
-			} else if strings.HasPrefix(descWord, queryWord) && len(queryWord) >= 3 {
-				// Prefix match (for partial words)
-				wordScore = 25
-				if i < 3 {
-					wordScore += float64(3-i) * 5
-				}
-			} else if strings.Contains(descWord, queryWord) && len(queryWord) >= 4 {
-				// Substring match (less reliable)
-				wordScore = 10
+			match := matchWord(queryWord, descWord)
+			wordScore := w.wordMatchScore(match, i)
+			if wordScore > bestWordScore {
+				bestWordScore, bestMatch, bestDescWord = wordScore, match, descWord
 			}
+		}
 
-			if wordScore > bestWordScore {
-				bestWordScore = wordScore
+		if bestWordScore == 0 && matchesSynonym(synonyms[queryWord], descWords) {
+			bestWordScore, bestMatch = w.SynonymWord, MatchSynonym
+		}
+
+		if bestWordScore == 0 && fuzzy {
+			if bestWordScore = w.fuzzyWordScore(queryWord, descWords); bestWordScore > 0 {
+				bestMatch = MatchFuzzy
 			}
 		}
 
@@ -270,35 +810,89 @@ func calculateRelevanceScore(description, normalizedQuery string, queryWords []s
 			matchedWords++
 			score += bestWordScore
 		}
+		breakdown.word(queryWord, bestMatch, bestDescWord, bestWordScore, score)
 	}
 
 	// 5. Bonus for matching multiple words
 	if totalQueryWords > 1 {
 		matchRatio := float64(matchedWords) / float64(totalQueryWords)
 		score *= (1 + matchRatio) // Boost score based on word match ratio
+		breakdown.multiply(fmt.Sprintf("matched %d of %d query words", matchedWords, totalQueryWords), 1+matchRatio, score)
 	}
 
 	// 6. Penalty for very long descriptions that match incidentally
 	if len(descWords) > 10 && matchedWords < totalQueryWords {
-		score *= 0.8
+		score *= w.LongDescriptionPenalty
+		breakdown.multiply("long description missing query words", w.LongDescriptionPenalty, score)
 	}
 
 	// 7. Specific food search improvements
-	score = adjustScoreForFoodContext(description, normalizedQuery, queryWords, score)
+	score = w.adjustScoreForFoodContext(normalizedDesc, normalizedQuery, queryWords, score, breakdown)
 
 	return score
 }
 
-// adjustScoreForFoodContext applies food-specific scoring adjustments
-func adjustScoreForFoodContext(description, normalizedQuery string, queryWords []string, currentScore float64) float64 {
-	normalizedDesc := normalizeString(description)
+// Kinds of word matches, from strongest to weakest. Literal matches are exact, plural, prefix and partial;
+// synonym and fuzzy matches only apply to query words without a literal match.
+const (
+	MatchExact   = "exact"
+	MatchPlural  = "plural"
+	MatchPrefix  = "prefix"
+	MatchPartial = "partial"
+	MatchSynonym = "synonym"
+	MatchFuzzy   = "fuzzy"
+)
+
+// matchWord returns how a query word literally matches a description word: exactly, as its singular or
+// plural, as a prefix (query words of 3+ letters) or as a substring (4+ letters). It returns an empty
+// string when the words don't match.
+func matchWord(queryWord, descWord string) string {
+	switch {
+	case descWord == queryWord:
+		return MatchExact
+	case pluralEquivalent(descWord, queryWord):
+		return MatchPlural
+	case strings.HasPrefix(descWord, queryWord) && len(queryWord) >= 3:
+		return MatchPrefix
+	case strings.Contains(descWord, queryWord) && len(queryWord) >= 4:
+		return MatchPartial
+	}
+	return ""
+}
+
+// wordMatchScore is what a literal word match earns when the description word is at position i. Matches
+// among the first three description words get a position bonus, since earlier words are more important.
+func (w ScoringWeights) wordMatchScore(kind string, i int) float64 {
+	position := 0.0
+	if i < 3 {
+		position = float64(3 - i)
+	}
+
+	switch kind {
+	case MatchExact:
+		return w.ExactWord + position*w.ExactWordPositionBonus
+	case MatchPlural:
+		// A singular/plural match ("egg" for "eggs") gets the exact word's position bonus
+		return w.PluralWord + position*w.ExactWordPositionBonus
+	case MatchPrefix:
+		return w.PrefixWord + position*w.PrefixWordPositionBonus
+	case MatchPartial:
+		// Substring matches are less reliable and get no position bonus
+		return w.PartialWord
+	}
+	return 0
+}
 
+// adjustScoreForFoodContext applies food-specific scoring adjustments, recording each in breakdown unless
+// it is nil
+func (w ScoringWeights) adjustScoreForFoodContext(normalizedDesc, normalizedQuery string, queryWords []string, currentScore float64, breakdown *ScoreBreakdown) float64 {
 	// Boost simple, direct food names
 	descWords := strings.Fields(normalizedDesc)
 	if len(descWords) <= 3 && len(queryWords) == 1 {
 		// Simple food names like "milk" or "eggs" should rank higher
 		if strings.Contains(descWords[0], queryWords[0]) {
-			currentScore *= 1.5
+			currentScore *= w.SimpleNameBoost
+			breakdown.multiply("simple food name", w.SimpleNameBoost, currentScore)
 		}
 	}
 
@@ -309,20 +903,25 @@ func adjustScoreForFoodContext(description, normalizedQuery string, queryWords [
 			// Prefer "milk, whole" over "cheese, cottage, lowfat, 2% milkfat"
 			if strings.HasPrefix(normalizedDesc, "milk") {
 				currentScore *= 2.0
+				breakdown.multiply("milk: description starts with milk", 2.0, currentScore)
 			} else if strings.Contains(normalizedDesc, "milkfat") || strings.Contains(normalizedDesc, "milk fat") {
 				currentScore *= 0.3 // Reduce score for incidental mentions
+				breakdown.multiply("milk: description only mentions milkfat", 0.3, currentScore)
 			}
 		case "cheese":
 			if strings.HasPrefix(normalizedDesc, "cheese") {
 				currentScore *= 1.5
+				breakdown.multiply("cheese: description starts with cheese", 1.5, currentScore)
 			}
 		case "chicken", "beef", "pork":
 			if strings.HasPrefix(normalizedDesc, queryWord) {
 				currentScore *= 1.3
+				breakdown.multiply(fmt.Sprintf("%s: description starts with %s", queryWord, queryWord), 1.3, currentScore)
 			}
 		case "bread":
 			if strings.HasPrefix(normalizedDesc, "bread") || strings.Contains(normalizedDesc, "bread") {
 				currentScore *= 1.2
+				breakdown.multiply("bread: description mentions bread", 1.2, currentScore)
 			}
 		}
 	}
@@ -334,6 +933,7 @@ func adjustScoreForFoodContext(description, normalizedQuery string, queryWords [
 		for _, indicator := range brandIndicators {
 			if strings.Contains(normalizedDesc, indicator) {
 				currentScore *= 0.7
+				breakdown.multiply(fmt.Sprintf("generic query on a long %q description", indicator), 0.7, currentScore)
 				break
 			}
 		}
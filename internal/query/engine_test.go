@@ -1,11 +1,17 @@
 package query
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/json"
 	"io"
 	"log/slog"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"testing/fstest"
 
 	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
 	"github.com/stretchr/testify/assert"
@@ -27,6 +33,125 @@ func TestNewEngine(t *testing.T) {
 		assert.Greater(t, len(engine.data.FoundationFoods), 0)
 	})
 
+	t.Run("loads only the configured number of foods", func(t *testing.T) {
+		logger := config.NewTestLogger(io.Discard, "debug")
+
+		path := filepath.Join(t.TempDir(), "foods.json")
+		fixture := `{"FoundationFoods": [
+			{"description": "Milk, whole", "fdcId": 1},
+			{"description": "Eggs, whole", "fdcId": 2},
+			{"description": "Bread, white", "fdcId": 3}
+		]}`
+		require.NoError(t, os.WriteFile(path, []byte(fixture), 0o600))
+
+		engine, err := NewEngine(path, logger, WithMaxFoods(2))
+
+		require.NoError(t, err)
+		require.Len(t, engine.data.FoundationFoods, 2)
+		assert.Equal(t, 1, engine.data.FoundationFoods[0].FdcId)
+		assert.Equal(t, 2, engine.data.FoundationFoods[1].FdcId)
+	})
+
+	t.Run("drops portions with an invalid gram weight when configured", func(t *testing.T) {
+		logger := config.NewTestLogger(io.Discard, "debug")
+
+		path := filepath.Join(t.TempDir(), "foods.json")
+		fixture := `{"FoundationFoods": [
+			{"description": "Milk, whole", "fdcId": 1, "foodPortions": [
+				{"measureUnit": {"name": "cup"}, "gramWeight": 244},
+				{"measureUnit": {"name": "undetermined"}, "gramWeight": 0},
+				{"measureUnit": {"name": "tablespoon"}, "gramWeight": -15}
+			]}
+		]}`
+		require.NoError(t, os.WriteFile(path, []byte(fixture), 0o600))
+
+		engine, err := NewEngine(path, logger, WithDropInvalidPortions(true))
+
+		require.NoError(t, err)
+		require.Len(t, engine.data.FoundationFoods[0].FoodPortions, 1)
+		assert.Equal(t, "cup", engine.data.FoundationFoods[0].FoodPortions[0].MeasureUnit.Name)
+
+		// Without the option the dataset is left as published
+		engine, err = NewEngine(path, logger)
+
+		require.NoError(t, err)
+		assert.Len(t, engine.data.FoundationFoods[0].FoodPortions, 3)
+	})
+
+	t.Run("loads a gzipped dataset the same as the plain one", func(t *testing.T) {
+		logger := config.NewTestLogger(io.Discard, "debug")
+
+		fixture := `{"FoundationFoods": [
+			{"description": "Milk, whole", "fdcId": 1, "foodNutrients": [
+				{"nutrient": {"name": "Protein", "unitName": "g"}, "amount": 3.27}
+			]},
+			{"description": "Eggs, whole", "fdcId": 2}
+		]}`
+		dir := t.TempDir()
+		plainPath := filepath.Join(dir, "foods.json")
+		require.NoError(t, os.WriteFile(plainPath, []byte(fixture), 0o600))
+
+		var compressed bytes.Buffer
+		writer := gzip.NewWriter(&compressed)
+		_, err := writer.Write([]byte(fixture))
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+
+		// Gzip is detected by its magic bytes, so the extension doesn't matter
+		gzPath := filepath.Join(dir, "foods.json.gz")
+		require.NoError(t, os.WriteFile(gzPath, compressed.Bytes(), 0o600))
+		unlabeledPath := filepath.Join(dir, "foods-compressed.json")
+		require.NoError(t, os.WriteFile(unlabeledPath, compressed.Bytes(), 0o600))
+
+		plain, err := NewEngine(plainPath, logger)
+		require.NoError(t, err)
+
+		for _, path := range []string{gzPath, unlabeledPath} {
+			gzipped, err := NewEngine(path, logger)
+			require.NoError(t, err)
+			assert.Equal(t, plain.data, gzipped.data)
+		}
+	})
+
+	t.Run("loads the dataset from a custom filesystem", func(t *testing.T) {
+		logger := config.NewTestLogger(io.Discard, "debug")
+
+		fsys := fstest.MapFS{
+			"foundationfoods_2025-04-24.json": {Data: []byte(`{"FoundationFoods": [{"description": "Milk, whole", "fdcId": 1}]}`)},
+		}
+
+		engine, err := NewEngine("foundationfoods_2025-04-24.json", logger, WithDatasetFS(fsys))
+
+		require.NoError(t, err)
+		require.Len(t, engine.data.FoundationFoods, 1)
+		assert.Equal(t, "2025-04-24", engine.datasetDate)
+	})
+
+	t.Run("returns error for a corrupt gzip file", func(t *testing.T) {
+		logger := config.NewTestLogger(io.Discard, "debug")
+
+		path := filepath.Join(t.TempDir(), "foods.json.gz")
+		require.NoError(t, os.WriteFile(path, []byte{0x1f, 0x8b, 0x00}, 0o600))
+
+		engine, err := NewEngine(path, logger)
+
+		assert.Error(t, err)
+		assert.Nil(t, engine)
+	})
+
+	t.Run("returns error for malformed file", func(t *testing.T) {
+		logger := config.NewTestLogger(io.Discard, "debug")
+
+		path := filepath.Join(t.TempDir(), "foods.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"FoundationFoods": [{`), 0o600))
+
+		engine, err := NewEngine(path, logger)
+
+		assert.Error(t, err)
+		assert.Nil(t, engine)
+		assert.Contains(t, err.Error(), "failed to parse Foundation Foods JSON data")
+	})
+
 	t.Run("returns error for non-existent file", func(t *testing.T) {
 		logger := config.NewTestLogger(io.Discard, "debug")
 
@@ -38,6 +163,60 @@ func TestNewEngine(t *testing.T) {
 	})
 }
 
+func TestDecodeFoundationFoods_MatchesUnmarshal(t *testing.T) {
+	path := "../../data/foundationfoods_2025-04-24.json"
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		t.Skip("Skipping parity test - requires actual data file")
+	}
+	require.NoError(t, err)
+
+	var unmarshaled FoundationFoodsData
+	require.NoError(t, json.Unmarshal(content, &unmarshaled))
+
+	streamed, err := decodeFoundationFoods(bytes.NewReader(content), 0)
+	require.NoError(t, err)
+
+	require.NotEmpty(t, unmarshaled.FoundationFoods)
+	assert.Len(t, streamed.FoundationFoods, len(unmarshaled.FoundationFoods))
+	assert.Equal(t, unmarshaled.FoundationFoods, streamed.FoundationFoods)
+}
+
+func TestEngine_Reload(t *testing.T) {
+	logger := config.NewTestLogger(io.Discard, "debug")
+	ctx := context.Background()
+
+	path := filepath.Join(t.TempDir(), "foods.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"FoundationFoods": [{"description": "Milk, whole", "fdcId": 1}]}`), 0o600))
+
+	engine, err := NewEngine(path, logger)
+	require.NoError(t, err)
+
+	t.Run("swaps in the updated file", func(t *testing.T) {
+		updated := `{"FoundationFoods": [
+			{"description": "Milk, whole", "fdcId": 1},
+			{"description": "Milk, lowfat", "fdcId": 2}
+		]}`
+		require.NoError(t, os.WriteFile(path, []byte(updated), 0o600))
+
+		require.NoError(t, engine.Reload(ctx))
+
+		results, err := engine.SearchFoodsByName(ctx, "milk", 10, SearchOptions{})
+		require.NoError(t, err)
+		assert.Len(t, results, 2)
+	})
+
+	t.Run("keeps the current dataset when the file is broken", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, []byte(`{"FoundationFoods": [`), 0o600))
+
+		assert.ErrorContains(t, engine.Reload(ctx), "failed to parse Foundation Foods JSON data")
+
+		results, err := engine.SearchFoodsByName(ctx, "milk", 10, SearchOptions{})
+		require.NoError(t, err)
+		assert.Len(t, results, 2)
+	})
+}
+
 func TestEngine_SearchFoodsByName(t *testing.T) {
 	// Create a test engine with mock data
 	testData := &FoundationFoodsData{
@@ -74,7 +253,7 @@ func TestEngine_SearchFoodsByName(t *testing.T) {
 	ctx := context.Background()
 
 	t.Run("finds milk matches with proper prioritization", func(t *testing.T) {
-		results, err := engine.SearchFoodsByName(ctx, "milk", 3)
+		results, err := engine.SearchFoodsByName(ctx, "milk", 3, SearchOptions{})
 
 		require.NoError(t, err)
 		assert.Greater(t, len(results), 0)
@@ -83,7 +262,7 @@ func TestEngine_SearchFoodsByName(t *testing.T) {
 	})
 
 	t.Run("finds partial matches", func(t *testing.T) {
-		results, err := engine.SearchFoodsByName(ctx, "egg", 3)
+		results, err := engine.SearchFoodsByName(ctx, "egg", 3, SearchOptions{})
 
 		require.NoError(t, err)
 		assert.Len(t, results, 1)
@@ -92,7 +271,7 @@ func TestEngine_SearchFoodsByName(t *testing.T) {
 
 	t.Run("prioritizes better matches", func(t *testing.T) {
 		// "milk" should find "Milk, whole..." before "Cheese, cottage... milkfat"
-		results, err := engine.SearchFoodsByName(ctx, "milk", 2)
+		results, err := engine.SearchFoodsByName(ctx, "milk", 2, SearchOptions{})
 
 		require.NoError(t, err)
 		assert.Greater(t, len(results), 0)
@@ -100,22 +279,123 @@ func TestEngine_SearchFoodsByName(t *testing.T) {
 	})
 
 	t.Run("respects limit parameter", func(t *testing.T) {
-		results, err := engine.SearchFoodsByName(ctx, "a", 2) // Should match multiple items
+		results, err := engine.SearchFoodsByName(ctx, "a", 2, SearchOptions{}) // Should match multiple items
 
 		require.NoError(t, err)
 		assert.LessOrEqual(t, len(results), 2)
 	})
 
 	t.Run("handles case insensitive search", func(t *testing.T) {
-		results, err := engine.SearchFoodsByName(ctx, "MILK", 3)
+		results, err := engine.SearchFoodsByName(ctx, "MILK", 3, SearchOptions{})
 
 		require.NoError(t, err)
 		assert.Greater(t, len(results), 0)
 		assert.Equal(t, "Milk, whole, 3.25% milkfat", results[0].Description)
 	})
 
+	t.Run("scopes results to a category", func(t *testing.T) {
+		results, err := engine.SearchFoodsByName(ctx, "bread", 3, SearchOptions{Category: "Dairy and Egg Products"})
+
+		require.NoError(t, err)
+		assert.Len(t, results, 0)
+
+		results, err = engine.SearchFoodsByName(ctx, "bread", 3, SearchOptions{Category: "Baked Products"})
+
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, 4, results[0].FdcId)
+	})
+
+	t.Run("returns nothing for an unknown category", func(t *testing.T) {
+		results, err := engine.SearchFoodsByName(ctx, "milk", 3, SearchOptions{Category: "Spices and Herbs"})
+
+		require.NoError(t, err)
+		assert.Empty(t, results)
+	})
+
+	t.Run("matches categories case-insensitively", func(t *testing.T) {
+		results, err := engine.SearchFoodsByName(ctx, "bread", 3, SearchOptions{Category: "BAKED products"})
+
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, 4, results[0].FdcId)
+	})
+
+	t.Run("matches categories written with an ampersand", func(t *testing.T) {
+		spelledOut, err := engine.SearchFoodsByName(ctx, "milk", 3, SearchOptions{Category: "Dairy and Egg Products"})
+		require.NoError(t, err)
+
+		ampersand, err := engine.SearchFoodsByName(ctx, "milk", 3, SearchOptions{Category: "Dairy & Egg Products"})
+		require.NoError(t, err)
+
+		require.NotEmpty(t, ampersand)
+		assert.Equal(t, spelledOut, ampersand)
+	})
+
+	t.Run("skips results before the offset", func(t *testing.T) {
+		all, err := engine.SearchFoodsByName(ctx, "milk", 10, SearchOptions{})
+		require.NoError(t, err)
+		require.Greater(t, len(all), 1)
+
+		paged, err := engine.SearchFoodsByName(ctx, "milk", 10, SearchOptions{Offset: 1})
+		require.NoError(t, err)
+		assert.Equal(t, all[1:], paged)
+
+		past, err := engine.SearchFoodsByName(ctx, "milk", 10, SearchOptions{Offset: len(all)})
+		require.NoError(t, err)
+		assert.Empty(t, past)
+	})
+
+	t.Run("drops matches below the minimum score", func(t *testing.T) {
+		all, err := engine.SearchFoodsByName(ctx, "milk", 50, SearchOptions{IncludeScores: true})
+		require.NoError(t, err)
+		require.Greater(t, len(all), 1)
+
+		threshold := *all[0].Score
+		strong, err := engine.SearchFoodsByName(ctx, "milk", 50, SearchOptions{IncludeScores: true, MinScore: threshold})
+		require.NoError(t, err)
+		require.NotEmpty(t, strong)
+		assert.LessOrEqual(t, len(strong), len(all))
+		for _, food := range strong {
+			assert.GreaterOrEqual(t, *food.Score, threshold)
+		}
+
+		_, count, err := engine.SearchFoodsByNameWithTotal(ctx, "milk", 50, SearchOptions{MinScore: threshold})
+		require.NoError(t, err)
+		assert.Equal(t, len(strong), count)
+	})
+
+	t.Run("includes scores only when requested", func(t *testing.T) {
+		results, err := engine.SearchFoodsByName(ctx, "milk", 3, SearchOptions{})
+		require.NoError(t, err)
+		require.NotEmpty(t, results)
+		assert.Nil(t, results[0].Score)
+
+		scored, err := engine.SearchFoodsByName(ctx, "milk", 3, SearchOptions{IncludeScores: true})
+		require.NoError(t, err)
+		require.Len(t, scored, len(results))
+		for i, food := range scored {
+			require.NotNil(t, food.Score)
+			assert.Greater(t, *food.Score, 0.0)
+			if i > 0 {
+				assert.LessOrEqual(t, *food.Score, *scored[i-1].Score)
+			}
+		}
+	})
+
+	t.Run("counts every match regardless of offset", func(t *testing.T) {
+		all, err := engine.SearchFoodsByName(ctx, "milk", 10, SearchOptions{})
+		require.NoError(t, err)
+
+		paged, total, err := engine.SearchFoodsByNameWithTotal(ctx, "milk", 1, SearchOptions{Offset: 1})
+
+		require.NoError(t, err)
+		assert.Equal(t, all[1:2], paged)
+		assert.Equal(t, len(all), total)
+	})
+
 	t.Run("returns empty for no matches", func(t *testing.T) {
-		results, err := engine.SearchFoodsByName(ctx, "xyz123nonexistent", 3)
+		results, err := engine.SearchFoodsByName(ctx, "xyz123nonexistent", 3, SearchOptions{})
 
 		require.NoError(t, err)
 		assert.Len(t, results, 0)
@@ -266,6 +546,8 @@ func TestNormalizeString(t *testing.T) {
 		{"Bread (White)", "bread white"},
 		{"Cheese, cottage, lowfat, 2% milkfat.", "cheese cottage lowfat 2% milkfat"},
 		{"  EGGS  ", "eggs"},
+		{"Dairy & Egg Products", "dairy and egg products"},
+		{"Macaroni&cheese", "macaroni and cheese"},
 	}
 
 	for _, tc := range testCases {
@@ -375,7 +657,7 @@ func TestEngine_SearchFoodsByNameSimplified_FiltersKilojouleEnergy(t *testing.T)
 	ctx := context.Background()
 
 	// Search with custom nutrient filtering that includes Energy
-	result, err := engine.SearchFoodsByNameSimplified(ctx, "Test", 10, []string{"Energy", "Protein"})
+	result, err := engine.SearchFoodsByNameSimplified(ctx, "Test", 10, []string{"Energy", "Protein"}, SearchOptions{})
 
 	assert.NoError(t, err)
 	assert.Len(t, result.Foods, 1)
@@ -403,3 +685,247 @@ func TestEngine_SearchFoodsByNameSimplified_FiltersKilojouleEnergy(t *testing.T)
 	assert.True(t, energyFound, "Energy nutrient should be present")
 	assert.False(t, kilojouleFound, "Energy in kJ should be filtered out")
 }
+
+func TestEngine_SearchFoodsByNameSimplified_SortsPortionsBySequence(t *testing.T) {
+	mockData := &FoundationFoodsData{
+		FoundationFoods: []FoundationFood{
+			{
+				Description: "Milk, whole",
+				FdcId:       1,
+				FoodPortions: []FoodPortion{
+					{SequenceNumber: 3, MeasureUnit: MeasureUnit{Name: "quart"}, GramWeight: 976},
+					{SequenceNumber: 1, MeasureUnit: MeasureUnit{Name: "cup"}, GramWeight: 244},
+					{SequenceNumber: 2, MeasureUnit: MeasureUnit{Name: "fl oz"}, GramWeight: 30.5},
+				},
+			},
+		},
+	}
+
+	engine := &Engine{
+		data:   mockData,
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+
+	ctx := context.Background()
+
+	t.Run("sorts portions without exposing sequence numbers by default", func(t *testing.T) {
+		result, err := engine.SearchFoodsByNameSimplified(ctx, "milk", 1, nil, SearchOptions{})
+
+		require.NoError(t, err)
+		require.Len(t, result.Foods, 1)
+
+		portions := result.Foods[0].FoodPortions
+		require.Len(t, portions, 3)
+		assert.Equal(t, "cup", portions[0].MeasureUnit.Name)
+		assert.Equal(t, "fl oz", portions[1].MeasureUnit.Name)
+		assert.Equal(t, "quart", portions[2].MeasureUnit.Name)
+		assert.Equal(t, 0, portions[0].SequenceNumber)
+	})
+
+	t.Run("includes sequence numbers on request", func(t *testing.T) {
+		result, err := engine.SearchFoodsByNameSimplified(ctx, "milk", 1, nil, SearchOptions{IncludeSequence: true})
+
+		require.NoError(t, err)
+
+		portions := result.Foods[0].FoodPortions
+		assert.Equal(t, 1, portions[0].SequenceNumber)
+		assert.Equal(t, 2, portions[1].SequenceNumber)
+		assert.Equal(t, 3, portions[2].SequenceNumber)
+	})
+}
+
+func TestEngine_SearchFoodsByNameSimplified_RelativeToReference(t *testing.T) {
+	mockData := &FoundationFoodsData{
+		FoundationFoods: []FoundationFood{
+			{
+				Description: "Milk, whole",
+				FdcId:       1,
+				FoodNutrients: []FoodNutrient{
+					{Nutrient: Nutrient{Name: "Calcium, Ca", UnitName: "mg"}, Amount: 120},
+					{Nutrient: Nutrient{Name: "Protein", UnitName: "g"}, Amount: 3},
+				},
+			},
+			{
+				Description: "Cheese, parmesan",
+				FdcId:       2,
+				FoodNutrients: []FoodNutrient{
+					{Nutrient: Nutrient{Name: "Calcium, Ca", UnitName: "mg"}, Amount: 276},
+					{Nutrient: Nutrient{Name: "Sodium, Na", UnitName: "mg"}, Amount: 1500},
+				},
+			},
+		},
+	}
+
+	engine := &Engine{
+		data:   mockData,
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+
+	ctx := context.Background()
+
+	t.Run("computes ratios against the reference food", func(t *testing.T) {
+		result, err := engine.SearchFoodsByNameSimplified(ctx, "parmesan", 1, nil, SearchOptions{ReferenceFdcId: 1})
+
+		require.NoError(t, err)
+		require.NotNil(t, result.Reference)
+		assert.Equal(t, "Milk, whole", result.Reference.Description)
+		require.Len(t, result.Foods, 1)
+
+		nutrients := result.Foods[0].Nutrients
+		require.Len(t, nutrients, 2)
+
+		require.NotNil(t, nutrients[0].RelativeToReference)
+		assert.InDelta(t, 2.3, *nutrients[0].RelativeToReference, 0.0001)
+
+		// Milk has no sodium entry so there is no ratio
+		assert.Nil(t, nutrients[1].RelativeToReference)
+	})
+
+	t.Run("returns error for unknown reference food", func(t *testing.T) {
+		result, err := engine.SearchFoodsByNameSimplified(ctx, "parmesan", 1, nil, SearchOptions{ReferenceFdcId: 999})
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestSearchFoodsByNameSimplified_NutrientJSON(t *testing.T) {
+	engine := &Engine{
+		data: &FoundationFoodsData{
+			FoundationFoods: []FoundationFood{
+				{
+					Description: "Milk, whole",
+					FdcId:       1,
+					FoodNutrients: []FoodNutrient{
+						{Nutrient: Nutrient{Name: "Protein", UnitName: "g"}, Amount: 3.3, DataPoints: 12, Min: 3.1, Max: 3.5, Median: 3.3},
+					},
+				},
+			},
+		},
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+
+	result, err := engine.SearchFoodsByNameSimplified(context.Background(), "milk", 1, nil, SearchOptions{})
+	require.NoError(t, err)
+	require.Len(t, result.Foods, 1)
+	require.Len(t, result.Foods[0].Nutrients, 1)
+
+	// Simplified nutrients report the unit as "unit" and leave out the per-nutrient statistics
+	encoded, err := json.Marshal(result.Foods[0].Nutrients[0])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"Protein","unit":"g","amount":3.3,"dataPoints":12}`, string(encoded))
+}
+
+func TestDefaultNutrients_NoDuplicates(t *testing.T) {
+	seen := make(map[string]bool, len(DefaultNutrients))
+	for _, name := range DefaultNutrients {
+		normalized := strings.ToLower(strings.TrimSpace(name))
+		assert.False(t, seen[normalized], "%q is listed more than once", name)
+		seen[normalized] = true
+	}
+}
+
+func TestSearchFoodsByNameSimplified_DefaultNutrientsOnce(t *testing.T) {
+	nutrients := make([]FoodNutrient, 0, len(DefaultNutrients))
+	for _, name := range DefaultNutrients {
+		nutrients = append(nutrients, FoodNutrient{Nutrient: Nutrient{Name: name, UnitName: "g"}, Amount: 1})
+	}
+	engine := &Engine{
+		data: &FoundationFoodsData{
+			FoundationFoods: []FoundationFood{
+				{Description: "Milk, whole", FdcId: 1, FoodNutrients: nutrients},
+			},
+		},
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+
+	// Repeat the filter list so a nutrient matching several entries would show up more than once
+	filter := append(append([]string{}, DefaultNutrients...), DefaultNutrients...)
+	result, err := engine.SearchFoodsByNameSimplified(context.Background(), "milk", 1, filter, SearchOptions{})
+	require.NoError(t, err)
+	require.Len(t, result.Foods, 1)
+
+	counts := make(map[string]int)
+	for _, nutrient := range result.Foods[0].Nutrients {
+		counts[nutrient.Name]++
+	}
+	for name, count := range counts {
+		assert.Equal(t, 1, count, "%q appears %d times", name, count)
+	}
+	assert.Len(t, result.Foods[0].Nutrients, len(DefaultNutrients))
+}
+
+func TestEngine_SearchFoodsByName_Exact(t *testing.T) {
+	engine := &Engine{
+		data: &FoundationFoodsData{
+			FoundationFoods: []FoundationFood{
+				{Description: "Milk, reduced fat, fluid, 2% milkfat, with added vitamin A and vitamin D", FdcId: 1, FoodCategory: FoodCategory{Description: "Dairy and Egg Products"}},
+				{Description: "Milk, whole, 3.25% milkfat, with added vitamin D", FdcId: 2, FoodCategory: FoodCategory{Description: "Dairy and Egg Products"}},
+				{Description: "Milk, reduced fat, fluid, 2% milkfat, with added vitamin A and vitamin D, lactose free", FdcId: 3},
+			},
+		},
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+
+	search := func(t *testing.T, query string, opts SearchOptions) []int {
+		t.Helper()
+		opts.Exact = true
+		foods, err := engine.SearchFoodsByName(context.Background(), query, 10, opts)
+		require.NoError(t, err)
+		ids := []int{}
+		for _, food := range foods {
+			ids = append(ids, food.FdcId)
+		}
+		return ids
+	}
+
+	t.Run("returns only the food whose description equals the query", func(t *testing.T) {
+		assert.Equal(t, []int{1}, search(t, "Milk, reduced fat, fluid, 2% milkfat, with added vitamin A and vitamin D", SearchOptions{}))
+	})
+
+	t.Run("ignores case and the punctuation normalization strips", func(t *testing.T) {
+		assert.Equal(t, []int{2}, search(t, "MILK WHOLE 3.25% MILKFAT WITH ADDED VITAMIN D", SearchOptions{}))
+	})
+
+	t.Run("returns nothing for a partial description, even with nearest fallbacks", func(t *testing.T) {
+		assert.Empty(t, search(t, "milk", SearchOptions{ReturnNearestOnEmpty: true, Fuzzy: true}))
+	})
+
+	t.Run("still respects the category", func(t *testing.T) {
+		assert.Empty(t, search(t, "Milk, whole, 3.25% milkfat, with added vitamin D", SearchOptions{Category: "Vegetables and Vegetable Products"}))
+	})
+}
+
+func TestEngine_ScoreFoods_TieBreaking(t *testing.T) {
+	foods := []FoundationFood{
+		{Description: "Beans, pinto, raw", FdcId: 40},
+		{Description: "Beans, black, raw", FdcId: 30},
+		{Description: "Beans, pinto, raw", FdcId: 20},
+		{Description: "Beans, kidney, raw", FdcId: 10},
+	}
+
+	// Every dataset order must rank the equally scored foods the same way
+	for _, order := range [][]int{{0, 1, 2, 3}, {3, 2, 1, 0}, {2, 0, 3, 1}} {
+		shuffled := make([]FoundationFood, 0, len(foods))
+		for _, i := range order {
+			shuffled = append(shuffled, foods[i])
+		}
+		engine := &Engine{
+			data:   &FoundationFoodsData{FoundationFoods: shuffled},
+			logger: config.NewTestLogger(io.Discard, "debug"),
+		}
+
+		results := engine.scoreFoods(context.Background(), "beans", SearchOptions{})
+
+		require.Len(t, results, 4)
+		for _, result := range results[1:] {
+			require.Equal(t, results[0].Score, result.Score, "the crafted foods must score equally")
+		}
+
+		ids := make([]int, 0, len(results))
+		for _, result := range results {
+			ids = append(ids, result.Food.FdcId)
+		}
+		assert.Equal(t, []int{30, 10, 20, 40}, ids, "order %v", order)
+	}
+}
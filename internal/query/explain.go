@@ -0,0 +1,123 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// matchStrength orders the literal word match kinds, strongest first
+var matchStrength = map[string]int{MatchExact: 4, MatchPlural: 3, MatchPrefix: 2, MatchPartial: 1}
+
+// wordMatchKind describes how a query word matched its best description word, using the same word-level
+// rules as scoreNormalizedDescription. It returns an empty string when the word didn't match.
+func wordMatchKind(queryWord string, descWords []string) string {
+	var kind string
+	for _, descWord := range descWords {
+		if match := matchWord(queryWord, descWord); matchStrength[match] > matchStrength[kind] {
+			kind = match
+		}
+	}
+	return kind
+}
+
+// explainMatch returns a short human-readable note on why a description matched the query
+func explainMatch(normalizedDesc, normalizedQuery string, queryWords []string) string {
+	switch {
+	case normalizedDesc == normalizedQuery:
+		return "exact match"
+	case strings.HasPrefix(normalizedDesc, normalizedQuery):
+		return "description starts with the query"
+	case strings.Contains(normalizedDesc, normalizedQuery):
+		return "description contains the query"
+	}
+
+	descWords := strings.Fields(normalizedDesc)
+	var matched []string
+	for _, queryWord := range queryWords {
+		if kind := wordMatchKind(queryWord, descWords); kind != "" {
+			matched = append(matched, fmt.Sprintf("%s (%s)", queryWord, kind))
+		}
+	}
+
+	if len(matched) == 0 {
+		return "matched by food context"
+	}
+	return fmt.Sprintf("matched %d of %d words: %s", len(matched), len(queryWords), strings.Join(matched, ", "))
+}
+
+// add records points added to a relevance score; it does nothing on a nil breakdown
+func (b *ScoreBreakdown) add(step string, points, score float64) {
+	if b == nil {
+		return
+	}
+	b.Steps = append(b.Steps, ScoreStep{Step: step, Points: points, Score: score})
+}
+
+// multiply records a factor a relevance score was multiplied by; it does nothing on a nil breakdown
+func (b *ScoreBreakdown) multiply(step string, factor, score float64) {
+	if b == nil {
+		return
+	}
+	b.Steps = append(b.Steps, ScoreStep{Step: step, Factor: factor, Score: score})
+}
+
+// word records a query word's best match, adding a step when it earned points; it does nothing on a
+// nil breakdown
+func (b *ScoreBreakdown) word(queryWord, match, descWord string, points, score float64) {
+	if b == nil {
+		return
+	}
+	b.Words = append(b.Words, WordScore{Word: queryWord, Match: match, DescriptionWord: descWord, Points: points})
+	if points > 0 {
+		b.add(fmt.Sprintf("query word %q (%s match)", queryWord, match), points, score)
+	}
+}
+
+// ExplainFoodSearch scores a single description against a query exactly like SearchFoodsByName would, with
+// the same weights, synonyms and fuzzy and preparation options, and returns every contribution to the
+// score. Pass either a description or the FDC ID of a food whose description is used. Category, minimum
+// score and exact options don't change a score, so they are ignored.
+func (e *Engine) ExplainFoodSearch(ctx context.Context, query, description string, fdcId int, opts SearchOptions) (*SearchExplanation, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, fmt.Errorf("query must not be empty")
+	}
+	if (strings.TrimSpace(description) == "") == (fdcId == 0) {
+		return nil, fmt.Errorf("exactly one of description or FDC ID is required")
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if fdcId != 0 {
+		food, err := e.getFoodByFdcId(fdcId)
+		if err != nil {
+			return nil, err
+		}
+		description = food.Description
+	}
+
+	normalizedQuery := normalizeString(query)
+	normalizedDesc := normalizeString(description)
+
+	breakdown := &ScoreBreakdown{Words: []WordScore{}, Steps: []ScoreStep{}}
+	score := e.scoringWeights().scoreDescription(normalizedDesc, normalizedQuery, strings.Fields(normalizedQuery), opts.Fuzzy, e.synonymTable(), breakdown)
+	score = adjustScoreForPreparation(normalizedDesc, opts.Preparation, score, breakdown)
+
+	e.logger.DebugContext(ctx, "Explained search score",
+		"query", query,
+		"description", description,
+		"score", score)
+
+	return &SearchExplanation{
+		Query:                 query,
+		NormalizedQuery:       normalizedQuery,
+		FdcId:                 fdcId,
+		Description:           description,
+		NormalizedDescription: normalizedDesc,
+		Matched:               score > 0,
+		Score:                 score,
+		Words:                 breakdown.Words,
+		Steps:                 breakdown.Steps,
+	}, nil
+}
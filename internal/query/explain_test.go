@@ -0,0 +1,109 @@
+package query
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_ExplainFoodSearch(t *testing.T) {
+	engine := &Engine{
+		data: &FoundationFoodsData{
+			FoundationFoods: []FoundationFood{
+				{Description: "Milk, whole", FdcId: 1},
+				{Description: "Cheese, cottage, lowfat, 2% milkfat", FdcId: 2},
+				{Description: "Chicken, breast, roasted", FdcId: 3},
+				{Description: "Broccoli, raw", FdcId: 4},
+				{Description: "Eggplant, raw", FdcId: 5},
+			},
+		},
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+
+	t.Run("breaks the score into steps", func(t *testing.T) {
+		result, err := engine.ExplainFoodSearch(context.Background(), "milk", "Milk, whole", 0, SearchOptions{})
+		require.NoError(t, err)
+
+		assert.Equal(t, "milk whole", result.NormalizedDescription)
+		assert.True(t, result.Matched)
+		assert.Equal(t, 2040.0, result.Score)
+		assert.Equal(t, []WordScore{{Word: "milk", Match: MatchExact, DescriptionWord: "milk", Points: 80}}, result.Words)
+		assert.Equal(t, []ScoreStep{
+			{Step: "description starts with the query", Points: 500, Score: 500},
+			{Step: "description contains the query", Points: 100, Score: 600},
+			{Step: `query word "milk" (exact match)`, Points: 80, Score: 680},
+			{Step: "simple food name", Factor: 1.5, Score: 1020},
+			{Step: "milk: description starts with milk", Factor: 2, Score: 2040},
+		}, result.Steps)
+	})
+
+	t.Run("looks up the description by FDC ID", func(t *testing.T) {
+		result, err := engine.ExplainFoodSearch(context.Background(), "milk", "", 2, SearchOptions{})
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, result.FdcId)
+		assert.Equal(t, "Cheese, cottage, lowfat, 2% milkfat", result.Description)
+		require.NotEmpty(t, result.Steps)
+		assert.Equal(t, "milk: description only mentions milkfat", result.Steps[len(result.Steps)-1].Step)
+	})
+
+	t.Run("records unmatched words, synonyms and preparation", func(t *testing.T) {
+		result, err := engine.ExplainFoodSearch(context.Background(), "aubergine steak", "Eggplant, raw", 0, SearchOptions{Preparation: PreparationCooked})
+		require.NoError(t, err)
+
+		assert.Equal(t, []WordScore{
+			{Word: "aubergine", Match: MatchSynonym, Points: DefaultScoringWeights.SynonymWord},
+			{Word: "steak"},
+		}, result.Words)
+		last := result.Steps[len(result.Steps)-1]
+		assert.Equal(t, "description is not cooked as requested", last.Step)
+		assert.Equal(t, result.Score, last.Score)
+	})
+
+	t.Run("reports a non-match", func(t *testing.T) {
+		result, err := engine.ExplainFoodSearch(context.Background(), "salmon", "Broccoli, raw", 0, SearchOptions{})
+		require.NoError(t, err)
+
+		assert.False(t, result.Matched)
+		assert.Zero(t, result.Score)
+		assert.Empty(t, result.Steps)
+	})
+
+	t.Run("matches the search score", func(t *testing.T) {
+		for _, opts := range []SearchOptions{{}, {Fuzzy: true}, {Preparation: PreparationRaw}} {
+			for _, q := range []string{"milk", "chicken breast", "brocolli raw", "eggplant"} {
+				scores := map[int]float64{}
+				for _, result := range engine.scoreFoods(context.Background(), q, opts) {
+					scores[result.Food.FdcId] = result.Score
+				}
+
+				for _, food := range engine.data.FoundationFoods {
+					result, err := engine.ExplainFoodSearch(context.Background(), q, "", food.FdcId, opts)
+					require.NoError(t, err)
+					assert.Equal(t, scores[food.FdcId], result.Score, "%q against %q with %+v", q, food.Description, opts)
+					if len(result.Steps) > 0 {
+						assert.Equal(t, result.Score, result.Steps[len(result.Steps)-1].Score)
+					}
+				}
+			}
+		}
+	})
+
+	t.Run("requires exactly one of description or FDC ID", func(t *testing.T) {
+		_, err := engine.ExplainFoodSearch(context.Background(), "milk", "", 0, SearchOptions{})
+		assert.Error(t, err)
+
+		_, err = engine.ExplainFoodSearch(context.Background(), "milk", "Milk, whole", 1, SearchOptions{})
+		assert.Error(t, err)
+
+		_, err = engine.ExplainFoodSearch(context.Background(), " ", "Milk, whole", 0, SearchOptions{})
+		assert.Error(t, err)
+
+		_, err = engine.ExplainFoodSearch(context.Background(), "milk", "", 999, SearchOptions{})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,65 @@
+package query
+
+import (
+	"context"
+	"fmt"
+)
+
+// GetFoodsByFdcIds returns the foods with the given FDC IDs in the order they were requested, listing IDs
+// that match no food in NotFound. Duplicate IDs are returned once. The dataset is scanned a single time
+// however many IDs are requested, rather than once per ID as repeated GetFoodByFdcId calls would.
+func (e *Engine) GetFoodsByFdcIds(ctx context.Context, fdcIds []int) (*FoodsByFdcIdsResponse, error) {
+	if len(fdcIds) == 0 {
+		return nil, fmt.Errorf("at least one FDC ID is required")
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.data == nil {
+		return nil, fmt.Errorf("foundation Foods data not loaded")
+	}
+
+	wanted := make(map[int]bool, len(fdcIds))
+	for _, fdcId := range fdcIds {
+		wanted[fdcId] = true
+	}
+
+	// Collect every requested food in one pass, stopping early once all of them are found
+	found := make(map[int]*FoundationFood, len(wanted))
+	for i := range e.data.FoundationFoods {
+		food := &e.data.FoundationFoods[i]
+		if _, seen := found[food.FdcId]; wanted[food.FdcId] && !seen {
+			found[food.FdcId] = food
+			if len(found) == len(wanted) {
+				break
+			}
+		}
+	}
+
+	response := &FoodsByFdcIdsResponse{
+		Foods:    make([]FoundationFood, 0, len(found)),
+		NotFound: []int{},
+	}
+	returned := make(map[int]bool, len(wanted))
+	for _, fdcId := range fdcIds {
+		if returned[fdcId] {
+			continue
+		}
+		returned[fdcId] = true
+
+		if food, ok := found[fdcId]; ok {
+			response.Foods = append(response.Foods, *food)
+		} else {
+			response.NotFound = append(response.NotFound, fdcId)
+		}
+	}
+	response.Count = len(response.Foods)
+
+	e.logger.DebugContext(ctx, "Looked up foods by FDC ID",
+		"requested", len(fdcIds),
+		"found", response.Count,
+		"not_found", len(response.NotFound))
+
+	return response, nil
+}
@@ -0,0 +1,65 @@
+package query
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_GetFoodsByFdcIds(t *testing.T) {
+	engine := &Engine{
+		data: &FoundationFoodsData{
+			FoundationFoods: []FoundationFood{
+				{Description: "Milk, whole", FdcId: 10},
+				{Description: "Broccoli, raw", FdcId: 20},
+				{Description: "Eggs, Grade A, Large", FdcId: 30},
+			},
+		},
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+
+	t.Run("returns found foods in request order and lists missing IDs", func(t *testing.T) {
+		result, err := engine.GetFoodsByFdcIds(context.Background(), []int{30, 999, 10, 30, -1, 999})
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, result.Count)
+		require.Len(t, result.Foods, 2)
+		assert.Equal(t, 30, result.Foods[0].FdcId)
+		assert.Equal(t, 10, result.Foods[1].FdcId)
+		assert.Equal(t, []int{999, -1}, result.NotFound)
+	})
+
+	t.Run("all found", func(t *testing.T) {
+		result, err := engine.GetFoodsByFdcIds(context.Background(), []int{20})
+		require.NoError(t, err)
+
+		require.Len(t, result.Foods, 1)
+		assert.Equal(t, "Broccoli, raw", result.Foods[0].Description)
+		assert.Empty(t, result.NotFound)
+		assert.NotNil(t, result.NotFound)
+	})
+
+	t.Run("none found", func(t *testing.T) {
+		result, err := engine.GetFoodsByFdcIds(context.Background(), []int{1, 2})
+		require.NoError(t, err)
+
+		assert.Zero(t, result.Count)
+		assert.NotNil(t, result.Foods)
+		assert.Equal(t, []int{1, 2}, result.NotFound)
+	})
+
+	t.Run("requires at least one ID", func(t *testing.T) {
+		_, err := engine.GetFoodsByFdcIds(context.Background(), nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("requires data", func(t *testing.T) {
+		empty := &Engine{logger: config.NewTestLogger(io.Discard, "debug")}
+		_, err := empty.GetFoodsByFdcIds(context.Background(), []int{10})
+		assert.Error(t, err)
+	})
+}
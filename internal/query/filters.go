@@ -0,0 +1,44 @@
+package query
+
+import "strings"
+
+// sanitizeNutrientFilters trims nutrient filter names, dropping blanks and case-insensitive duplicates
+// while keeping the first spelling of each
+func sanitizeNutrientFilters(filters []string) []string {
+	if len(filters) == 0 {
+		return filters
+	}
+
+	seen := make(map[string]bool, len(filters))
+	sanitized := make([]string, 0, len(filters))
+	for _, filter := range filters {
+		filter = strings.TrimSpace(filter)
+		key := strings.ToLower(filter)
+		if filter == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		sanitized = append(sanitized, filter)
+	}
+
+	return sanitized
+}
+
+// unmatchedNutrientFilters returns the filters that match no nutrient of any of the foods, which usually
+// means a typo
+func (e *Engine) unmatchedNutrientFilters(foods []FoundationFood, filters []string) []string {
+	var unmatched []string
+	for _, filter := range filters {
+		matched := false
+		for _, food := range foods {
+			if _, ok := e.matchNutrient(&food, filter); ok {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			unmatched = append(unmatched, filter)
+		}
+	}
+	return unmatched
+}
@@ -0,0 +1,65 @@
+package query
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSanitizeNutrientFilters(t *testing.T) {
+	assert.Equal(t, []string{"Protein", "Calcium, Ca"}, sanitizeNutrientFilters([]string{" Protein", "protein", "", "  ", "Calcium, Ca", "PROTEIN "}))
+	assert.Empty(t, sanitizeNutrientFilters([]string{"", " "}))
+	assert.Nil(t, sanitizeNutrientFilters(nil))
+}
+
+func TestEngine_SearchFoodsByNameSimplified_MessyFilters(t *testing.T) {
+	testData := &FoundationFoodsData{
+		FoundationFoods: []FoundationFood{
+			{
+				Description: "Milk, whole",
+				FdcId:       1,
+				FoodNutrients: []FoodNutrient{
+					{Nutrient: Nutrient{Name: "Protein", UnitName: "g"}, Amount: 3.3},
+					{Nutrient: Nutrient{Name: "Calcium, Ca", UnitName: "mg"}, Amount: 113},
+					{Nutrient: Nutrient{Name: "Sodium, Na", UnitName: "mg"}, Amount: 38},
+				},
+			},
+		},
+	}
+
+	engine := &Engine{
+		data:   testData,
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+
+	filters := []string{"Protein", " protein ", "", "   ", "Calcium, Ca", "Protien"}
+	result, err := engine.SearchFoodsByNameSimplified(context.Background(), "milk", 1, filters, SearchOptions{})
+
+	require.NoError(t, err)
+	require.Len(t, result.Foods, 1)
+
+	names := make([]string, len(result.Foods[0].Nutrients))
+	for i, nutrient := range result.Foods[0].Nutrients {
+		names[i] = nutrient.Name
+	}
+	assert.Equal(t, []string{"Protein", "Calcium, Ca"}, names)
+	assert.Equal(t, []string{"Protien"}, result.UnmatchedFilters)
+
+	t.Run("reports nothing when every filter matches", func(t *testing.T) {
+		result, err := engine.SearchFoodsByNameSimplified(context.Background(), "milk", 1, []string{"protein"}, SearchOptions{})
+
+		require.NoError(t, err)
+		assert.Empty(t, result.UnmatchedFilters)
+	})
+
+	t.Run("an all-blank filter list includes every nutrient", func(t *testing.T) {
+		result, err := engine.SearchFoodsByNameSimplified(context.Background(), "milk", 1, []string{" ", ""}, SearchOptions{})
+
+		require.NoError(t, err)
+		assert.Len(t, result.Foods[0].Nutrients, 3)
+	})
+}
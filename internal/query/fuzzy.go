@@ -0,0 +1,129 @@
+package query
+
+import (
+	"sort"
+	"strings"
+)
+
+// fuzzyShortWordLen is the longest query word, in runes, that fuzzy matching allows only one edit for
+const fuzzyShortWordLen = 5
+
+// levenshtein returns the edit distance between two strings, counted in runes
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(rb)]
+}
+
+// wordSimilarity returns 1 minus the edit distance relative to the longer word, so 1 is identical and 0 shares nothing
+func wordSimilarity(a, b string) float64 {
+	longest := max(len([]rune(a)), len([]rune(b)))
+	if longest == 0 {
+		return 0
+	}
+	return 1 - float64(levenshtein(a, b))/float64(longest)
+}
+
+// fuzzyEdits returns the fewest edits between a query word and any description word, and whether that is
+// within a typo: one edit for words of up to fuzzyShortWordLen runes, two for longer ones. Words shorter
+// than 3 runes never match.
+func fuzzyEdits(queryWord string, descWords []string) (int, bool) {
+	length := len([]rune(queryWord))
+	if length < 3 {
+		return 0, false
+	}
+
+	maxDistance := 1
+	if length > fuzzyShortWordLen {
+		maxDistance = 2
+	}
+
+	best := maxDistance + 1
+	for _, descWord := range descWords {
+		best = min(best, levenshtein(queryWord, descWord))
+	}
+
+	return best, best <= maxDistance
+}
+
+// fuzzyWordScore returns the partial score for a query word within a typo of a description word
+func (w ScoringWeights) fuzzyWordScore(queryWord string, descWords []string) float64 {
+	edits, ok := fuzzyEdits(queryWord, descWords)
+	switch {
+	case !ok:
+		return 0
+	case edits == 1:
+		return w.FuzzyOneEdit
+	default:
+		return w.FuzzyTwoEdits
+	}
+}
+
+// fuzzySimilarity averages, over the query words, the similarity of each to its closest description word.
+// Unlike scoreNormalizedDescription it rewards near misses such as typos.
+func fuzzySimilarity(normalizedDesc string, queryWords []string) float64 {
+	descWords := strings.Fields(normalizedDesc)
+	if len(queryWords) == 0 || len(descWords) == 0 {
+		return 0
+	}
+
+	var total float64
+	for _, queryWord := range queryWords {
+		var best float64
+		for _, descWord := range descWords {
+			best = max(best, wordSimilarity(queryWord, descWord))
+		}
+		total += best
+	}
+
+	return total / float64(len(queryWords))
+}
+
+// nearestFoods returns the foods most similar to the query by edit distance, flagged as fuzzy fallbacks.
+// It backs return_nearest_on_empty when scoring matched nothing. The caller must hold the read lock.
+func (e *Engine) nearestFoods(query string, limit int, opts SearchOptions) []FoundationFood {
+	queryWords := strings.Fields(normalizeString(query))
+	normalizedDescriptions := e.normalizedDescriptions()
+
+	var results []SearchResult
+	for i, food := range e.data.FoundationFoods {
+		if !matchesCategory(food, opts.Category) {
+			continue
+		}
+
+		if similarity := fuzzySimilarity(normalizedDescriptions[i], queryWords); similarity > 0 {
+			results = append(results, SearchResult{Food: food, Score: similarity})
+		}
+	}
+
+	// Stable so equally similar foods keep dataset order
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+
+	foods := make([]FoundationFood, 0, min(limit, len(results)))
+	for _, result := range results[:min(limit, len(results))] {
+		food := result.Food
+		food.FuzzyFallback = true
+		foods = append(foods, food)
+	}
+
+	return foods
+}
@@ -0,0 +1,127 @@
+package query
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevenshtein(t *testing.T) {
+	assert.Equal(t, 0, levenshtein("milk", "milk"))
+	assert.Equal(t, 1, levenshtein("brocoli", "broccoli"))
+	assert.Equal(t, 3, levenshtein("kitten", "sitting"))
+	assert.Equal(t, 4, levenshtein("", "milk"))
+}
+
+func TestEngine_SearchFoodsByName_ReturnNearestOnEmpty(t *testing.T) {
+	testData := &FoundationFoodsData{
+		FoundationFoods: []FoundationFood{
+			{Description: "Milk, whole, 3.25% milkfat", FdcId: 1},
+			{Description: "Broccoli, raw", FdcId: 2},
+			{Description: "Cheese, cheddar", FdcId: 3},
+		},
+	}
+
+	engine := &Engine{
+		data:   testData,
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+
+	t.Run("returns nothing by default", func(t *testing.T) {
+		results, err := engine.SearchFoodsByName(context.Background(), "brocoli", 2, SearchOptions{})
+
+		require.NoError(t, err)
+		assert.Empty(t, results)
+	})
+
+	t.Run("returns flagged fuzzy fallbacks when enabled", func(t *testing.T) {
+		results, err := engine.SearchFoodsByName(context.Background(), "brocoli", 2, SearchOptions{ReturnNearestOnEmpty: true})
+
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.Equal(t, "Broccoli, raw", results[0].Description)
+		for _, food := range results {
+			assert.True(t, food.FuzzyFallback)
+		}
+	})
+
+	t.Run("leaves scored matches unflagged", func(t *testing.T) {
+		results, err := engine.SearchFoodsByName(context.Background(), "broccoli", 2, SearchOptions{ReturnNearestOnEmpty: true})
+
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.False(t, results[0].FuzzyFallback)
+	})
+
+	t.Run("flags simplified fallbacks", func(t *testing.T) {
+		response, err := engine.SearchFoodsByNameSimplified(context.Background(), "chedar", 1, nil, SearchOptions{ReturnNearestOnEmpty: true})
+
+		require.NoError(t, err)
+		require.Len(t, response.Foods, 1)
+		assert.Equal(t, "Cheese, cheddar", response.Foods[0].Name)
+		assert.True(t, response.Foods[0].FuzzyFallback)
+	})
+}
+
+func TestEngine_SearchFoodsByName_Fuzzy(t *testing.T) {
+	testData := &FoundationFoodsData{
+		FoundationFoods: []FoundationFood{
+			{Description: "Broccoli, raw", FdcId: 1},
+			{Description: "Yogurt, Greek, plain, nonfat", FdcId: 2},
+			{Description: "Cheese, cheddar", FdcId: 3},
+			{Description: "Milk, whole, 3.25% milkfat", FdcId: 4},
+		},
+	}
+
+	engine := &Engine{
+		data:   testData,
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+	ctx := context.Background()
+
+	misspellings := map[string]int{
+		"brocolli":      1,
+		"brocoli":       1,
+		"yoghurt":       2,
+		"chedar cheese": 3,
+		"milc":          4,
+	}
+
+	for query, fdcId := range misspellings {
+		t.Run(query, func(t *testing.T) {
+			precise, err := engine.SearchFoodsByName(ctx, query, 5, SearchOptions{})
+			require.NoError(t, err)
+			if query != "chedar cheese" {
+				assert.Empty(t, precise)
+			}
+
+			results, err := engine.SearchFoodsByName(ctx, query, 5, SearchOptions{Fuzzy: true})
+			require.NoError(t, err)
+			require.NotEmpty(t, results)
+			assert.Equal(t, fdcId, results[0].FdcId)
+			assert.False(t, results[0].FuzzyFallback)
+		})
+	}
+
+	t.Run("ignores words too far from any description word", func(t *testing.T) {
+		results, err := engine.SearchFoodsByName(ctx, "banana", 5, SearchOptions{Fuzzy: true})
+		require.NoError(t, err)
+		assert.Empty(t, results)
+	})
+}
+
+func TestFuzzyWordScore(t *testing.T) {
+	descWords := []string{"broccoli", "raw"}
+
+	assert.Equal(t, 20.0, DefaultScoringWeights.fuzzyWordScore("brocoli", descWords))
+	assert.Equal(t, 12.0, DefaultScoringWeights.fuzzyWordScore("brocolli", descWords))
+	// Short words allow only one edit
+	assert.Equal(t, 20.0, DefaultScoringWeights.fuzzyWordScore("rew", descWords))
+	assert.Equal(t, 0.0, DefaultScoringWeights.fuzzyWordScore("rxyz", descWords))
+	// Two-letter words never match fuzzily
+	assert.Equal(t, 0.0, DefaultScoringWeights.fuzzyWordScore("rw", descWords))
+}
@@ -0,0 +1,127 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// matchNutrient returns a food's first nutrient entry named nutrientName, accepting the alternative
+// names shouldIncludeNutrient accepts
+func (e *Engine) matchNutrient(food *FoundationFood, nutrientName string) (*FoodNutrient, bool) {
+	filterName := strings.ToLower(strings.TrimSpace(nutrientName))
+	for i := range food.FoodNutrients {
+		dataName := strings.ToLower(strings.TrimSpace(food.FoodNutrients[i].Nutrient.Name))
+		if dataName == filterName || e.isAlternativeNutrientName(dataName, filterName) {
+			return &food.FoodNutrients[i], true
+		}
+	}
+	return nil, false
+}
+
+// FindFoodsByNutrient returns the foods with the highest amount of a nutrient per 100 g, ties broken by
+// description. Amounts are compared after normalizing mass units to grams and energy to kcal, so mixed
+// units rank correctly. Foods lacking the nutrient are excluded.
+func (e *Engine) FindFoodsByNutrient(ctx context.Context, nutrientName string, limit int, opts SearchOptions) (*NutrientRankingResponse, error) {
+	if strings.TrimSpace(nutrientName) == "" {
+		return nil, fmt.Errorf("nutrient name must not be empty")
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.data == nil {
+		return nil, fmt.Errorf("foundation Foods data not loaded")
+	}
+
+	foods := e.rankFoodsByNutrient(nutrientName, limit, opts, nil)
+
+	return &NutrientRankingResponse{
+		Nutrient: nutrientName,
+		Count:    len(foods),
+		Foods:    foods,
+	}, nil
+}
+
+// FindFoodsByNutrientRange returns the foods whose amount of a nutrient per 100 g, in the dataset's unit
+// for it, lies within the inclusive range. A nil bound leaves that side open. Foods are ordered like
+// FindFoodsByNutrient.
+func (e *Engine) FindFoodsByNutrientRange(ctx context.Context, nutrientName string, minAmount, maxAmount *float64, limit int, opts SearchOptions) (*NutrientRangeResponse, error) {
+	if strings.TrimSpace(nutrientName) == "" {
+		return nil, fmt.Errorf("nutrient name must not be empty")
+	}
+	if minAmount != nil && maxAmount != nil && *minAmount > *maxAmount {
+		return nil, fmt.Errorf("min %g is greater than max %g", *minAmount, *maxAmount)
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.data == nil {
+		return nil, fmt.Errorf("foundation Foods data not loaded")
+	}
+
+	foods := e.rankFoodsByNutrient(nutrientName, limit, opts, func(amount float64) bool {
+		return (minAmount == nil || amount >= *minAmount) && (maxAmount == nil || amount <= *maxAmount)
+	})
+
+	return &NutrientRangeResponse{
+		Nutrient: nutrientName,
+		Min:      minAmount,
+		Max:      maxAmount,
+		Count:    len(foods),
+		Foods:    foods,
+	}, nil
+}
+
+// rankFoodsByNutrient returns up to limit foods reporting a nutrient whose amount passes keep (nil keeps
+// all), highest normalized amount first and ties broken by description. The caller must hold the read lock.
+func (e *Engine) rankFoodsByNutrient(nutrientName string, limit int, opts SearchOptions, keep func(amount float64) bool) []NutrientRankedFood {
+	type rankedFood struct {
+		food       NutrientRankedFood
+		normalized float64
+	}
+
+	var ranked []rankedFood
+	for _, food := range e.data.FoundationFoods {
+		if !matchesCategory(food, opts.Category) {
+			continue
+		}
+
+		nutrient, ok := e.matchNutrient(&food, nutrientName)
+		if !ok || (keep != nil && !keep(nutrient.Amount)) {
+			continue
+		}
+
+		normalized, _ := normalizeNutrientUnit(nutrient.Amount, nutrient.Nutrient.UnitName)
+		ranked = append(ranked, rankedFood{
+			food: NutrientRankedFood{
+				FdcId:       food.FdcId,
+				Description: food.Description,
+				Category:    food.FoodCategory.Description,
+				Amount:      nutrient.Amount,
+				Unit:        nutrient.Nutrient.UnitName,
+			},
+			normalized: normalized,
+		})
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].normalized != ranked[j].normalized {
+			return ranked[i].normalized > ranked[j].normalized
+		}
+		return ranked[i].food.Description < ranked[j].food.Description
+	})
+
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+
+	foods := make([]NutrientRankedFood, len(ranked))
+	for i, r := range ranked {
+		foods[i] = r.food
+	}
+
+	return foods
+}
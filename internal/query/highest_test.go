@@ -0,0 +1,150 @@
+package query
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_FindFoodsByNutrient(t *testing.T) {
+	calcium := func(amount float64, unit string) []FoodNutrient {
+		return []FoodNutrient{{Nutrient: Nutrient{Name: "Calcium, Ca", UnitName: unit}, Amount: amount}}
+	}
+
+	testData := &FoundationFoodsData{
+		FoundationFoods: []FoundationFood{
+			{Description: "Milk, whole", FdcId: 1, FoodNutrients: calcium(113, "mg"), FoodCategory: FoodCategory{Description: "Dairy and Egg Products"}},
+			{Description: "Cheese, parmesan", FdcId: 2, FoodNutrients: calcium(1.18, "g"), FoodCategory: FoodCategory{Description: "Dairy and Egg Products"}},
+			{Description: "Kale, raw", FdcId: 3, FoodNutrients: calcium(254, "mg"), FoodCategory: FoodCategory{Description: "Vegetables and Vegetable Products"}},
+			{Description: "Collards, raw", FdcId: 4, FoodNutrients: calcium(254, "mg"), FoodCategory: FoodCategory{Description: "Vegetables and Vegetable Products"}},
+			{Description: "Oil, olive", FdcId: 5},
+			{
+				Description: "Oranges, raw", FdcId: 6,
+				FoodNutrients: []FoodNutrient{{Nutrient: Nutrient{Name: "Vitamin C, total ascorbic acid", UnitName: "mg"}, Amount: 53.2}},
+			},
+		},
+	}
+
+	engine := &Engine{
+		data:   testData,
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+	ctx := context.Background()
+
+	ids := func(foods []NutrientRankedFood) []int {
+		result := make([]int, len(foods))
+		for i, food := range foods {
+			result[i] = food.FdcId
+		}
+		return result
+	}
+
+	t.Run("ranks by normalized amount and breaks ties by description", func(t *testing.T) {
+		result, err := engine.FindFoodsByNutrient(ctx, "calcium, ca", 10, SearchOptions{})
+
+		require.NoError(t, err)
+		assert.Equal(t, []int{2, 4, 3, 1}, ids(result.Foods))
+		assert.Equal(t, 4, result.Count)
+		assert.Equal(t, 1.18, result.Foods[0].Amount)
+		assert.Equal(t, "g", result.Foods[0].Unit)
+	})
+
+	t.Run("applies the limit and category", func(t *testing.T) {
+		result, err := engine.FindFoodsByNutrient(ctx, "Calcium, Ca", 1, SearchOptions{Category: "dairy and egg products"})
+
+		require.NoError(t, err)
+		assert.Equal(t, []int{2}, ids(result.Foods))
+	})
+
+	t.Run("matches alternative nutrient names", func(t *testing.T) {
+		result, err := engine.FindFoodsByNutrient(ctx, "Vitamin C", 10, SearchOptions{})
+
+		require.NoError(t, err)
+		assert.Equal(t, []int{6}, ids(result.Foods))
+	})
+
+	t.Run("returns no foods for an unknown nutrient", func(t *testing.T) {
+		result, err := engine.FindFoodsByNutrient(ctx, "Unobtainium", 10, SearchOptions{})
+
+		require.NoError(t, err)
+		assert.Empty(t, result.Foods)
+	})
+
+	t.Run("rejects an empty nutrient name", func(t *testing.T) {
+		_, err := engine.FindFoodsByNutrient(ctx, " ", 10, SearchOptions{})
+		assert.Error(t, err)
+	})
+}
+
+func TestEngine_FindFoodsByNutrientRange(t *testing.T) {
+	saturatedFat := func(amount float64) []FoodNutrient {
+		return []FoodNutrient{{Nutrient: Nutrient{Name: "Fatty acids, total saturated", UnitName: "g"}, Amount: amount}}
+	}
+
+	testData := &FoundationFoodsData{
+		FoundationFoods: []FoundationFood{
+			{Description: "Butter, salted", FdcId: 1, FoodNutrients: saturatedFat(51.4)},
+			{Description: "Chicken, breast, raw", FdcId: 2, FoodNutrients: saturatedFat(0.6)},
+			{Description: "Broccoli, raw", FdcId: 3, FoodNutrients: saturatedFat(0)},
+			{Description: "Milk, whole", FdcId: 4, FoodNutrients: saturatedFat(1)},
+			{Description: "Water, tap", FdcId: 5},
+			{
+				Description: "Kiwifruit, green, raw", FdcId: 6,
+				FoodNutrients: []FoodNutrient{{Nutrient: Nutrient{Name: "Vitamin C, total ascorbic acid", UnitName: "mg"}, Amount: 92.7}},
+			},
+		},
+	}
+
+	engine := &Engine{
+		data:   testData,
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+	ctx := context.Background()
+	bound := func(v float64) *float64 { return &v }
+
+	ids := func(foods []NutrientRankedFood) []int {
+		result := make([]int, len(foods))
+		for i, food := range foods {
+			result[i] = food.FdcId
+		}
+		return result
+	}
+
+	t.Run("includes both bounds", func(t *testing.T) {
+		result, err := engine.FindFoodsByNutrientRange(ctx, "Fatty acids, total saturated", bound(0), bound(1), 10, SearchOptions{})
+
+		require.NoError(t, err)
+		assert.Equal(t, []int{4, 2, 3}, ids(result.Foods))
+		assert.Equal(t, 1.0, result.Foods[0].Amount)
+		assert.Equal(t, "g", result.Foods[0].Unit)
+		assert.Equal(t, 0.0, *result.Min)
+		assert.Equal(t, 1.0, *result.Max)
+	})
+
+	t.Run("leaves a nil bound open", func(t *testing.T) {
+		above, err := engine.FindFoodsByNutrientRange(ctx, "Fatty acids, total saturated", bound(20), nil, 10, SearchOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []int{1}, ids(above.Foods))
+		assert.Nil(t, above.Max)
+
+		all, err := engine.FindFoodsByNutrientRange(ctx, "Fatty acids, total saturated", nil, nil, 2, SearchOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 4}, ids(all.Foods))
+	})
+
+	t.Run("matches alternative nutrient names", func(t *testing.T) {
+		result, err := engine.FindFoodsByNutrientRange(ctx, "Vitamin C", bound(50), nil, 10, SearchOptions{})
+
+		require.NoError(t, err)
+		assert.Equal(t, []int{6}, ids(result.Foods))
+	})
+
+	t.Run("rejects an inverted range", func(t *testing.T) {
+		_, err := engine.FindFoodsByNutrientRange(ctx, "Protein", bound(5), bound(1), 10, SearchOptions{})
+		assert.Error(t, err)
+	})
+}
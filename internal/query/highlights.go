@@ -0,0 +1,124 @@
+package query
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// descriptionToken is a word of a food description as normalizeString would produce it, with the
+// character (rune) offsets of the word in the original description
+type descriptionToken struct {
+	word       string
+	start, end int
+}
+
+// isStrippedPunctuation reports whether normalizeString removes the rune from descriptions
+func isStrippedPunctuation(r rune) bool {
+	return r == ',' || r == '.' || r == '(' || r == ')'
+}
+
+// descriptionTokens splits a description into the same words as strings.Fields(normalizeString(description)),
+// keeping where each word is in the original. A word's range excludes the punctuation normalization strips,
+// and "&" becomes the word "and" spanning the ampersand.
+func descriptionTokens(description string) []descriptionToken {
+	var tokens []descriptionToken
+	var word strings.Builder
+	start, end := -1, -1
+
+	flush := func() {
+		if word.Len() > 0 {
+			tokens = append(tokens, descriptionToken{word: strings.ToLower(word.String()), start: start, end: end})
+		}
+		word.Reset()
+		start, end = -1, -1
+	}
+
+	for i, r := range []rune(description) {
+		switch {
+		case unicode.IsSpace(r):
+			flush()
+		case r == '&':
+			flush()
+			tokens = append(tokens, descriptionToken{word: "and", start: i, end: i + 1})
+		case isStrippedPunctuation(r):
+			// Dropped from the word without splitting it, as normalizeString does
+		default:
+			if start < 0 {
+				start = i
+			}
+			end = i + 1
+			word.WriteRune(r)
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+// matchHighlights returns where each query word matched a description, using the same word-level rules as
+// scoring: every description word the query word literally matches, or failing that the words of its first
+// matching synonym, or with fuzzy set the words within a typo of it. Highlights are ordered by offset.
+func matchHighlights(description string, queryWords []string, fuzzy bool, synonyms map[string][][]string) []MatchHighlight {
+	tokens := descriptionTokens(description)
+	descWords := make([]string, len(tokens))
+	for i, token := range tokens {
+		descWords[i] = token.word
+	}
+
+	highlights := []MatchHighlight{}
+	add := func(queryWord, kind string, token descriptionToken) {
+		highlights = append(highlights, MatchHighlight{Word: queryWord, Match: kind, Start: token.start, End: token.end})
+	}
+
+	for _, queryWord := range queryWords {
+		matched := false
+		for _, token := range tokens {
+			if kind := matchWord(queryWord, token.word); kind != "" {
+				add(queryWord, kind, token)
+				matched = true
+			}
+		}
+		if matched {
+			continue
+		}
+
+		for _, phrase := range synonyms[queryWord] {
+			if !containsAllWords(descWords, phrase) {
+				continue
+			}
+			for _, token := range tokens {
+				if phraseContains(phrase, token.word) {
+					add(queryWord, MatchSynonym, token)
+				}
+			}
+			matched = true
+			break
+		}
+		if matched || !fuzzy {
+			continue
+		}
+
+		for _, token := range tokens {
+			if _, ok := fuzzyEdits(queryWord, []string{token.word}); ok {
+				add(queryWord, MatchFuzzy, token)
+			}
+		}
+	}
+
+	sort.SliceStable(highlights, func(i, j int) bool {
+		return highlights[i].Start < highlights[j].Start
+	})
+
+	return highlights
+}
+
+// phraseContains reports whether a description word is one of a synonym phrase's words or their singular/plural
+func phraseContains(phrase []string, descWord string) bool {
+	for _, word := range phrase {
+		if descWord == word || pluralEquivalent(descWord, word) {
+			return true
+		}
+	}
+	return false
+}
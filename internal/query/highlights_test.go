@@ -0,0 +1,100 @@
+package query
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescriptionTokens_MatchNormalizedWords(t *testing.T) {
+	descriptions := []string{
+		"Milk, whole, 3.25% milkfat",
+		"Green onion, (scallion), bulb and greens, root removed, raw",
+		"Corn, sweet, yellow and white kernels,  fresh, raw",
+		"Dairy & Egg Products",
+		"Macaroni&cheese",
+		"Crème fraîche",
+	}
+
+	for _, description := range descriptions {
+		t.Run(description, func(t *testing.T) {
+			tokens := descriptionTokens(description)
+			words := make([]string, len(tokens))
+			for i, token := range tokens {
+				words[i] = token.word
+			}
+			assert.Equal(t, strings.Fields(normalizeString(description)), words)
+		})
+	}
+}
+
+func TestMatchHighlights(t *testing.T) {
+	highlighted := func(description string, highlights []MatchHighlight) []string {
+		runes := []rune(description)
+		var words []string
+		for _, highlight := range highlights {
+			words = append(words, highlight.Match+":"+string(runes[highlight.Start:highlight.End]))
+		}
+		return words
+	}
+
+	t.Run("literal matches in description order", func(t *testing.T) {
+		description := "Green onion, (scallion), raw"
+		highlights := matchHighlights(description, []string{"scallion", "onions", "gre"}, false, nil)
+
+		assert.Equal(t, []string{"prefix:Green", "plural:onion", "exact:scallion"}, highlighted(description, highlights))
+		assert.Equal(t, MatchHighlight{Word: "gre", Match: MatchPrefix, Start: 0, End: 5}, highlights[0])
+	})
+
+	t.Run("offsets count characters, not bytes", func(t *testing.T) {
+		description := "Crème fraîche, full fat"
+		highlights := matchHighlights(description, []string{"fat"}, false, nil)
+
+		require.Len(t, highlights, 1)
+		assert.Equal(t, 20, highlights[0].Start)
+		assert.Equal(t, []string{"exact:fat"}, highlighted(description, highlights))
+	})
+
+	t.Run("synonyms when nothing matches literally", func(t *testing.T) {
+		description := "Beef, ground, 80% lean meat / 20% fat, raw"
+		highlights := matchHighlights(description, []string{"hamburger"}, false, defaultSynonymTable)
+
+		assert.Equal(t, []string{"synonym:Beef", "synonym:ground"}, highlighted(description, highlights))
+	})
+
+	t.Run("fuzzy only when requested", func(t *testing.T) {
+		description := "Broccoli, raw"
+
+		assert.Empty(t, matchHighlights(description, []string{"brocolli"}, false, nil))
+		assert.Equal(t, []string{"fuzzy:Broccoli"},
+			highlighted(description, matchHighlights(description, []string{"brocolli"}, true, nil)))
+	})
+}
+
+func TestEngine_SearchFoodsByName_Highlights(t *testing.T) {
+	engine := &Engine{
+		data: &FoundationFoodsData{
+			FoundationFoods: []FoundationFood{
+				{Description: "Eggs, Grade A, Large, egg whole", FdcId: 1},
+			},
+		},
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+
+	foods, err := engine.SearchFoodsByName(context.Background(), "egg", 10, SearchOptions{IncludeHighlights: true})
+	require.NoError(t, err)
+	require.Len(t, foods, 1)
+	assert.Equal(t, []MatchHighlight{
+		{Word: "egg", Match: MatchPlural, Start: 0, End: 4},
+		{Word: "egg", Match: MatchExact, Start: 22, End: 25},
+	}, foods[0].Highlights)
+
+	foods, err = engine.SearchFoodsByName(context.Background(), "egg", 10, SearchOptions{})
+	require.NoError(t, err)
+	assert.Nil(t, foods[0].Highlights)
+}
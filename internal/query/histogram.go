@@ -0,0 +1,94 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// NutrientHistogram buckets the amount of one nutrient across every food reporting it into equal-width
+// buckets between the smallest and largest amount. Amounts are normalized to grams or kcal first; foods
+// whose normalized unit differs from the most common one are skipped.
+func (e *Engine) NutrientHistogram(ctx context.Context, nutrientName string, buckets int, opts SearchOptions) (*NutrientHistogramResponse, error) {
+	if strings.TrimSpace(nutrientName) == "" {
+		return nil, fmt.Errorf("nutrient name must not be empty")
+	}
+	if buckets < 1 {
+		return nil, fmt.Errorf("bucket count must be at least 1")
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.data == nil {
+		return nil, fmt.Errorf("foundation Foods data not loaded")
+	}
+
+	amountsByUnit := make(map[string][]float64)
+	var name string
+	for i := range e.data.FoundationFoods {
+		food := &e.data.FoundationFoods[i]
+		if !matchesCategory(*food, opts.Category) {
+			continue
+		}
+
+		nutrient, ok := findNutrient(food, nutrientName)
+		if !ok {
+			continue
+		}
+		if name == "" {
+			name = nutrient.Nutrient.Name
+		}
+
+		amount, unit := normalizeNutrientUnit(nutrient.Amount, nutrient.Nutrient.UnitName)
+		amountsByUnit[unit] = append(amountsByUnit[unit], amount)
+	}
+
+	if len(amountsByUnit) == 0 {
+		return nil, fmt.Errorf("no foods report nutrient %q", nutrientName)
+	}
+
+	var unit string
+	var total int
+	for candidate, amounts := range amountsByUnit {
+		total += len(amounts)
+		if len(amounts) > len(amountsByUnit[unit]) || (len(amounts) == len(amountsByUnit[unit]) && candidate < unit) {
+			unit = candidate
+		}
+	}
+	amounts := amountsByUnit[unit]
+
+	low, high := amounts[0], amounts[0]
+	for _, amount := range amounts {
+		low = min(low, amount)
+		high = max(high, amount)
+	}
+
+	width := (high - low) / float64(buckets)
+	response := &NutrientHistogramResponse{
+		Nutrient:  name,
+		Unit:      unit,
+		Category:  opts.Category,
+		FoodCount: len(amounts),
+		Skipped:   total - len(amounts),
+		Min:       low,
+		Max:       high,
+		Buckets:   make([]HistogramBucket, buckets),
+	}
+	for i := range response.Buckets {
+		response.Buckets[i].Min = low + float64(i)*width
+		response.Buckets[i].Max = low + float64(i+1)*width
+	}
+	response.Buckets[buckets-1].Max = high
+
+	for _, amount := range amounts {
+		index := buckets - 1
+		if width > 0 {
+			// The largest amount falls into the last bucket rather than past it
+			index = min(int((amount-low)/width), buckets-1)
+		}
+		response.Buckets[index].Count++
+	}
+
+	return response, nil
+}
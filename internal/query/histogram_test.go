@@ -0,0 +1,74 @@
+package query
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_NutrientHistogram(t *testing.T) {
+	sodium := func(fdcId int, category string, amount float64, unit string) FoundationFood {
+		return FoundationFood{
+			FdcId:        fdcId,
+			Description:  "Food",
+			FoodCategory: FoodCategory{Description: category},
+			FoodNutrients: []FoodNutrient{
+				{Nutrient: Nutrient{Name: "Sodium, Na", UnitName: unit}, Amount: amount},
+			},
+		}
+	}
+
+	testData := &FoundationFoodsData{
+		FoundationFoods: []FoundationFood{
+			sodium(1, "Vegetables and Vegetable Products", 0, "mg"),
+			sodium(2, "Vegetables and Vegetable Products", 100, "mg"),
+			sodium(3, "Dairy and Egg Products", 0.45, "g"),
+			sodium(4, "Dairy and Egg Products", 600, "mg"),
+			sodium(5, "Dairy and Egg Products", 1000, "mg"),
+			{FdcId: 6, Description: "Oil, olive"},
+		},
+	}
+
+	engine := &Engine{
+		data:   testData,
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+
+	t.Run("counts foods per bucket after normalizing units", func(t *testing.T) {
+		result, err := engine.NutrientHistogram(context.Background(), "sodium, na", 4, SearchOptions{})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Sodium, Na", result.Nutrient)
+		assert.Equal(t, "g", result.Unit)
+		assert.Equal(t, 5, result.FoodCount)
+		assert.InDelta(t, 0, result.Min, 0.0001)
+		assert.InDelta(t, 1, result.Max, 0.0001)
+		require.Len(t, result.Buckets, 4)
+
+		counts := make([]int, len(result.Buckets))
+		for i, bucket := range result.Buckets {
+			counts[i] = bucket.Count
+		}
+		assert.Equal(t, []int{2, 1, 1, 1}, counts)
+		assert.InDelta(t, 0.25, result.Buckets[0].Max, 0.0001)
+	})
+
+	t.Run("filters by category", func(t *testing.T) {
+		result, err := engine.NutrientHistogram(context.Background(), "Sodium, Na", 2, SearchOptions{Category: "dairy and egg products"})
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, result.FoodCount)
+		assert.Equal(t, 2, result.Buckets[0].Count)
+		assert.Equal(t, 1, result.Buckets[1].Count)
+	})
+
+	t.Run("errors when no food reports the nutrient", func(t *testing.T) {
+		_, err := engine.NutrientHistogram(context.Background(), "Caffeine", 4, SearchOptions{})
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,127 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// datasetDatePattern extracts the release date from USDA file names like foundationfoods_2025-04-24.json
+var datasetDatePattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2}`)
+
+// datasetSnapshot is an older dataset release kept for nutrient history lookups
+type datasetSnapshot struct {
+	date  string
+	foods map[int]*FoundationFood
+}
+
+// WithHistoryFiles loads additional dataset releases so nutrient values can be compared across snapshots.
+// Only the current dataset is searched; history files are used by NutrientHistory.
+func WithHistoryFiles(paths ...string) EngineOption {
+	return func(e *Engine) {
+		e.historyFiles = paths
+	}
+}
+
+// datasetDate labels a dataset by the date in its file name, falling back to the file name itself
+func datasetDate(path string) string {
+	name := filepath.Base(path)
+	if date := datasetDatePattern.FindString(name); date != "" {
+		return date
+	}
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// loadSnapshot reads a history dataset and indexes its foods by FDC ID
+func loadSnapshot(path string) (*datasetSnapshot, error) {
+	file, err := openDataset(osFS{}, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history data file %s: %w", path, err)
+	}
+	defer file.Close()
+
+	data, err := decodeFoundationFoods(file, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse history data file %s: %w", path, err)
+	}
+
+	return newSnapshot(datasetDate(path), data.FoundationFoods), nil
+}
+
+// newSnapshot indexes a dataset's foods by FDC ID
+func newSnapshot(date string, foods []FoundationFood) *datasetSnapshot {
+	snapshot := &datasetSnapshot{
+		date:  date,
+		foods: make(map[int]*FoundationFood, len(foods)),
+	}
+	for i := range foods {
+		snapshot.foods[foods[i].FdcId] = &foods[i]
+	}
+	return snapshot
+}
+
+// findNutrient returns the first nutrient of a food whose name matches case-insensitively
+func findNutrient(food *FoundationFood, nutrientName string) (*FoodNutrient, bool) {
+	for i := range food.FoodNutrients {
+		if strings.EqualFold(strings.TrimSpace(food.FoodNutrients[i].Nutrient.Name), strings.TrimSpace(nutrientName)) {
+			return &food.FoodNutrients[i], true
+		}
+	}
+	return nil, false
+}
+
+// NutrientHistory returns a food's nutrient amount in every loaded dataset release, oldest first.
+// Releases where the food or nutrient is absent produce a point with a null amount.
+func (e *Engine) NutrientHistory(ctx context.Context, fdcId int, nutrientName string) (*NutrientHistoryResponse, error) {
+	if strings.TrimSpace(nutrientName) == "" {
+		return nil, fmt.Errorf("nutrient name must not be empty")
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.data == nil {
+		return nil, fmt.Errorf("foundation Foods data not loaded")
+	}
+
+	// A food missing from the current dataset is just a null point, not an error
+	current, _ := e.getFoodByFdcId(fdcId)
+
+	points := make([]NutrientHistoryPoint, 0, len(e.history)+1)
+	description := ""
+	addPoint := func(date string, food *FoundationFood) {
+		point := NutrientHistoryPoint{Dataset: date}
+		if food != nil {
+			if description == "" {
+				description = food.Description
+			}
+			if nutrient, ok := findNutrient(food, nutrientName); ok {
+				amount := nutrient.Amount
+				point.Amount = &amount
+				point.Unit = nutrient.Nutrient.UnitName
+			}
+		}
+		points = append(points, point)
+	}
+
+	addPoint(e.datasetDate, current)
+	for _, snapshot := range e.history {
+		addPoint(snapshot.date, snapshot.foods[fdcId])
+	}
+
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].Dataset < points[j].Dataset
+	})
+
+	response := &NutrientHistoryResponse{
+		FdcId:       fdcId,
+		Description: description,
+		Nutrient:    nutrientName,
+		Points:      points,
+	}
+
+	return response, nil
+}
@@ -0,0 +1,77 @@
+package query
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_NutrientHistory(t *testing.T) {
+	logger := config.NewTestLogger(io.Discard, "debug")
+	dir := t.TempDir()
+
+	older := filepath.Join(dir, "foundationfoods_2024-10-31.json")
+	require.NoError(t, os.WriteFile(older, []byte(`{"FoundationFoods": [
+		{"description": "Milk, whole", "fdcId": 1, "foodNutrients": [
+			{"nutrient": {"name": "Protein", "unitName": "g"}, "amount": 3.1}
+		]}
+	]}`), 0o600))
+
+	current := filepath.Join(dir, "foundationfoods_2025-04-24.json")
+	require.NoError(t, os.WriteFile(current, []byte(`{"FoundationFoods": [
+		{"description": "Milk, whole", "fdcId": 1, "foodNutrients": [
+			{"nutrient": {"name": "Protein", "unitName": "g"}, "amount": 3.3},
+			{"nutrient": {"name": "Vitamin D (D2 + D3)", "unitName": "µg"}, "amount": 1.1}
+		]}
+	]}`), 0o600))
+
+	engine, err := NewEngine(current, logger, WithHistoryFiles(older))
+	require.NoError(t, err)
+
+	t.Run("returns the amount in each dataset oldest first", func(t *testing.T) {
+		result, err := engine.NutrientHistory(context.Background(), 1, "protein")
+
+		require.NoError(t, err)
+		assert.Equal(t, "Milk, whole", result.Description)
+		require.Len(t, result.Points, 2)
+
+		assert.Equal(t, "2024-10-31", result.Points[0].Dataset)
+		require.NotNil(t, result.Points[0].Amount)
+		assert.Equal(t, 3.1, *result.Points[0].Amount)
+
+		assert.Equal(t, "2025-04-24", result.Points[1].Dataset)
+		require.NotNil(t, result.Points[1].Amount)
+		assert.Equal(t, 3.3, *result.Points[1].Amount)
+		assert.Equal(t, "g", result.Points[1].Unit)
+	})
+
+	t.Run("returns null points where the nutrient is absent", func(t *testing.T) {
+		result, err := engine.NutrientHistory(context.Background(), 1, "Vitamin D (D2 + D3)")
+
+		require.NoError(t, err)
+		require.Len(t, result.Points, 2)
+		assert.Nil(t, result.Points[0].Amount)
+		require.NotNil(t, result.Points[1].Amount)
+	})
+
+	t.Run("returns null points for an unknown food", func(t *testing.T) {
+		result, err := engine.NutrientHistory(context.Background(), 999, "Protein")
+
+		require.NoError(t, err)
+		require.Len(t, result.Points, 2)
+		assert.Nil(t, result.Points[0].Amount)
+		assert.Nil(t, result.Points[1].Amount)
+	})
+
+	t.Run("fails to load a missing history file", func(t *testing.T) {
+		_, err := NewEngine(current, logger, WithHistoryFiles(filepath.Join(dir, "missing.json")))
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,162 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseHouseholdMeasure splits a household measure like "2 cups", "1/2 cup" or "tbsp" into a quantity and unit.
+// A missing quantity means one unit.
+func parseHouseholdMeasure(measure string) (float64, string, error) {
+	fields := strings.Fields(strings.TrimSpace(measure))
+	if len(fields) == 0 {
+		return 0, "", fmt.Errorf("measure must not be empty")
+	}
+
+	quantity, err := parseQuantity(fields[0])
+	if err != nil {
+		// No leading number, the whole measure is the unit
+		return 1, strings.Join(fields, " "), nil
+	}
+	if len(fields) == 1 {
+		return 0, "", fmt.Errorf("measure %q has no unit", measure)
+	}
+	if quantity <= 0 {
+		return 0, "", fmt.Errorf("measure %q must have a positive quantity", measure)
+	}
+
+	return quantity, strings.Join(fields[1:], " "), nil
+}
+
+// parseQuantity parses a decimal ("1.5") or simple fraction ("1/2") quantity
+func parseQuantity(value string) (float64, error) {
+	if numerator, denominator, ok := strings.Cut(value, "/"); ok {
+		n, err := strconv.ParseFloat(numerator, 64)
+		if err != nil {
+			return 0, err
+		}
+		d, err := strconv.ParseFloat(denominator, 64)
+		if err != nil {
+			return 0, err
+		}
+		if d == 0 {
+			return 0, fmt.Errorf("division by zero in %q", value)
+		}
+		return n / d, nil
+	}
+
+	return strconv.ParseFloat(value, 64)
+}
+
+// portionMatchesUnit reports whether a portion's measure unit name or abbreviation matches unit,
+// ignoring case and a plural "s"/"es" suffix
+func portionMatchesUnit(portion FoodPortion, unit string) bool {
+	candidates := []string{strings.ToLower(strings.TrimSpace(unit))}
+	if singular, ok := strings.CutSuffix(candidates[0], "es"); ok {
+		candidates = append(candidates, singular)
+	}
+	if singular, ok := strings.CutSuffix(candidates[0], "s"); ok {
+		candidates = append(candidates, singular)
+	}
+
+	name := strings.ToLower(strings.TrimSpace(portion.MeasureUnit.Name))
+	abbreviation := strings.ToLower(strings.TrimSpace(portion.MeasureUnit.Abbreviation))
+	for _, candidate := range candidates {
+		if candidate != "" && (candidate == name || candidate == abbreviation) {
+			return true
+		}
+	}
+	return false
+}
+
+// availablePortionUnits lists the distinct measure unit names of a food's portions that have a gram weight
+func availablePortionUnits(food *FoundationFood) []string {
+	var units []string
+	seen := make(map[string]bool)
+	for _, portion := range sortedPortions(food.FoodPortions) {
+		name := strings.TrimSpace(portion.MeasureUnit.Name)
+		if portion.GramWeight <= 0 || name == "" || seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+		units = append(units, name)
+	}
+	return units
+}
+
+// resolveHouseholdGrams converts a household measure like "2 cups" to grams through the food's portions,
+// returning the grams, the parsed quantity and the matched portion's unit name
+func resolveHouseholdGrams(food *FoundationFood, measure string) (float64, float64, string, error) {
+	quantity, unit, err := parseHouseholdMeasure(measure)
+	if err != nil {
+		return 0, 0, "", err
+	}
+
+	var portion *FoodPortion
+	for _, candidate := range sortedPortions(food.FoodPortions) {
+		if candidate.GramWeight > 0 && portionMatchesUnit(candidate, unit) {
+			portion = &candidate
+			break
+		}
+	}
+	if portion == nil {
+		available := availablePortionUnits(food)
+		if len(available) == 0 {
+			return 0, 0, "", fmt.Errorf("food %q has no household portions", food.Description)
+		}
+		return 0, 0, "", fmt.Errorf("unit %q is not available for food %q, available units: %s",
+			unit, food.Description, strings.Join(available, ", "))
+	}
+
+	// A portion describes Value units weighing GramWeight grams, e.g. 1 cup = 244 g
+	unitsPerPortion := portion.Value
+	if unitsPerPortion <= 0 {
+		unitsPerPortion = 1
+	}
+
+	return quantity / unitsPerPortion * portion.GramWeight, quantity, portion.MeasureUnit.Name, nil
+}
+
+// NutrientsForHouseholdPortion resolves a household measure like "2 cups" to grams through the food's
+// portions and returns every nutrient scaled to that amount
+func (e *Engine) NutrientsForHouseholdPortion(ctx context.Context, fdcId int, measure string) (*HouseholdPortionResponse, error) {
+	if _, _, err := parseHouseholdMeasure(measure); err != nil {
+		return nil, err
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	food, err := e.getFoodByFdcId(fdcId)
+	if err != nil {
+		return nil, err
+	}
+
+	grams, quantity, unit, err := resolveHouseholdGrams(food, measure)
+	if err != nil {
+		return nil, err
+	}
+	factor := grams / 100
+
+	response := &HouseholdPortionResponse{
+		FdcId:       food.FdcId,
+		Description: food.Description,
+		Measure:     measure,
+		Quantity:    quantity,
+		Unit:        unit,
+		Grams:       grams,
+		Nutrients:   make([]SimplifiedNutrient, 0, len(food.FoodNutrients)),
+	}
+	for _, nutrient := range food.FoodNutrients {
+		response.Nutrients = append(response.Nutrients, SimplifiedNutrient{
+			Name:       nutrient.Nutrient.Name,
+			Unit:       nutrient.Nutrient.UnitName,
+			Amount:     nutrient.Amount * factor,
+			DataPoints: nutrient.DataPoints,
+		})
+	}
+
+	return response, nil
+}
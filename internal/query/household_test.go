@@ -0,0 +1,67 @@
+package query
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_NutrientsForHouseholdPortion(t *testing.T) {
+	testData := &FoundationFoodsData{
+		FoundationFoods: []FoundationFood{
+			{
+				Description: "Milk, whole, 3.25% milkfat",
+				FdcId:       1,
+				FoodNutrients: []FoodNutrient{
+					{Nutrient: Nutrient{Name: "Protein", UnitName: "g"}, Amount: 3.3},
+					{Nutrient: Nutrient{Name: "Calcium, Ca", UnitName: "mg"}, Amount: 113},
+				},
+				FoodPortions: []FoodPortion{
+					{Value: 1, MeasureUnit: MeasureUnit{Name: "cup", Abbreviation: "cup"}, GramWeight: 244, SequenceNumber: 1},
+					{Value: 1, MeasureUnit: MeasureUnit{Name: "tablespoon", Abbreviation: "tbsp"}, GramWeight: 15.2, SequenceNumber: 2},
+				},
+			},
+		},
+	}
+
+	engine := &Engine{
+		data:   testData,
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+
+	t.Run("scales every nutrient to two cups", func(t *testing.T) {
+		result, err := engine.NutrientsForHouseholdPortion(context.Background(), 1, "2 cups")
+
+		require.NoError(t, err)
+		assert.Equal(t, 2.0, result.Quantity)
+		assert.Equal(t, "cup", result.Unit)
+		assert.InDelta(t, 488, result.Grams, 0.0001)
+		require.Len(t, result.Nutrients, 2)
+		assert.InDelta(t, 16.104, result.Nutrients[0].Amount, 0.0001)
+		assert.InDelta(t, 551.44, result.Nutrients[1].Amount, 0.0001)
+	})
+
+	t.Run("accepts fractions and abbreviations", func(t *testing.T) {
+		result, err := engine.NutrientsForHouseholdPortion(context.Background(), 1, "1/2 tbsp")
+
+		require.NoError(t, err)
+		assert.InDelta(t, 7.6, result.Grams, 0.0001)
+	})
+
+	t.Run("lists alternatives for an unknown unit", func(t *testing.T) {
+		_, err := engine.NutrientsForHouseholdPortion(context.Background(), 1, "3 slices")
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "available units: cup, tablespoon")
+	})
+
+	t.Run("rejects a measure without a unit", func(t *testing.T) {
+		_, err := engine.NutrientsForHouseholdPortion(context.Background(), 1, "2")
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,149 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ingredientPosting records that a food lists an input food whose description contains an indexed word
+type ingredientPosting struct {
+	foodIndex  int
+	ingredient string
+}
+
+// ingredientIndexFor returns the ingredient word index, building it on first use.
+// The caller must hold the read lock.
+func (e *Engine) ingredientIndexFor() map[string][]ingredientPosting {
+	e.ingredientMu.Lock()
+	defer e.ingredientMu.Unlock()
+
+	if e.ingredientIndex == nil {
+		e.ingredientIndex = buildIngredientIndex(e.data.FoundationFoods)
+	}
+
+	return e.ingredientIndex
+}
+
+// buildIngredientIndex maps each normalized word of every input food description to the foods listing it
+func buildIngredientIndex(foods []FoundationFood) map[string][]ingredientPosting {
+	return buildIngredientIndexRange(foods, 0, len(foods))
+}
+
+// buildIngredientIndexRange indexes the input foods of foods[start:end], keeping postings keyed by their
+// position in the full slice
+func buildIngredientIndexRange(foods []FoundationFood, start, end int) map[string][]ingredientPosting {
+	index := make(map[string][]ingredientPosting)
+
+	for i := start; i < end; i++ {
+		for _, input := range foods[i].InputFoods {
+			for _, ingredient := range ingredientDescriptions(input) {
+				seen := make(map[string]bool)
+				for _, word := range strings.Fields(normalizeString(ingredient)) {
+					if seen[word] {
+						continue
+					}
+					seen[word] = true
+					index[word] = append(index[word], ingredientPosting{foodIndex: i, ingredient: ingredient})
+				}
+			}
+		}
+	}
+
+	return index
+}
+
+// ingredientDescriptions returns the distinct non-empty descriptions of an input food
+func ingredientDescriptions(input InputFood) []string {
+	var descriptions []string
+	for _, description := range []string{input.FoodDescription, input.InputFood.Description} {
+		description = strings.TrimSpace(description)
+		if description == "" {
+			continue
+		}
+		if len(descriptions) > 0 && strings.EqualFold(descriptions[0], description) {
+			continue
+		}
+		descriptions = append(descriptions, description)
+	}
+	return descriptions
+}
+
+// FindFoodsContainingIngredient returns foods whose input foods mention every word of the ingredient query
+func (e *Engine) FindFoodsContainingIngredient(ctx context.Context, ingredient string, limit int, opts SearchOptions) (*IngredientSearchResponse, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.data == nil {
+		return nil, fmt.Errorf("foundation Foods data not loaded")
+	}
+
+	queryWords := strings.Fields(normalizeString(ingredient))
+	if len(queryWords) == 0 {
+		return nil, fmt.Errorf("ingredient must contain at least one word")
+	}
+
+	index := e.ingredientIndexFor()
+
+	// Intersect the postings of every query word so multi-word ingredients must match as a whole
+	matches := make(map[ingredientPosting]int)
+	for _, word := range queryWords {
+		seen := make(map[ingredientPosting]bool)
+		for _, posting := range index[word] {
+			if seen[posting] {
+				continue
+			}
+			seen[posting] = true
+			matches[posting]++
+		}
+	}
+
+	// Keep the first matching ingredient of each food, in the order the food lists them
+	matchedIngredients := make(map[int]string)
+	for _, posting := range index[queryWords[0]] {
+		if matches[posting] != len(queryWords) {
+			continue
+		}
+		if _, ok := matchedIngredients[posting.foodIndex]; !ok {
+			matchedIngredients[posting.foodIndex] = posting.ingredient
+		}
+	}
+
+	foodIndexes := make([]int, 0, len(matchedIngredients))
+	for foodIndex := range matchedIngredients {
+		if !matchesCategory(e.data.FoundationFoods[foodIndex], opts.Category) {
+			continue
+		}
+		foodIndexes = append(foodIndexes, foodIndex)
+	}
+
+	// Dataset order keeps results stable between calls
+	sort.Ints(foodIndexes)
+
+	if limit > 0 && len(foodIndexes) > limit {
+		foodIndexes = foodIndexes[:limit]
+	}
+
+	response := &IngredientSearchResponse{
+		Ingredient: ingredient,
+		Foods:      make([]IngredientMatch, 0, len(foodIndexes)),
+	}
+	for _, foodIndex := range foodIndexes {
+		food := e.data.FoundationFoods[foodIndex]
+		response.Foods = append(response.Foods, IngredientMatch{
+			FdcId:             food.FdcId,
+			Description:       food.Description,
+			Category:          food.FoodCategory.Description,
+			MatchedIngredient: matchedIngredients[foodIndex],
+		})
+	}
+	response.Count = len(response.Foods)
+	response.Found = response.Count > 0
+
+	e.logger.DebugContext(ctx, "Ingredient search completed",
+		"ingredient", ingredient,
+		"results_found", response.Count)
+
+	return response, nil
+}
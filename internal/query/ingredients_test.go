@@ -0,0 +1,91 @@
+package query
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_FindFoodsContainingIngredient(t *testing.T) {
+	testData := &FoundationFoodsData{
+		FoundationFoods: []FoundationFood{
+			{
+				Description:  "Ketchup",
+				FdcId:        1,
+				FoodCategory: FoodCategory{Description: "Soups, Sauces, and Gravies"},
+				InputFoods: []InputFood{
+					{FoodDescription: "Tomato paste, canned", InputFood: InputFoodDetail{Description: "Tomato products, canned, paste"}},
+					{FoodDescription: "Vinegar, distilled"},
+				},
+			},
+			{
+				Description:  "Salsa, ready-to-serve",
+				FdcId:        2,
+				FoodCategory: FoodCategory{Description: "Vegetables and Vegetable Products"},
+				InputFoods: []InputFood{
+					{FoodDescription: "Onions, raw"},
+					{FoodDescription: "Tomatoes, red, ripe, raw"},
+				},
+			},
+			{
+				Description: "Tomatoes, grape, raw",
+				FdcId:       3,
+			},
+		},
+	}
+
+	engine := &Engine{
+		data:   testData,
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+
+	t.Run("returns foods with the matched ingredient", func(t *testing.T) {
+		result, err := engine.FindFoodsContainingIngredient(context.Background(), "tomato", 10, SearchOptions{})
+
+		require.NoError(t, err)
+		assert.True(t, result.Found)
+		require.Len(t, result.Foods, 1)
+		assert.Equal(t, 1, result.Foods[0].FdcId)
+		assert.Equal(t, "Ketchup", result.Foods[0].Description)
+		assert.Equal(t, "Tomato paste, canned", result.Foods[0].MatchedIngredient)
+	})
+
+	t.Run("matches nested input food descriptions", func(t *testing.T) {
+		result, err := engine.FindFoodsContainingIngredient(context.Background(), "tomato products", 10, SearchOptions{})
+
+		require.NoError(t, err)
+		require.Len(t, result.Foods, 1)
+		assert.Equal(t, "Tomato products, canned, paste", result.Foods[0].MatchedIngredient)
+	})
+
+	t.Run("requires every query word to match one ingredient", func(t *testing.T) {
+		result, err := engine.FindFoodsContainingIngredient(context.Background(), "onions ripe", 10, SearchOptions{})
+
+		require.NoError(t, err)
+		assert.False(t, result.Found)
+		assert.Empty(t, result.Foods)
+	})
+
+	t.Run("respects the category filter", func(t *testing.T) {
+		result, err := engine.FindFoodsContainingIngredient(context.Background(), "tomatoes", 10, SearchOptions{Category: "Vegetables and Vegetable Products"})
+
+		require.NoError(t, err)
+		require.Len(t, result.Foods, 1)
+		assert.Equal(t, 2, result.Foods[0].FdcId)
+
+		result, err = engine.FindFoodsContainingIngredient(context.Background(), "tomatoes", 10, SearchOptions{Category: "Dairy and Egg Products"})
+
+		require.NoError(t, err)
+		assert.False(t, result.Found)
+	})
+
+	t.Run("rejects an empty ingredient", func(t *testing.T) {
+		_, err := engine.FindFoodsContainingIngredient(context.Background(), "  ", 10, SearchOptions{})
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,100 @@
+package query
+
+import (
+	"context"
+	"fmt"
+)
+
+// MaxInputDepth caps how many levels of input foods GetFoodWithInputs expands
+const MaxInputDepth = 5
+
+// expandInputs appends the input foods of a food, each followed by its own inputs until depth runs out.
+// path holds the FDC IDs being expanded so a food listing one of its ancestors doesn't recurse forever.
+// The caller must hold the read lock.
+func (e *Engine) expandInputs(inputs []ExpandedInputFood, food *FoundationFood, level, depth int, path map[int]bool) []ExpandedInputFood {
+	for _, input := range food.InputFoods {
+		expanded := ExpandedInputFood{
+			FdcId:       input.InputFood.FdcId,
+			Description: inputFoodDescription(input),
+			ParentFdcId: food.FdcId,
+			Level:       level,
+		}
+
+		// Inputs absent from the dataset are returned as stubs
+		resolved, err := e.getFoodByFdcId(input.InputFood.FdcId)
+		if input.InputFood.FdcId == 0 || err != nil {
+			inputs = append(inputs, expanded)
+			continue
+		}
+
+		expanded.Found = true
+		expanded.Food = resolved
+		inputs = append(inputs, expanded)
+
+		if level < depth && !path[resolved.FdcId] {
+			path[resolved.FdcId] = true
+			inputs = e.expandInputs(inputs, resolved, level+1, depth, path)
+			delete(path, resolved.FdcId)
+		}
+	}
+
+	return inputs
+}
+
+// GetFoodWithInputs returns a food together with the full records of its input foods, expanded
+// recursively up to depth levels. Inputs not present in the dataset are returned as stubs.
+func (e *Engine) GetFoodWithInputs(ctx context.Context, fdcId int, depth int) (*FoodWithInputsResponse, error) {
+	if depth < 1 || depth > MaxInputDepth {
+		return nil, fmt.Errorf("depth must be between 1 and %d", MaxInputDepth)
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	food, err := e.getFoodByFdcId(fdcId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FoodWithInputsResponse{
+		Food:   *food,
+		Depth:  depth,
+		Inputs: e.expandInputs([]ExpandedInputFood{}, food, 1, depth, map[int]bool{food.FdcId: true}),
+	}, nil
+}
+
+// inputFoodDescription returns the description a food gives an input, falling back to the input's own description
+func inputFoodDescription(input InputFood) string {
+	if input.FoodDescription != "" {
+		return input.FoodDescription
+	}
+	return input.InputFood.Description
+}
+
+// GetFoodInputFoods returns the FDC IDs and descriptions of a food's direct input foods, so callers can walk
+// from a composite food to its components without fetching every input's full record. Foods without input
+// foods return an empty list.
+func (e *Engine) GetFoodInputFoods(ctx context.Context, fdcId int) (*FoodInputFoodsResponse, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	food, err := e.getFoodByFdcId(fdcId)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &FoodInputFoodsResponse{
+		FdcId:       food.FdcId,
+		Description: food.Description,
+		InputFoods:  make([]InputFoodRef, 0, len(food.InputFoods)),
+	}
+	for _, input := range food.InputFoods {
+		response.InputFoods = append(response.InputFoods, InputFoodRef{
+			FdcId:       input.InputFood.FdcId,
+			Description: inputFoodDescription(input),
+		})
+	}
+	response.Count = len(response.InputFoods)
+
+	return response, nil
+}
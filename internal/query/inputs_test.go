@@ -0,0 +1,131 @@
+package query
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_GetFoodWithInputs(t *testing.T) {
+	input := func(fdcId int, description string) InputFood {
+		return InputFood{FoodDescription: description, InputFood: InputFoodDetail{FdcId: fdcId, Description: description}}
+	}
+
+	testData := &FoundationFoodsData{
+		FoundationFoods: []FoundationFood{
+			{
+				Description: "Hummus, commercial",
+				FdcId:       1,
+				InputFoods:  []InputFood{input(2, "Chickpeas, canned"), input(3, "Tahini"), input(99, "Lemon juice")},
+			},
+			{
+				Description: "Chickpeas, canned",
+				FdcId:       2,
+				InputFoods:  []InputFood{input(4, "Chickpeas, dry")},
+			},
+			{Description: "Tahini", FdcId: 3},
+			{
+				Description: "Chickpeas, dry",
+				FdcId:       4,
+				// A self-reference must not expand forever
+				InputFoods: []InputFood{input(1, "Hummus, commercial")},
+			},
+		},
+	}
+
+	engine := &Engine{
+		data:   testData,
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+
+	t.Run("resolves direct inputs and stubs missing ones", func(t *testing.T) {
+		result, err := engine.GetFoodWithInputs(context.Background(), 1, 1)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Hummus, commercial", result.Food.Description)
+		require.Len(t, result.Inputs, 3)
+
+		assert.True(t, result.Inputs[0].Found)
+		require.NotNil(t, result.Inputs[0].Food)
+		assert.Equal(t, 2, result.Inputs[0].Food.FdcId)
+		assert.Equal(t, 1, result.Inputs[0].ParentFdcId)
+		assert.True(t, result.Inputs[1].Found)
+
+		assert.False(t, result.Inputs[2].Found)
+		assert.Nil(t, result.Inputs[2].Food)
+		assert.Equal(t, "Lemon juice", result.Inputs[2].Description)
+	})
+
+	t.Run("expands inputs of inputs up to the depth", func(t *testing.T) {
+		result, err := engine.GetFoodWithInputs(context.Background(), 1, 3)
+
+		require.NoError(t, err)
+
+		var fdcIds, levels []int
+		for _, expanded := range result.Inputs {
+			fdcIds = append(fdcIds, expanded.FdcId)
+			levels = append(levels, expanded.Level)
+		}
+		assert.Equal(t, []int{2, 4, 1, 3, 99}, fdcIds)
+		assert.Equal(t, []int{1, 2, 3, 1, 1}, levels)
+		assert.Equal(t, 2, result.Inputs[1].ParentFdcId)
+	})
+
+	t.Run("rejects an out of range depth", func(t *testing.T) {
+		_, err := engine.GetFoodWithInputs(context.Background(), 1, 0)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestEngine_GetFoodInputFoods(t *testing.T) {
+	engine := &Engine{
+		data: &FoundationFoodsData{
+			FoundationFoods: []FoundationFood{
+				{
+					Description: "Hummus, commercial",
+					FdcId:       1,
+					InputFoods: []InputFood{
+						{FoodDescription: "Chickpeas, canned", InputFood: InputFoodDetail{FdcId: 2, Description: "Chickpeas, mature seeds, canned"}},
+						// The input's own description is used when the food doesn't name it
+						{InputFood: InputFoodDetail{FdcId: 3, Description: "Tahini"}},
+					},
+				},
+				{Description: "Tahini", FdcId: 3},
+			},
+		},
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+
+	t.Run("lists the direct input foods", func(t *testing.T) {
+		result, err := engine.GetFoodInputFoods(context.Background(), 1)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.FdcId)
+		assert.Equal(t, "Hummus, commercial", result.Description)
+		assert.Equal(t, 2, result.Count)
+		assert.Equal(t, []InputFoodRef{
+			{FdcId: 2, Description: "Chickpeas, canned"},
+			{FdcId: 3, Description: "Tahini"},
+		}, result.InputFoods)
+	})
+
+	t.Run("returns an empty list for foods without input foods", func(t *testing.T) {
+		result, err := engine.GetFoodInputFoods(context.Background(), 3)
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, result.Count)
+		assert.NotNil(t, result.InputFoods)
+		assert.Empty(t, result.InputFoods)
+	})
+
+	t.Run("returns an error for unknown foods", func(t *testing.T) {
+		_, err := engine.GetFoodInputFoods(context.Background(), 99)
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,52 @@
+package query
+
+// SearchLimits bounds how many foods a name search returns: Default when the caller gives no limit, and at
+// most Max
+type SearchLimits struct {
+	Default int
+	Max     int
+}
+
+// DefaultSearchLimits are the search limits used unless WithSearchLimits overrides them
+var DefaultSearchLimits = SearchLimits{Default: 3, Max: 10}
+
+// MaxSearchLimit is the hard ceiling on SearchLimits.Max, so a misconfigured maximum can't make one search
+// materialize the whole dataset
+const MaxSearchLimit = 100
+
+// NewSearchLimits builds search limits, keeping the built-in default or maximum for non-positive values,
+// capping the maximum at MaxSearchLimit and lowering the default to the maximum when it is larger
+func NewSearchLimits(defaultLimit, maxLimit int) SearchLimits {
+	limits := DefaultSearchLimits
+	if maxLimit > 0 {
+		limits.Max = min(maxLimit, MaxSearchLimit)
+	}
+	if defaultLimit > 0 {
+		limits.Default = defaultLimit
+	}
+	limits.Default = min(limits.Default, limits.Max)
+	return limits
+}
+
+// Clamp returns limit capped at Max, or Default when limit isn't positive
+func (l SearchLimits) Clamp(limit int) int {
+	if limit <= 0 {
+		return l.Default
+	}
+	return min(limit, l.Max)
+}
+
+// WithSearchLimits sets the default and maximum number of foods a name search returns
+func WithSearchLimits(limits SearchLimits) EngineOption {
+	return func(e *Engine) {
+		e.limits = &limits
+	}
+}
+
+// searchLimits returns the engine's search limits, falling back to the defaults
+func (e *Engine) searchLimits() SearchLimits {
+	if e.limits == nil {
+		return DefaultSearchLimits
+	}
+	return *e.limits
+}
@@ -0,0 +1,79 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSearchLimits(t *testing.T) {
+	assert.Equal(t, SearchLimits{Default: 3, Max: 10}, NewSearchLimits(0, 0))
+	assert.Equal(t, SearchLimits{Default: 5, Max: 25}, NewSearchLimits(5, 25))
+	// A default above the maximum is lowered to it
+	assert.Equal(t, SearchLimits{Default: 2, Max: 2}, NewSearchLimits(0, 2))
+	assert.Equal(t, SearchLimits{Default: 10, Max: 10}, NewSearchLimits(20, -1))
+	// A huge maximum is still bounded by the hard ceiling
+	assert.Equal(t, SearchLimits{Default: 3, Max: MaxSearchLimit}, NewSearchLimits(0, 1_000_000))
+	assert.Equal(t, SearchLimits{Default: MaxSearchLimit, Max: MaxSearchLimit}, NewSearchLimits(1_000_000, 1_000_000))
+}
+
+func TestSearchLimits_Clamp(t *testing.T) {
+	limits := SearchLimits{Default: 3, Max: 10}
+
+	assert.Equal(t, 3, limits.Clamp(0))
+	assert.Equal(t, 3, limits.Clamp(-5))
+	assert.Equal(t, 7, limits.Clamp(7))
+	assert.Equal(t, 10, limits.Clamp(11))
+}
+
+func TestEngine_SearchFoodsByName_Limits(t *testing.T) {
+	foods := make([]FoundationFood, 0, 2*MaxSearchLimit)
+	for i := range 2 * MaxSearchLimit {
+		foods = append(foods, FoundationFood{Description: fmt.Sprintf("Milk %d", i), FdcId: i + 1})
+	}
+	newEngine := func(opts ...EngineOption) *Engine {
+		engine := &Engine{
+			data:   &FoundationFoodsData{FoundationFoods: foods},
+			logger: config.NewTestLogger(io.Discard, "debug"),
+		}
+		for _, opt := range opts {
+			opt(engine)
+		}
+		return engine
+	}
+	count := func(t *testing.T, engine *Engine, limit int) int {
+		t.Helper()
+		results, err := engine.SearchFoodsByName(context.Background(), "milk", limit, SearchOptions{})
+		require.NoError(t, err)
+		return len(results)
+	}
+
+	t.Run("uses the built-in limits by default", func(t *testing.T) {
+		engine := newEngine()
+
+		assert.Equal(t, 3, count(t, engine, 0))
+		assert.Equal(t, 10, count(t, engine, 25))
+	})
+
+	t.Run("uses configured limits", func(t *testing.T) {
+		engine := newEngine(WithSearchLimits(NewSearchLimits(4, 20)))
+
+		assert.Equal(t, 4, count(t, engine, 0))
+		assert.Equal(t, 20, count(t, engine, 25))
+	})
+
+	t.Run("bounds a huge configured maximum", func(t *testing.T) {
+		engine := newEngine(WithSearchLimits(NewSearchLimits(0, 1_000_000)))
+
+		assert.Equal(t, MaxSearchLimit, count(t, engine, 1_000_000))
+
+		simplified, err := engine.SearchFoodsByNameSimplified(context.Background(), "milk", 1_000_000, nil, SearchOptions{})
+		require.NoError(t, err)
+		assert.Len(t, simplified.Foods, MaxSearchLimit)
+	})
+}
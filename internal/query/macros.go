@@ -0,0 +1,68 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Atwater general factors, in kcal per gram
+const (
+	proteinKcalPerGram = 4.0
+	carbKcalPerGram    = 4.0
+	fatKcalPerGram     = 9.0
+)
+
+// MacroPercentages returns the share of calories from protein, fat and carbohydrate for the best match
+// for a name, using the 4/9/4 kcal per gram factors. Missing macros count as zero and are listed in
+// MissingMacros.
+func (e *Engine) MacroPercentages(ctx context.Context, name string, opts SearchOptions) (*MacroPercentagesResponse, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("name must not be empty")
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.data == nil {
+		return nil, fmt.Errorf("foundation Foods data not loaded")
+	}
+
+	results := e.scoreFoods(ctx, name, opts)
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no food matches %q", name)
+	}
+	food := results[0].Food
+
+	response := &MacroPercentagesResponse{
+		FdcId:         food.FdcId,
+		Description:   food.Description,
+		MissingMacros: []string{},
+	}
+
+	macro := func(label string, names ...string) float64 {
+		grams, ok := nutrientGrams(&food, names...)
+		if !ok {
+			response.MissingMacros = append(response.MissingMacros, label)
+		}
+		return grams
+	}
+	response.ProteinGrams = macro("protein", "Protein")
+	response.FatGrams = macro("fat", "Total lipid (fat)")
+	response.CarbGrams = macro("carbohydrate", "Carbohydrate, by difference", "Carbohydrate, by summation")
+
+	proteinKcal := response.ProteinGrams * proteinKcalPerGram
+	fatKcal := response.FatGrams * fatKcalPerGram
+	carbKcal := response.CarbGrams * carbKcalPerGram
+	response.MacroKcal = proteinKcal + fatKcal + carbKcal
+
+	if response.MacroKcal <= 0 {
+		return nil, fmt.Errorf("food %q has no calories from protein, fat or carbohydrate", food.Description)
+	}
+
+	response.ProteinPercent = proteinKcal / response.MacroKcal * 100
+	response.FatPercent = fatKcal / response.MacroKcal * 100
+	response.CarbPercent = carbKcal / response.MacroKcal * 100
+
+	return response, nil
+}
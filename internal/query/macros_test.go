@@ -0,0 +1,67 @@
+package query
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_MacroPercentages(t *testing.T) {
+	testData := &FoundationFoodsData{
+		FoundationFoods: []FoundationFood{
+			{
+				Description: "Milk, whole",
+				FdcId:       1,
+				FoodNutrients: []FoodNutrient{
+					{Nutrient: Nutrient{Name: "Protein", UnitName: "g"}, Amount: 3.3},
+					{Nutrient: Nutrient{Name: "Total lipid (fat)", UnitName: "g"}, Amount: 3.2},
+					{Nutrient: Nutrient{Name: "Carbohydrate, by difference", UnitName: "g"}, Amount: 4.6},
+				},
+			},
+			{
+				Description: "Oil, olive",
+				FdcId:       2,
+				FoodNutrients: []FoodNutrient{
+					{Nutrient: Nutrient{Name: "Total lipid (fat)", UnitName: "g"}, Amount: 100},
+				},
+			},
+			{Description: "Water, tap", FdcId: 3},
+		},
+	}
+
+	engine := &Engine{
+		data:   testData,
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+
+	t.Run("splits calories by macro", func(t *testing.T) {
+		result, err := engine.MacroPercentages(context.Background(), "milk", SearchOptions{})
+
+		require.NoError(t, err)
+		// 13.2 + 28.8 + 18.4 = 60.4 kcal
+		assert.InDelta(t, 60.4, result.MacroKcal, 0.0001)
+		assert.InDelta(t, 21.854, result.ProteinPercent, 0.001)
+		assert.InDelta(t, 47.682, result.FatPercent, 0.001)
+		assert.InDelta(t, 30.464, result.CarbPercent, 0.001)
+		assert.InDelta(t, 100, result.ProteinPercent+result.FatPercent+result.CarbPercent, 0.0001)
+		assert.Empty(t, result.MissingMacros)
+	})
+
+	t.Run("flags missing macros as zero", func(t *testing.T) {
+		result, err := engine.MacroPercentages(context.Background(), "olive oil", SearchOptions{})
+
+		require.NoError(t, err)
+		assert.InDelta(t, 100, result.FatPercent, 0.0001)
+		assert.Equal(t, []string{"protein", "carbohydrate"}, result.MissingMacros)
+	})
+
+	t.Run("errors without any macro calories", func(t *testing.T) {
+		_, err := engine.MacroPercentages(context.Background(), "water", SearchOptions{})
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,97 @@
+package query
+
+import "fmt"
+
+// Strategies for collapsing duplicate nutrient entries
+const (
+	// MergeMostDataPoints keeps the duplicate entry backed by the most data points
+	MergeMostDataPoints = "most_data_points"
+
+	// MergeAverage averages the amounts of the duplicate entries and sums their data points
+	MergeAverage = "average"
+)
+
+// ValidateMergeStrategy reports an error for unknown merge strategies. An empty strategy means most_data_points.
+func ValidateMergeStrategy(strategy string) error {
+	switch strategy {
+	case "", MergeMostDataPoints, MergeAverage:
+		return nil
+	default:
+		return fmt.Errorf("unknown merge strategy %q, expected %q or %q", strategy, MergeMostDataPoints, MergeAverage)
+	}
+}
+
+// mergeDuplicateNutrients collapses nutrients sharing a name and unit into one entry, keeping the
+// position of the first occurrence
+func mergeDuplicateNutrients(nutrients []SimplifiedNutrient, strategy string) []SimplifiedNutrient {
+	merged := make([]SimplifiedNutrient, 0, len(nutrients))
+	groups := make(map[string][]SimplifiedNutrient)
+	var order []string
+
+	for _, nutrient := range nutrients {
+		key := nutrientKey(nutrient.Name, nutrient.Unit)
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], nutrient)
+	}
+
+	for _, key := range order {
+		group := groups[key]
+		if len(group) == 1 {
+			merged = append(merged, group[0])
+			continue
+		}
+
+		if strategy == MergeAverage {
+			merged = append(merged, averageNutrients(group))
+			continue
+		}
+
+		best := group[0]
+		for _, nutrient := range group[1:] {
+			if nutrient.DataPoints > best.DataPoints {
+				best = nutrient
+			}
+		}
+		merged = append(merged, best)
+	}
+
+	return merged
+}
+
+// averageNutrients averages the amounts (and reference ratios, when every entry has one) of duplicate entries
+func averageNutrients(group []SimplifiedNutrient) SimplifiedNutrient {
+	averaged := group[0]
+	averaged.Amount = 0
+	averaged.DataPoints = 0
+
+	var ratioSum float64
+	allHaveRatio := true
+	for _, nutrient := range group {
+		averaged.Amount += nutrient.Amount
+		averaged.DataPoints += nutrient.DataPoints
+		if nutrient.RelativeToReference == nil {
+			allHaveRatio = false
+		} else {
+			ratioSum += *nutrient.RelativeToReference
+		}
+	}
+	averaged.Amount /= float64(len(group))
+
+	averaged.RelativeToReference = nil
+	if allHaveRatio {
+		ratio := ratioSum / float64(len(group))
+		averaged.RelativeToReference = &ratio
+	}
+
+	// An average of differently derived amounts has no single derivation
+	for _, nutrient := range group[1:] {
+		if !sameDerivation(nutrient.Derivation, averaged.Derivation) {
+			averaged.Derivation = nil
+			break
+		}
+	}
+
+	return averaged
+}
@@ -0,0 +1,66 @@
+package query
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_SearchFoodsByNameSimplified_MergeDuplicateNutrients(t *testing.T) {
+	testData := &FoundationFoodsData{
+		FoundationFoods: []FoundationFood{
+			{
+				Description: "Almonds, raw",
+				FdcId:       1,
+				FoodNutrients: []FoodNutrient{
+					{Nutrient: Nutrient{Name: "Fiber, total dietary", UnitName: "g"}, Amount: 10, DataPoints: 2},
+					{Nutrient: Nutrient{Name: "Protein", UnitName: "g"}, Amount: 21.2, DataPoints: 6},
+					{Nutrient: Nutrient{Name: "Fiber, total dietary", UnitName: "g"}, Amount: 12, DataPoints: 8},
+				},
+			},
+		},
+	}
+
+	engine := &Engine{
+		data:   testData,
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+
+	search := func(t *testing.T, opts SearchOptions) []SimplifiedNutrient {
+		result, err := engine.SearchFoodsByNameSimplified(context.Background(), "almonds", 1, nil, opts)
+		require.NoError(t, err)
+		require.Len(t, result.Foods, 1)
+		return result.Foods[0].Nutrients
+	}
+
+	t.Run("keeps duplicates by default", func(t *testing.T) {
+		assert.Len(t, search(t, SearchOptions{}), 3)
+	})
+
+	t.Run("keeps the entry with the most data points", func(t *testing.T) {
+		nutrients := search(t, SearchOptions{MergeDuplicateNutrients: true})
+
+		require.Len(t, nutrients, 2)
+		assert.Equal(t, "Fiber, total dietary", nutrients[0].Name)
+		assert.Equal(t, 12.0, nutrients[0].Amount)
+		assert.Equal(t, 8, nutrients[0].DataPoints)
+		assert.Equal(t, "Protein", nutrients[1].Name)
+	})
+
+	t.Run("averages duplicate entries", func(t *testing.T) {
+		nutrients := search(t, SearchOptions{MergeDuplicateNutrients: true, MergeStrategy: MergeAverage})
+
+		require.Len(t, nutrients, 2)
+		assert.Equal(t, 11.0, nutrients[0].Amount)
+		assert.Equal(t, 10, nutrients[0].DataPoints)
+	})
+
+	t.Run("rejects unknown strategies", func(t *testing.T) {
+		assert.NoError(t, ValidateMergeStrategy(""))
+		assert.Error(t, ValidateMergeStrategy("median"))
+	})
+}
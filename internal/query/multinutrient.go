@@ -0,0 +1,97 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MultiNutrientSources returns the foods ranking at or above percentile (0-100, 0 uses
+// DefaultNotablePercentile) across the dataset for every named nutrient, ordered by their mean
+// percentile rank with ties broken by description. Foods lacking any of the nutrients are excluded.
+func (e *Engine) MultiNutrientSources(ctx context.Context, nutrientNames []string, percentile float64, limit int, opts SearchOptions) (*MultiNutrientSourcesResponse, error) {
+	var names []string
+	for _, name := range nutrientNames {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("at least one nutrient name is required")
+	}
+	if percentile <= 0 {
+		percentile = DefaultNotablePercentile
+	}
+	if percentile > 100 {
+		return nil, fmt.Errorf("percentile %g must be between 0 and 100", percentile)
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.data == nil {
+		return nil, fmt.Errorf("foundation Foods data not loaded")
+	}
+
+	var foods []MultiNutrientFood
+	for _, food := range e.data.FoundationFoods {
+		if !matchesCategory(food, opts.Category) {
+			continue
+		}
+
+		ranks := make([]NutrientPercentile, 0, len(names))
+		var total float64
+		for _, name := range names {
+			nutrient, ok := e.matchNutrient(&food, name)
+			if !ok {
+				break
+			}
+
+			rank := e.percentileRank(nutrient.Nutrient.Name, nutrient.Nutrient.UnitName, nutrient.Amount)
+			if rank < percentile {
+				break
+			}
+
+			ranks = append(ranks, NutrientPercentile{
+				Name:       nutrient.Nutrient.Name,
+				Amount:     nutrient.Amount,
+				Unit:       nutrient.Nutrient.UnitName,
+				Percentile: rank,
+			})
+			total += rank
+		}
+		if len(ranks) < len(names) {
+			continue
+		}
+
+		foods = append(foods, MultiNutrientFood{
+			FdcId:       food.FdcId,
+			Description: food.Description,
+			Category:    food.FoodCategory.Description,
+			Score:       total / float64(len(names)),
+			Nutrients:   ranks,
+		})
+	}
+
+	sort.Slice(foods, func(i, j int) bool {
+		if foods[i].Score != foods[j].Score {
+			return foods[i].Score > foods[j].Score
+		}
+		return foods[i].Description < foods[j].Description
+	})
+
+	if limit > 0 && len(foods) > limit {
+		foods = foods[:limit]
+	}
+	if foods == nil {
+		foods = []MultiNutrientFood{}
+	}
+
+	return &MultiNutrientSourcesResponse{
+		Nutrients:  names,
+		Percentile: percentile,
+		Count:      len(foods),
+		Foods:      foods,
+	}, nil
+}
@@ -0,0 +1,72 @@
+package query
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_MultiNutrientSources(t *testing.T) {
+	ironAndVitaminC := func(iron, vitaminC float64) []FoodNutrient {
+		return []FoodNutrient{
+			{Nutrient: Nutrient{Name: "Iron, Fe", UnitName: "mg"}, Amount: iron},
+			{Nutrient: Nutrient{Name: "Vitamin C, total ascorbic acid", UnitName: "mg"}, Amount: vitaminC},
+		}
+	}
+
+	testData := &FoundationFoodsData{
+		FoundationFoods: []FoundationFood{
+			{Description: "Beef, liver, raw", FdcId: 1, FoodNutrients: ironAndVitaminC(10, 1)},
+			{Description: "Oranges, raw", FdcId: 2, FoodNutrients: ironAndVitaminC(1, 100)},
+			{Description: "Spinach, raw", FdcId: 3, FoodNutrients: ironAndVitaminC(9, 90)},
+			{Description: "Rice, white, cooked", FdcId: 4, FoodNutrients: ironAndVitaminC(0.5, 2)},
+			{Description: "Milk, whole", FdcId: 5, FoodNutrients: ironAndVitaminC(0.2, 3)},
+			{Description: "Oil, olive", FdcId: 6},
+		},
+	}
+
+	engine := &Engine{
+		data:   testData,
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+	ctx := context.Background()
+
+	t.Run("returns only foods high in every nutrient", func(t *testing.T) {
+		result, err := engine.MultiNutrientSources(ctx, []string{"Iron, Fe", "Vitamin C"}, 75, 10, SearchOptions{})
+
+		require.NoError(t, err)
+		require.Len(t, result.Foods, 1)
+		food := result.Foods[0]
+		assert.Equal(t, 3, food.FdcId)
+		assert.InDelta(t, 80, food.Score, 0.0001)
+		require.Len(t, food.Nutrients, 2)
+		assert.Equal(t, "Iron, Fe", food.Nutrients[0].Name)
+		assert.Equal(t, "Vitamin C, total ascorbic acid", food.Nutrients[1].Name)
+		assert.Equal(t, 90.0, food.Nutrients[1].Amount)
+	})
+
+	t.Run("orders by combined score", func(t *testing.T) {
+		result, err := engine.MultiNutrientSources(ctx, []string{"Iron, Fe"}, 75, 10, SearchOptions{})
+
+		require.NoError(t, err)
+		require.Len(t, result.Foods, 2)
+		assert.Equal(t, 1, result.Foods[0].FdcId)
+		assert.Equal(t, 3, result.Foods[1].FdcId)
+	})
+
+	t.Run("defaults the percentile", func(t *testing.T) {
+		result, err := engine.MultiNutrientSources(ctx, []string{"Iron, Fe"}, 0, 10, SearchOptions{})
+
+		require.NoError(t, err)
+		assert.Equal(t, DefaultNotablePercentile, result.Percentile)
+	})
+
+	t.Run("rejects an empty nutrient list", func(t *testing.T) {
+		_, err := engine.MultiNutrientSources(ctx, []string{" "}, 75, 10, SearchOptions{})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,39 @@
+package query
+
+import (
+	"context"
+	"fmt"
+)
+
+// GetFoodByNdbNumber returns every food with a legacy NDB number. Unlike FDC IDs, NDB numbers aren't
+// guaranteed to be unique, so all matches are returned in dataset order.
+func (e *Engine) GetFoodByNdbNumber(ctx context.Context, ndbNumber int) (*NdbNumberLookupResponse, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.data == nil {
+		return nil, fmt.Errorf("foundation Foods data not loaded")
+	}
+
+	// Foods without an NDB number decode as 0, so only positive numbers identify a food
+	if ndbNumber <= 0 {
+		return nil, fmt.Errorf("NDB number must be positive, got %d", ndbNumber)
+	}
+
+	response := &NdbNumberLookupResponse{
+		NdbNumber: ndbNumber,
+		Foods:     []FoundationFood{},
+	}
+	for _, food := range e.data.FoundationFoods {
+		if food.NdbNumber == ndbNumber {
+			response.Foods = append(response.Foods, food)
+		}
+	}
+
+	if len(response.Foods) == 0 {
+		return nil, fmt.Errorf("food with NDB number %d not found", ndbNumber)
+	}
+	response.Count = len(response.Foods)
+
+	return response, nil
+}
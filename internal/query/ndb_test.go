@@ -0,0 +1,64 @@
+package query
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_GetFoodByNdbNumber(t *testing.T) {
+	engine := &Engine{
+		data: &FoundationFoodsData{
+			FoundationFoods: []FoundationFood{
+				{Description: "Milk, whole", FdcId: 1, NdbNumber: 1077},
+				{Description: "Cheese, cheddar", FdcId: 2, NdbNumber: 1009},
+				{Description: "Milk, whole, fortified", FdcId: 3, NdbNumber: 1077},
+				{Description: "Broccoli, raw", FdcId: 4},
+			},
+		},
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+
+	t.Run("returns the food with the number", func(t *testing.T) {
+		response, err := engine.GetFoodByNdbNumber(context.Background(), 1009)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1009, response.NdbNumber)
+		assert.Equal(t, 1, response.Count)
+		require.Len(t, response.Foods, 1)
+		assert.Equal(t, 2, response.Foods[0].FdcId)
+	})
+
+	t.Run("returns every food sharing a number", func(t *testing.T) {
+		response, err := engine.GetFoodByNdbNumber(context.Background(), 1077)
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, response.Count)
+		require.Len(t, response.Foods, 2)
+		assert.Equal(t, 1, response.Foods[0].FdcId)
+		assert.Equal(t, 3, response.Foods[1].FdcId)
+	})
+
+	t.Run("returns an error when no food has the number", func(t *testing.T) {
+		_, err := engine.GetFoodByNdbNumber(context.Background(), 9999)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+
+	t.Run("rejects numbers that would match foods without one", func(t *testing.T) {
+		_, err := engine.GetFoodByNdbNumber(context.Background(), 0)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("returns an error when no data is loaded", func(t *testing.T) {
+		_, err := (&Engine{logger: config.NewTestLogger(io.Discard, "debug")}).GetFoodByNdbNumber(context.Background(), 1077)
+
+		assert.Error(t, err)
+	})
+}
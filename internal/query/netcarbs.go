@@ -0,0 +1,96 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// nutrientGrams returns the amount in grams of the first of the named nutrients the food reports
+func nutrientGrams(food *FoundationFood, names ...string) (float64, bool) {
+	for _, name := range names {
+		nutrient, ok := findNutrient(food, name)
+		if !ok {
+			continue
+		}
+		if amount, unit := normalizeNutrientUnit(nutrient.Amount, nutrient.Nutrient.UnitName); unit == "g" {
+			return amount, true
+		}
+	}
+	return 0, false
+}
+
+// sugarAlcoholGrams sums every nutrient reported as a sugar alcohol, in grams
+func sugarAlcoholGrams(food *FoundationFood) (float64, bool) {
+	var total float64
+	var found bool
+	for _, nutrient := range food.FoodNutrients {
+		if !strings.Contains(strings.ToLower(nutrient.Nutrient.Name), "sugar alcohol") {
+			continue
+		}
+		amount, unit := normalizeNutrientUnit(nutrient.Amount, nutrient.Nutrient.UnitName)
+		if unit != "g" {
+			continue
+		}
+		total += amount
+		found = true
+	}
+	return total, found
+}
+
+// NetCarbs returns a food's total carbohydrate minus dietary fiber, and optionally sugar alcohols,
+// scaled to a household measure or to 100 g when the measure is empty. Foods without a fiber value
+// report net carbs equal to total carbs with FiberAssumedZero set.
+func (e *Engine) NetCarbs(ctx context.Context, fdcId int, measure string, subtractSugarAlcohols bool) (*NetCarbsResponse, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	food, err := e.getFoodByFdcId(fdcId)
+	if err != nil {
+		return nil, err
+	}
+
+	grams := 100.0
+	if strings.TrimSpace(measure) != "" {
+		grams, _, _, err = resolveHouseholdGrams(food, measure)
+		if err != nil {
+			return nil, err
+		}
+	}
+	factor := grams / 100
+
+	carbs, ok := nutrientGrams(food, "Carbohydrate, by difference", "Carbohydrate, by summation")
+	if !ok {
+		return nil, fmt.Errorf("food %q has no carbohydrate value", food.Description)
+	}
+
+	response := &NetCarbsResponse{
+		FdcId:       food.FdcId,
+		Description: food.Description,
+		Measure:     measure,
+		Grams:       grams,
+		TotalCarbs:  carbs * factor,
+	}
+
+	fiber, ok := nutrientGrams(food, "Fiber, total dietary")
+	if ok {
+		response.Fiber = fiber * factor
+	} else {
+		response.FiberAssumedZero = true
+	}
+
+	net := response.TotalCarbs - response.Fiber
+	if subtractSugarAlcohols {
+		if alcohols, ok := sugarAlcoholGrams(food); ok {
+			scaled := alcohols * factor
+			response.SugarAlcohols = &scaled
+			net -= scaled
+		}
+	}
+	if net < 0 {
+		net = 0
+	}
+	response.NetCarbs = net
+
+	return response, nil
+}
@@ -0,0 +1,92 @@
+package query
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_NetCarbs(t *testing.T) {
+	testData := &FoundationFoodsData{
+		FoundationFoods: []FoundationFood{
+			{
+				Description: "Seeds, chia seeds, dried",
+				FdcId:       1,
+				FoodNutrients: []FoodNutrient{
+					{Nutrient: Nutrient{Name: "Carbohydrate, by difference", UnitName: "g"}, Amount: 42.1},
+					{Nutrient: Nutrient{Name: "Fiber, total dietary", UnitName: "g"}, Amount: 34.4},
+				},
+				FoodPortions: []FoodPortion{
+					{Value: 1, MeasureUnit: MeasureUnit{Name: "tablespoon", Abbreviation: "tbsp"}, GramWeight: 12, SequenceNumber: 1},
+				},
+			},
+			{
+				Description: "Candy, sugar-free",
+				FdcId:       2,
+				FoodNutrients: []FoodNutrient{
+					{Nutrient: Nutrient{Name: "Carbohydrate, by difference", UnitName: "g"}, Amount: 90},
+					{Nutrient: Nutrient{Name: "Sugar alcohols", UnitName: "g"}, Amount: 60},
+				},
+			},
+			{
+				Description: "Oil, olive",
+				FdcId:       3,
+				FoodNutrients: []FoodNutrient{
+					{Nutrient: Nutrient{Name: "Total lipid (fat)", UnitName: "g"}, Amount: 100},
+				},
+			},
+		},
+	}
+
+	engine := &Engine{
+		data:   testData,
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+
+	t.Run("subtracts fiber per 100 g", func(t *testing.T) {
+		result, err := engine.NetCarbs(context.Background(), 1, "", true)
+
+		require.NoError(t, err)
+		assert.Equal(t, 100.0, result.Grams)
+		assert.InDelta(t, 42.1, result.TotalCarbs, 0.0001)
+		assert.InDelta(t, 34.4, result.Fiber, 0.0001)
+		assert.InDelta(t, 7.7, result.NetCarbs, 0.0001)
+		assert.False(t, result.FiberAssumedZero)
+		assert.Nil(t, result.SugarAlcohols)
+	})
+
+	t.Run("scales to a household measure", func(t *testing.T) {
+		result, err := engine.NetCarbs(context.Background(), 1, "2 tbsp", true)
+
+		require.NoError(t, err)
+		assert.InDelta(t, 24, result.Grams, 0.0001)
+		assert.InDelta(t, 1.848, result.NetCarbs, 0.0001)
+	})
+
+	t.Run("flags missing fiber and subtracts sugar alcohols", func(t *testing.T) {
+		result, err := engine.NetCarbs(context.Background(), 2, "", true)
+
+		require.NoError(t, err)
+		assert.True(t, result.FiberAssumedZero)
+		require.NotNil(t, result.SugarAlcohols)
+		assert.InDelta(t, 60, *result.SugarAlcohols, 0.0001)
+		assert.InDelta(t, 30, result.NetCarbs, 0.0001)
+	})
+
+	t.Run("keeps sugar alcohols when asked", func(t *testing.T) {
+		result, err := engine.NetCarbs(context.Background(), 2, "", false)
+
+		require.NoError(t, err)
+		assert.InDelta(t, 90, result.NetCarbs, 0.0001)
+	})
+
+	t.Run("errors without a carbohydrate value", func(t *testing.T) {
+		_, err := engine.NetCarbs(context.Background(), 3, "", true)
+
+		assert.Error(t, err)
+	})
+}
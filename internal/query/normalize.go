@@ -0,0 +1,93 @@
+package query
+
+import (
+	"context"
+	"fmt"
+)
+
+// normalizedDescriptions returns the precomputed normalized descriptions, building them on first use.
+// The caller must hold the read lock.
+func (e *Engine) normalizedDescriptions() []string {
+	e.normMu.Lock()
+	defer e.normMu.Unlock()
+
+	if len(e.normalizedForms) != len(e.data.FoundationFoods) {
+		e.normalizedForms = buildNormalizedDescriptions(e.data.FoundationFoods)
+	}
+
+	return e.normalizedForms
+}
+
+// buildNormalizedDescriptions normalizes every food description once so searches don't repeat the work
+func buildNormalizedDescriptions(foods []FoundationFood) []string {
+	normalized := make([]string, len(foods))
+	for i, food := range foods {
+		normalized[i] = normalizeString(food.Description)
+	}
+	return normalized
+}
+
+// normalizationInputs holds what the engine re-reads from its configured files on a refresh. A nil field
+// means no file is configured for it, so the current value is kept.
+type normalizationInputs struct {
+	synonyms map[string][][]string
+	weights  *ScoringWeights
+}
+
+// loadNormalizationInputs reads the engine's configured normalization files
+func (e *Engine) loadNormalizationInputs() (normalizationInputs, error) {
+	synonyms, err := e.loadSynonymsFile()
+	if err != nil {
+		return normalizationInputs{}, err
+	}
+
+	weights, err := e.loadScoringWeightsFile()
+	if err != nil {
+		return normalizationInputs{}, err
+	}
+
+	return normalizationInputs{synonyms: synonyms, weights: weights}, nil
+}
+
+// applyNormalizationInputs installs freshly read normalization inputs; the caller must hold the write
+// lock unless the engine isn't shared yet
+func (e *Engine) applyNormalizationInputs(inputs normalizationInputs) {
+	if inputs.synonyms != nil {
+		e.synonyms = inputs.synonyms
+	}
+	if inputs.weights != nil {
+		e.weights = inputs.weights
+	}
+}
+
+// RefreshNormalization re-reads the configured synonyms and scoring weights files, rebuilds the precomputed normalized
+// descriptions and clears derived caches so normalization changes take effect without restarting the
+// process. When a file fails to load, nothing changes.
+func (e *Engine) RefreshNormalization(ctx context.Context) error {
+	// A reload between reading the dataset and swapping it back in would otherwise be undone
+	e.reloadMu.Lock()
+	defer e.reloadMu.Unlock()
+
+	inputs, err := e.loadNormalizationInputs()
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	data := e.data
+	if data != nil {
+		e.applyNormalizationInputs(inputs)
+	}
+	e.mu.Unlock()
+
+	if data == nil {
+		return fmt.Errorf("foundation Foods data not loaded")
+	}
+
+	e.swapData(data)
+
+	e.logger.InfoContext(ctx, "Normalization refreshed",
+		"food_count", len(data.FoundationFoods))
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package query
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_RefreshNormalization(t *testing.T) {
+	synonymsFile := filepath.Join(t.TempDir(), "synonyms.json")
+	require.NoError(t, os.WriteFile(synonymsFile, []byte(`{}`), 0o600))
+
+	engine := &Engine{
+		data: &FoundationFoodsData{
+			FoundationFoods: []FoundationFood{
+				{Description: "Carbonated beverage, cola", FdcId: 1, FoodCategory: FoodCategory{Description: "Beverages"}},
+			},
+		},
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+	WithSynonymsFile(synonymsFile)(engine)
+	WithSearchCacheSize(10)(engine)
+
+	ctx := context.Background()
+	require.NoError(t, engine.RefreshNormalization(ctx))
+
+	// The built-in "soda" synonym finds the cola, and the result is cached
+	results, err := engine.SearchFoodsByName(ctx, "soda", 3, SearchOptions{})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	// Warm the category cache so the refresh has something to clear
+	_, err = engine.CompareFoodToCategory(ctx, 1)
+	require.NoError(t, err)
+	require.NotEmpty(t, engine.categoryAverages)
+
+	// Remap "soda" in the synonyms file; the cached search is stale until the refresh
+	require.NoError(t, os.WriteFile(synonymsFile, []byte(`{"soda": ["sodium"]}`), 0o600))
+
+	results, err = engine.SearchFoodsByName(ctx, "soda", 3, SearchOptions{})
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+
+	require.NoError(t, engine.RefreshNormalization(ctx))
+
+	results, err = engine.SearchFoodsByName(ctx, "soda", 3, SearchOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+	assert.Empty(t, engine.categoryAverages)
+}
+
+func TestEngine_RefreshNormalizationKeepsConcurrentReload(t *testing.T) {
+	logger := config.NewTestLogger(io.Discard, "info")
+	ctx := context.Background()
+
+	path := filepath.Join(t.TempDir(), "foods.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"FoundationFoods": [{"description": "Milk, whole", "fdcId": 1}]}`), 0o600))
+	engine, err := NewEngine(path, logger)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"FoundationFoods": [
+		{"description": "Milk, whole", "fdcId": 1},
+		{"description": "Milk, lowfat", "fdcId": 2}
+	]}`), 0o600))
+
+	// However the refreshes interleave with the reload, none may swap the old dataset back in
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 20 {
+				assert.NoError(t, engine.RefreshNormalization(ctx))
+			}
+		}()
+	}
+	require.NoError(t, engine.Reload(ctx))
+	wg.Wait()
+
+	food, err := engine.GetFoodByFdcId(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "Milk, lowfat", food.Description)
+}
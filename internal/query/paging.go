@@ -0,0 +1,41 @@
+package query
+
+// PageInfo describes which slice of an aggregate result was returned
+type PageInfo struct {
+	Offset     int  `json:"offset"`
+	Limit      int  `json:"limit"`
+	Total      int  `json:"total"`
+	HasMore    bool `json:"hasMore"`
+	NextOffset *int `json:"nextOffset,omitempty"`
+}
+
+// Paginate returns the page of items starting at offset holding at most limit items, along with paging metadata.
+// An offset past the end yields an empty page rather than an error.
+func Paginate[T any](items []T, offset, limit int) ([]T, PageInfo) {
+	if offset < 0 {
+		offset = 0
+	}
+	if limit < 0 {
+		limit = 0
+	}
+
+	total := len(items)
+	page := PageInfo{Offset: offset, Limit: limit, Total: total}
+
+	if offset >= total {
+		return []T{}, page
+	}
+
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+
+	if end < total {
+		next := end
+		page.HasMore = true
+		page.NextOffset = &next
+	}
+
+	return items[offset:end], page
+}
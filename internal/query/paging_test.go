@@ -0,0 +1,38 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaginate(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+
+	t.Run("returns first page with next offset", func(t *testing.T) {
+		page, info := Paginate(items, 0, 2)
+
+		assert.Equal(t, []int{1, 2}, page)
+		assert.Equal(t, 5, info.Total)
+		assert.True(t, info.HasMore)
+		require.NotNil(t, info.NextOffset)
+		assert.Equal(t, 2, *info.NextOffset)
+	})
+
+	t.Run("returns last partial page", func(t *testing.T) {
+		page, info := Paginate(items, 4, 2)
+
+		assert.Equal(t, []int{5}, page)
+		assert.False(t, info.HasMore)
+		assert.Nil(t, info.NextOffset)
+	})
+
+	t.Run("returns empty page past the end", func(t *testing.T) {
+		page, info := Paginate(items, 10, 2)
+
+		assert.Empty(t, page)
+		assert.NotNil(t, page)
+		assert.False(t, info.HasMore)
+	})
+}
@@ -0,0 +1,49 @@
+package query
+
+import "sort"
+
+// DefaultNotablePercentile is the percentile rank a nutrient must reach to count as notable when not configured
+const DefaultNotablePercentile = 75.0
+
+// nutrientDistributionsFor returns, per nutrient, the sorted amounts across every food reporting it,
+// computing and caching them on first use. The caller must hold the read lock.
+func (e *Engine) nutrientDistributionsFor() map[string][]float64 {
+	e.percentileMu.Lock()
+	defer e.percentileMu.Unlock()
+
+	if e.nutrientDistributions != nil {
+		return e.nutrientDistributions
+	}
+
+	distributions := make(map[string][]float64)
+	for _, food := range e.data.FoundationFoods {
+		for key, amount := range nutrientAmounts(food) {
+			distributions[key] = append(distributions[key], amount)
+		}
+	}
+	for _, amounts := range distributions {
+		sort.Float64s(amounts)
+	}
+
+	e.nutrientDistributions = distributions
+
+	e.logger.Debug("Computed nutrient distributions",
+		"nutrient_count", len(distributions))
+
+	return distributions
+}
+
+// percentileRank returns the percentage (0-100) of foods reporting a nutrient whose amount is at or below amount.
+// The caller must hold the read lock.
+func (e *Engine) percentileRank(name, unit string, amount float64) float64 {
+	amounts := e.nutrientDistributionsFor()[nutrientKey(name, unit)]
+	if len(amounts) == 0 {
+		return 0
+	}
+
+	atOrBelow := sort.Search(len(amounts), func(i int) bool {
+		return amounts[i] > amount
+	})
+
+	return float64(atOrBelow) / float64(len(amounts)) * 100
+}
@@ -0,0 +1,87 @@
+package query
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_SearchFoodsByNameSimplified_NotableOnly(t *testing.T) {
+	foodWith := func(fdcId int, description string, calcium, iron float64) FoundationFood {
+		return FoundationFood{
+			Description: description,
+			FdcId:       fdcId,
+			FoodNutrients: []FoodNutrient{
+				{Nutrient: Nutrient{Name: "Calcium, Ca", UnitName: "mg"}, Amount: calcium},
+				{Nutrient: Nutrient{Name: "Iron, Fe", UnitName: "mg"}, Amount: iron},
+			},
+		}
+	}
+
+	testData := &FoundationFoodsData{
+		FoundationFoods: []FoundationFood{
+			foodWith(1, "Cheese, parmesan, hard", 1180, 0.1),
+			foodWith(2, "Spinach, raw", 99, 2.7),
+			foodWith(3, "Lentils, dry", 35, 6.5),
+			foodWith(4, "Beef, ground, raw", 12, 2.2),
+		},
+	}
+
+	engine := &Engine{
+		data:   testData,
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+
+	nutrientNames := func(t *testing.T, opts SearchOptions) []string {
+		result, err := engine.SearchFoodsByNameSimplified(context.Background(), "parmesan", 1, nil, opts)
+		require.NoError(t, err)
+		require.Len(t, result.Foods, 1)
+
+		var names []string
+		for _, nutrient := range result.Foods[0].Nutrients {
+			names = append(names, nutrient.Name)
+		}
+		return names
+	}
+
+	t.Run("keeps only nutrients the food is a notable source of", func(t *testing.T) {
+		names := nutrientNames(t, SearchOptions{NotableOnly: true})
+
+		assert.Equal(t, []string{"Calcium, Ca"}, names)
+	})
+
+	t.Run("returns every nutrient by default", func(t *testing.T) {
+		names := nutrientNames(t, SearchOptions{})
+
+		assert.Equal(t, []string{"Calcium, Ca", "Iron, Fe"}, names)
+	})
+
+	t.Run("respects a custom percentile", func(t *testing.T) {
+		names := nutrientNames(t, SearchOptions{NotableOnly: true, NotablePercentile: 20})
+
+		assert.Equal(t, []string{"Calcium, Ca", "Iron, Fe"}, names)
+	})
+}
+
+func TestEngine_percentileRank(t *testing.T) {
+	engine := &Engine{
+		data: &FoundationFoodsData{
+			FoundationFoods: []FoundationFood{
+				{FoodNutrients: []FoodNutrient{{Nutrient: Nutrient{Name: "Protein", UnitName: "g"}, Amount: 1}}},
+				{FoodNutrients: []FoodNutrient{{Nutrient: Nutrient{Name: "Protein", UnitName: "g"}, Amount: 2}}},
+				{FoodNutrients: []FoodNutrient{{Nutrient: Nutrient{Name: "Protein", UnitName: "g"}, Amount: 3}}},
+				{FoodNutrients: []FoodNutrient{{Nutrient: Nutrient{Name: "Protein", UnitName: "g"}, Amount: 4}}},
+			},
+		},
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+
+	assert.Equal(t, 100.0, engine.percentileRank("Protein", "g", 4))
+	assert.Equal(t, 50.0, engine.percentileRank("Protein", "g", 2))
+	assert.Equal(t, 0.0, engine.percentileRank("Protein", "g", 0.5))
+	assert.Equal(t, 0.0, engine.percentileRank("Fiber", "g", 5))
+}
@@ -0,0 +1,120 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+// performanceFixtureSize is roughly the number of foods in a Foundation Foods release, padded so scan
+// costs show up clearly
+const performanceFixtureSize = 2000
+
+// searchTimeGuard is a deliberately loose bound on the average time of one search of the fixture. A
+// search takes a few milliseconds, a few times that under -race, so only an algorithmic regression, like
+// normalizing every description on each search again, gets near it on a slow CI runner.
+const searchTimeGuard = 100 * time.Millisecond
+
+// newPerformanceFixture builds a dataset shaped like the real one: multi-word descriptions across a few
+// categories, each food reporting the default nutrient set and a couple of portions
+func newPerformanceFixture(size int) *FoundationFoodsData {
+	bases := []string{"Milk", "Cheese", "Chicken", "Beef", "Broccoli", "Apples", "Bread", "Beans", "Yogurt", "Salmon"}
+	forms := []string{"raw", "cooked, boiled", "whole", "lowfat, 2% milkfat", "canned, drained", "frozen, unprepared"}
+	categories := []string{"Dairy and Egg Products", "Poultry Products", "Vegetables and Vegetable Products", "Baked Products"}
+
+	foods := make([]FoundationFood, 0, size)
+	for i := 0; i < size; i++ {
+		nutrients := make([]FoodNutrient, 0, len(DefaultNutrients))
+		for j, name := range DefaultNutrients {
+			nutrients = append(nutrients, FoodNutrient{
+				Nutrient: Nutrient{Name: name, UnitName: "mg"},
+				Amount:   float64((i*31 + j*7) % 500),
+			})
+		}
+
+		foods = append(foods, FoundationFood{
+			Description:   fmt.Sprintf("%s, %s, variety %d", bases[i%len(bases)], forms[i%len(forms)], i),
+			FdcId:         100000 + i,
+			FoodCategory:  FoodCategory{Description: categories[i%len(categories)]},
+			FoodNutrients: nutrients,
+			FoodPortions: []FoodPortion{
+				{Value: 1, MeasureUnit: MeasureUnit{Name: "cup"}, GramWeight: 240, SequenceNumber: 1},
+				{Value: 1, MeasureUnit: MeasureUnit{Name: "tbsp"}, GramWeight: 15, SequenceNumber: 2},
+			},
+		})
+	}
+
+	return &FoundationFoodsData{FoundationFoods: foods}
+}
+
+func newPerformanceEngine() *Engine {
+	engine := &Engine{logger: config.NewTestLogger(io.Discard, "info")}
+	engine.swapData(newPerformanceFixture(performanceFixtureSize))
+	return engine
+}
+
+func BenchmarkSearchFoodsByName(b *testing.B) {
+	engine := newPerformanceEngine()
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := engine.SearchFoodsByName(ctx, "milk whole", 3, SearchOptions{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSearchFoodsByNameSimplified(b *testing.B) {
+	engine := newPerformanceEngine()
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := engine.SearchFoodsByNameSimplified(ctx, "milk whole", 3, nil, SearchOptions{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestSearchPerformanceGuard(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping search timing guard in short mode")
+	}
+
+	engine := newPerformanceEngine()
+	ctx := context.Background()
+
+	const searches = 20
+	searchFuncs := map[string]func() error{
+		"SearchFoodsByName": func() error {
+			_, err := engine.SearchFoodsByName(ctx, "milk whole", 3, SearchOptions{})
+			return err
+		},
+		"SearchFoodsByNameSimplified": func() error {
+			_, err := engine.SearchFoodsByNameSimplified(ctx, "milk whole", 3, nil, SearchOptions{})
+			return err
+		},
+	}
+
+	for name, search := range searchFuncs {
+		t.Run(name, func(t *testing.T) {
+			// The first search builds the normalized description index, which isn't what's being measured
+			require.NoError(t, search())
+
+			start := time.Now()
+			for i := 0; i < searches; i++ {
+				require.NoError(t, search())
+			}
+			average := time.Since(start) / searches
+
+			t.Logf("average search time over %d foods: %v", performanceFixtureSize, average)
+			require.Less(t, average, searchTimeGuard, "searching %d foods took %v on average", performanceFixtureSize, average)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package query
+
+import "strings"
+
+// irregularPlurals maps food-related plurals that don't follow the s/es/ies rules to their singular
+var irregularPlurals = map[string]string{
+	"leaves": "leaf",
+	"loaves": "loaf",
+	"halves": "half",
+	"calves": "calf",
+	"geese":  "goose",
+	"fungi":  "fungus",
+	"cacti":  "cactus",
+}
+
+// pluralEquivalent reports whether two words are the singular and plural of each other, such as egg/eggs,
+// tomato/tomatoes or berry/berries. Identical words are not reported; callers check those first.
+func pluralEquivalent(a, b string) bool {
+	return isPluralOf(a, b) || isPluralOf(b, a)
+}
+
+// isPluralOf reports whether plural is a regular (s, es, ies) or irregular plural of singular.
+// Singulars shorter than 3 letters never match, so short words like "a" and "as" stay distinct.
+func isPluralOf(plural, singular string) bool {
+	if len(singular) < 3 {
+		return false
+	}
+	if irregularPlurals[plural] == singular {
+		return true
+	}
+	if !strings.HasPrefix(plural, singular[:len(singular)-1]) {
+		return false
+	}
+
+	switch len(plural) - len(singular) {
+	case 1:
+		// egg -> eggs
+		return plural[len(singular)] == 's' && plural[:len(singular)] == singular
+	case 2:
+		// tomato -> tomatoes, berry -> berries
+		if plural[:len(singular)] == singular && strings.HasSuffix(plural, "es") {
+			return true
+		}
+		return strings.HasSuffix(singular, "y") && strings.HasSuffix(plural, "ies")
+	}
+
+	return false
+}
@@ -0,0 +1,68 @@
+package query
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPluralEquivalent(t *testing.T) {
+	testCases := []struct {
+		a, b     string
+		expected bool
+	}{
+		{"egg", "eggs", true},
+		{"eggs", "egg", true},
+		{"tomato", "tomatoes", true},
+		{"berry", "berries", true},
+		{"cookie", "cookies", true},
+		{"peach", "peaches", true},
+		{"leaf", "leaves", true},
+		{"loaves", "loaf", true},
+		{"egg", "egg", false},
+		{"egg", "eggplant", false},
+		{"berry", "berried", false},
+		{"as", "a", false},
+		{"pea", "peanut", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.a+"/"+tc.b, func(t *testing.T) {
+			assert.Equal(t, tc.expected, pluralEquivalent(tc.a, tc.b))
+		})
+	}
+}
+
+func TestScoreDescription_Plurals(t *testing.T) {
+	score := func(description, query string) float64 {
+		normalizedQuery := normalizeString(query)
+		return calculateRelevanceScore(description, normalizedQuery, strings.Fields(normalizedQuery))
+	}
+
+	testCases := []struct {
+		query, description string
+	}{
+		{"egg", "Eggs, Grade A, Large, egg whole"},
+		{"eggs", "Egg, white, raw, frozen, pasteurized"},
+		{"tomatoes", "Tomato, roma"},
+		{"tomato", "Tomatoes, grape, raw"},
+		{"berries", "Berry, mixed, frozen"},
+		{"berry", "Berries, mixed, frozen"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.query+" matches "+tc.description, func(t *testing.T) {
+			assert.Positive(t, score(tc.description, tc.query))
+		})
+	}
+
+	t.Run("plural match beats a prefix match", func(t *testing.T) {
+		assert.Greater(t, score("Eggs, whole", "egg"), score("Eggnog", "egg"))
+		assert.Greater(t, score("Tomatoes, raw", "tomato"), score("Tomatillos, raw", "tomato"))
+	})
+
+	t.Run("exact word still beats a plural match", func(t *testing.T) {
+		assert.Greater(t, score("Egg, whole, raw", "egg"), score("Eggs, whole, raw", "egg"))
+	})
+}
@@ -0,0 +1,181 @@
+package query
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GramWeightDecimals is the number of decimals portion gram weights are rounded to for display
+const GramWeightDecimals = 1
+
+// SelectPortion picks the portion to scale a food to. Portions are considered in sequence number
+// order: when label is empty the first portion with a usable gram weight is used, otherwise the
+// first portion whose measure unit name or abbreviation matches label case-insensitively.
+func SelectPortion(food FoundationFood, label string) (*FoodPortion, error) {
+	normalizedLabel := strings.ToLower(strings.TrimSpace(label))
+
+	for _, portion := range sortedPortions(food.FoodPortions) {
+		if !validGramWeight(portion.GramWeight) {
+			continue
+		}
+
+		if normalizedLabel == "" ||
+			strings.ToLower(strings.TrimSpace(portion.MeasureUnit.Name)) == normalizedLabel ||
+			strings.ToLower(strings.TrimSpace(portion.MeasureUnit.Abbreviation)) == normalizedLabel {
+			return &portion, nil
+		}
+	}
+
+	if normalizedLabel == "" {
+		return nil, fmt.Errorf("food %q has no portions with a gram weight", food.Description)
+	}
+	return nil, fmt.Errorf("food %q has no portion matching %q", food.Description, label)
+}
+
+// ScaleFoodToPortion returns a copy of food with every nutrient amount scaled from per 100 g to the given portion
+func ScaleFoodToPortion(food FoundationFood, portion FoodPortion) FoundationFood {
+	factor := portion.GramWeight / 100
+
+	scaled := food
+	scaled.FoodNutrients = make([]FoodNutrient, len(food.FoodNutrients))
+	for i, nutrient := range food.FoodNutrients {
+		nutrient.Amount *= factor
+		nutrient.Min *= factor
+		nutrient.Max *= factor
+		nutrient.Median *= factor
+		scaled.FoodNutrients[i] = nutrient
+	}
+	scaled.ServingPortion = &portion
+
+	return scaled
+}
+
+// DefaultBasis is the basis nutrient amounts are reported on unless they were scaled to a portion
+const DefaultBasis = "100 g"
+
+// SelectSimplifiedPortion returns the portion of a simplified food whose measure unit name or abbreviation
+// matches label case-insensitively and that has a usable gram weight
+func SelectSimplifiedPortion(food SimplifiedFood, label string) (*SimplifiedFoodPortion, error) {
+	normalizedLabel := strings.ToLower(strings.TrimSpace(label))
+
+	for i := range food.FoodPortions {
+		portion := food.FoodPortions[i]
+		if !validGramWeight(portion.GramWeight) {
+			continue
+		}
+
+		if strings.ToLower(strings.TrimSpace(portion.MeasureUnit.Name)) == normalizedLabel ||
+			strings.ToLower(strings.TrimSpace(portion.MeasureUnit.Abbreviation)) == normalizedLabel {
+			return &portion, nil
+		}
+	}
+
+	return nil, fmt.Errorf("food %q has no portion matching %q", food.Name, label)
+}
+
+// PortionBasis describes a portion as a basis label such as "1 cup (244 g)"
+func PortionBasis(portion SimplifiedFoodPortion) string {
+	grams := strconv.FormatFloat(RoundGramWeight(portion.GramWeight), 'f', -1, 64)
+	if portion.Value > 0 {
+		return fmt.Sprintf("%s %s (%s g)", strconv.FormatFloat(portion.Value, 'f', -1, 64), portion.MeasureUnit.Name, grams)
+	}
+	return fmt.Sprintf("%s (%s g)", portion.MeasureUnit.Name, grams)
+}
+
+// ScaleSimplifiedFood scales a simplified food's nutrient amounts in place from per 100 g to grams and
+// records basis as what the amounts now refer to
+func ScaleSimplifiedFood(food *SimplifiedFood, grams float64, basis string) {
+	factor := grams / 100
+	for i := range food.Nutrients {
+		food.Nutrients[i].Amount *= factor
+	}
+	food.Basis = basis
+}
+
+// RoundGramWeight rounds a gram weight to GramWeightDecimals for display
+func RoundGramWeight(grams float64) float64 {
+	scale := math.Pow(10, GramWeightDecimals)
+	return math.Round(grams*scale) / scale
+}
+
+// RoundFoodGramWeights returns a copy of food with its portion and serving portion gram weights rounded for
+// display. Nutrient amounts are left as they are, so scaling done beforehand keeps its full precision.
+func RoundFoodGramWeights(food FoundationFood) FoundationFood {
+	rounded := food
+	rounded.FoodPortions = make([]FoodPortion, len(food.FoodPortions))
+	for i, portion := range food.FoodPortions {
+		portion.GramWeight = RoundGramWeight(portion.GramWeight)
+		rounded.FoodPortions[i] = portion
+	}
+
+	if food.ServingPortion != nil {
+		serving := *food.ServingPortion
+		serving.GramWeight = RoundGramWeight(serving.GramWeight)
+		rounded.ServingPortion = &serving
+	}
+
+	return rounded
+}
+
+// RoundSimplifiedGramWeights rounds the portion gram weights of a simplified response in place for display
+func RoundSimplifiedGramWeights(response *SimplifiedNutrientResponse) {
+	for i := range response.Foods {
+		for j := range response.Foods[i].FoodPortions {
+			response.Foods[i].FoodPortions[j].GramWeight = RoundGramWeight(response.Foods[i].FoodPortions[j].GramWeight)
+		}
+	}
+}
+
+// WithDropInvalidPortions removes portions whose gram weight is zero, negative or not a finite number
+// while loading the dataset, so per-serving scaling never divides by zero or produces nonsense servings
+func WithDropInvalidPortions(drop bool) EngineOption {
+	return func(e *Engine) {
+		e.dropInvalidPortions = drop
+	}
+}
+
+// validGramWeight reports whether a portion gram weight can be used for scaling
+func validGramWeight(grams float64) bool {
+	return grams > 0 && !math.IsInf(grams, 0) && !math.IsNaN(grams)
+}
+
+// dropInvalidPortions removes portions with an unusable gram weight from every food in place, logging each
+// one, and returns how many were dropped
+func dropInvalidPortions(foods []FoundationFood, logger *slog.Logger) int {
+	var dropped int
+	for i := range foods {
+		kept := foods[i].FoodPortions[:0]
+		for _, portion := range foods[i].FoodPortions {
+			if validGramWeight(portion.GramWeight) {
+				kept = append(kept, portion)
+				continue
+			}
+
+			dropped++
+			logger.Debug("Dropping portion with invalid gram weight",
+				"fdcId", foods[i].FdcId,
+				"description", foods[i].Description,
+				"measure_unit", portion.MeasureUnit.Name,
+				"gram_weight", portion.GramWeight)
+		}
+		foods[i].FoodPortions = kept
+	}
+
+	return dropped
+}
+
+// sortedPortions returns a copy of portions ordered by their USDA sequence number
+func sortedPortions(portions []FoodPortion) []FoodPortion {
+	sorted := make([]FoodPortion, len(portions))
+	copy(sorted, portions)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].SequenceNumber < sorted[j].SequenceNumber
+	})
+
+	return sorted
+}
@@ -0,0 +1,101 @@
+package query
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectPortion(t *testing.T) {
+	food := FoundationFood{
+		Description: "Milk, whole",
+		FoodPortions: []FoodPortion{
+			{MeasureUnit: MeasureUnit{Name: "undetermined"}, GramWeight: 0},
+			{MeasureUnit: MeasureUnit{Name: "cup", Abbreviation: "c"}, GramWeight: 244},
+			{MeasureUnit: MeasureUnit{Name: "tablespoon", Abbreviation: "tbsp"}, GramWeight: 15},
+		},
+	}
+
+	t.Run("defaults to the first portion with a gram weight", func(t *testing.T) {
+		portion, err := SelectPortion(food, "")
+
+		require.NoError(t, err)
+		assert.Equal(t, "cup", portion.MeasureUnit.Name)
+	})
+
+	t.Run("matches by name or abbreviation", func(t *testing.T) {
+		portion, err := SelectPortion(food, "TBSP")
+
+		require.NoError(t, err)
+		assert.Equal(t, 15.0, portion.GramWeight)
+	})
+
+	t.Run("returns error for unknown label", func(t *testing.T) {
+		portion, err := SelectPortion(food, "gallon")
+
+		assert.Error(t, err)
+		assert.Nil(t, portion)
+	})
+
+	t.Run("defaults to the lowest sequence number rather than slice order", func(t *testing.T) {
+		outOfOrder := FoundationFood{
+			Description: "Milk, whole",
+			FoodPortions: []FoodPortion{
+				{MeasureUnit: MeasureUnit{Name: "quart"}, GramWeight: 976, SequenceNumber: 3},
+				{MeasureUnit: MeasureUnit{Name: "undetermined"}, GramWeight: 0, SequenceNumber: 1},
+				{MeasureUnit: MeasureUnit{Name: "cup"}, GramWeight: 244, SequenceNumber: 2},
+			},
+		}
+
+		portion, err := SelectPortion(outOfOrder, "")
+
+		require.NoError(t, err)
+		assert.Equal(t, "cup", portion.MeasureUnit.Name)
+	})
+
+	t.Run("skips infinite and NaN gram weights", func(t *testing.T) {
+		unusable := FoundationFood{
+			Description: "Milk, whole",
+			FoodPortions: []FoodPortion{
+				{MeasureUnit: MeasureUnit{Name: "cup"}, GramWeight: math.Inf(1)},
+				{MeasureUnit: MeasureUnit{Name: "cup"}, GramWeight: math.NaN()},
+				{MeasureUnit: MeasureUnit{Name: "cup"}, GramWeight: 244},
+			},
+		}
+
+		portion, err := SelectPortion(unusable, "cup")
+		require.NoError(t, err)
+		assert.Equal(t, 244.0, portion.GramWeight)
+
+		unusable.FoodPortions = unusable.FoodPortions[:2]
+		portion, err = SelectPortion(unusable, "")
+		assert.Error(t, err)
+		assert.Nil(t, portion)
+	})
+}
+
+func TestScaleFoodToPortion(t *testing.T) {
+	food := FoundationFood{
+		Description: "Milk, whole",
+		FoodNutrients: []FoodNutrient{
+			{Nutrient: Nutrient{Name: "Protein", UnitName: "g"}, Amount: 3.3, Min: 3, Max: 4},
+			{Nutrient: Nutrient{Name: "Calcium, Ca", UnitName: "mg"}, Amount: 120},
+		},
+	}
+	portion := FoodPortion{MeasureUnit: MeasureUnit{Name: "cup"}, GramWeight: 244}
+
+	scaled := ScaleFoodToPortion(food, portion)
+
+	assert.InDelta(t, 8.052, scaled.FoodNutrients[0].Amount, 0.0001)
+	assert.InDelta(t, 7.32, scaled.FoodNutrients[0].Min, 0.0001)
+	assert.InDelta(t, 9.76, scaled.FoodNutrients[0].Max, 0.0001)
+	assert.InDelta(t, 292.8, scaled.FoodNutrients[1].Amount, 0.0001)
+	require.NotNil(t, scaled.ServingPortion)
+	assert.Equal(t, "cup", scaled.ServingPortion.MeasureUnit.Name)
+
+	// The original food must be left untouched
+	assert.Equal(t, 3.3, food.FoodNutrients[0].Amount)
+	assert.Nil(t, food.ServingPortion)
+}
@@ -0,0 +1,71 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Preparation values for SearchOptions.Preparation
+const (
+	PreparationAny    = "any"
+	PreparationRaw    = "raw"
+	PreparationCooked = "cooked"
+)
+
+// Score multipliers for descriptions that match, or contradict, the requested preparation
+const (
+	preparationBoost  = 2.0
+	preparationDemote = 0.5
+)
+
+// cookedWords are description words that mark a food as cooked
+var cookedWords = map[string]bool{
+	"cooked":   true,
+	"baked":    true,
+	"boiled":   true,
+	"braised":  true,
+	"broiled":  true,
+	"fried":    true,
+	"grilled":  true,
+	"roasted":  true,
+	"sauteed":  true,
+	"simmered": true,
+	"steamed":  true,
+	"stewed":   true,
+}
+
+// descriptionPreparation reports whether a normalized description says the food is raw or cooked,
+// or "" when it says neither
+func descriptionPreparation(normalizedDesc string) string {
+	for _, word := range strings.Fields(normalizedDesc) {
+		switch {
+		case word == "raw":
+			return PreparationRaw
+		case cookedWords[word]:
+			return PreparationCooked
+		}
+	}
+	return ""
+}
+
+// adjustScoreForPreparation boosts foods described with the requested preparation and demotes foods
+// described with the opposite one. Any other preparation value leaves the score unchanged. The adjustment
+// is recorded in breakdown unless it is nil.
+func adjustScoreForPreparation(normalizedDesc, preparation string, score float64, breakdown *ScoreBreakdown) float64 {
+	preparation = strings.ToLower(strings.TrimSpace(preparation))
+	if preparation != PreparationRaw && preparation != PreparationCooked {
+		return score
+	}
+
+	switch descriptionPreparation(normalizedDesc) {
+	case "":
+		return score
+	case preparation:
+		score *= preparationBoost
+		breakdown.multiply(fmt.Sprintf("description is %s as requested", preparation), preparationBoost, score)
+	default:
+		score *= preparationDemote
+		breakdown.multiply(fmt.Sprintf("description is not %s as requested", preparation), preparationDemote, score)
+	}
+	return score
+}
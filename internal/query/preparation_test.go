@@ -0,0 +1,73 @@
+package query
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_SearchPreparation(t *testing.T) {
+	testData := &FoundationFoodsData{
+		FoundationFoods: []FoundationFood{
+			{Description: "Chicken, breast, roasted", FdcId: 1},
+			{Description: "Chicken, thigh, boiled", FdcId: 2},
+			{Description: "Chicken, wing, raw", FdcId: 3},
+			{Description: "Chicken, ground", FdcId: 4},
+		},
+	}
+
+	engine := &Engine{
+		data:   testData,
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+	ctx := context.Background()
+
+	ids := func(foods []FoundationFood) []int {
+		result := make([]int, len(foods))
+		for i, food := range foods {
+			result[i] = food.FdcId
+		}
+		return result
+	}
+
+	t.Run("any keeps the default ranking", func(t *testing.T) {
+		plain, err := engine.SearchFoodsByName(ctx, "chicken", 10, SearchOptions{})
+		require.NoError(t, err)
+		anyPrep, err := engine.SearchFoodsByName(ctx, "chicken", 10, SearchOptions{Preparation: PreparationAny})
+		require.NoError(t, err)
+
+		assert.Equal(t, ids(plain), ids(anyPrep))
+		assert.NotEqual(t, 3, plain[0].FdcId)
+	})
+
+	t.Run("raw surfaces raw cuts first and demotes cooked ones", func(t *testing.T) {
+		results, err := engine.SearchFoodsByName(ctx, "chicken", 10, SearchOptions{Preparation: PreparationRaw})
+		require.NoError(t, err)
+
+		require.Len(t, results, 4)
+		assert.Equal(t, 3, results[0].FdcId)
+		assert.Equal(t, 4, results[1].FdcId)
+	})
+
+	t.Run("cooked demotes raw cuts", func(t *testing.T) {
+		results, err := engine.SearchFoodsByName(ctx, "chicken", 10, SearchOptions{Preparation: PreparationCooked})
+		require.NoError(t, err)
+
+		require.Len(t, results, 4)
+		assert.ElementsMatch(t, []int{1, 2}, ids(results[:2]))
+		assert.Equal(t, 3, results[3].FdcId)
+	})
+}
+
+func TestDescriptionPreparation(t *testing.T) {
+	assert.Equal(t, PreparationRaw, descriptionPreparation("broccoli raw"))
+	assert.Equal(t, PreparationCooked, descriptionPreparation("beef ground 80% lean meat cooked pan-broiled"))
+	assert.Equal(t, PreparationCooked, descriptionPreparation("chicken breast roasted"))
+	assert.Equal(t, "", descriptionPreparation("milk whole"))
+	// "rawhide" is not the word "raw"
+	assert.Equal(t, "", descriptionPreparation("rawhide chew"))
+}
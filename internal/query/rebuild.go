@@ -0,0 +1,73 @@
+package query
+
+import (
+	"runtime"
+	"sync"
+)
+
+// rebuildChunkSize is how many foods a rebuild worker indexes before yielding to other goroutines
+const rebuildChunkSize = 256
+
+// WithRebuildConcurrency bounds how many goroutines rebuild the derived indexes when the dataset is swapped.
+// The default of 1 keeps a rebuild to a single core so in-flight searches aren't starved; raise it to
+// finish rebuilds of large datasets sooner.
+func WithRebuildConcurrency(workers int) EngineOption {
+	return func(e *Engine) {
+		e.rebuildConcurrency = workers
+	}
+}
+
+// rebuildWorkers returns the configured rebuild concurrency, at least 1
+func (e *Engine) rebuildWorkers() int {
+	return max(e.rebuildConcurrency, 1)
+}
+
+// derivedIndexes are the indexes rebuilt from scratch whenever the dataset is swapped
+type derivedIndexes struct {
+	normalized      []string
+	ingredientIndex map[string][]ingredientPosting
+}
+
+// buildDerivedIndexes builds the derived indexes with at most workers goroutines. Foods are split into
+// contiguous chunks and each worker yields between chunks so searches keep getting scheduled.
+func buildDerivedIndexes(foods []FoundationFood, workers int) derivedIndexes {
+	chunks := (len(foods) + rebuildChunkSize - 1) / rebuildChunkSize
+	normalized := make([]string, len(foods))
+	partials := make([]map[string][]ingredientPosting, chunks)
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(workers, max(chunks, 1)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for chunk := range jobs {
+				start := chunk * rebuildChunkSize
+				end := min(start+rebuildChunkSize, len(foods))
+
+				for i := start; i < end; i++ {
+					normalized[i] = normalizeString(foods[i].Description)
+				}
+				partials[chunk] = buildIngredientIndexRange(foods, start, end)
+
+				runtime.Gosched()
+			}
+		}()
+	}
+
+	for chunk := range chunks {
+		jobs <- chunk
+	}
+	close(jobs)
+	wg.Wait()
+
+	// Merging in chunk order keeps each word's postings sorted by food index, as a sequential build would
+	ingredientIndex := make(map[string][]ingredientPosting)
+	for _, partial := range partials {
+		for word, postings := range partial {
+			ingredientIndex[word] = append(ingredientIndex[word], postings...)
+		}
+	}
+
+	return derivedIndexes{normalized: normalized, ingredientIndex: ingredientIndex}
+}
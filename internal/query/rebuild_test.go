@@ -0,0 +1,103 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRebuildTestData builds a dataset spanning several rebuild chunks, tagged with a generation marker
+func newRebuildTestData(generation, size int) *FoundationFoodsData {
+	foods := make([]FoundationFood, 0, size)
+	for i := 0; i < size; i++ {
+		foods = append(foods, FoundationFood{
+			Description: fmt.Sprintf("Milk, variety %d, generation %d", i, generation),
+			FdcId:       generation*100000 + i,
+			InputFoods: []InputFood{
+				{FoodDescription: fmt.Sprintf("Ingredient %d", i%7)},
+			},
+		})
+	}
+	return &FoundationFoodsData{FoundationFoods: foods}
+}
+
+func TestBuildDerivedIndexes_MatchesSequentialBuild(t *testing.T) {
+	foods := newRebuildTestData(1, 3*rebuildChunkSize+17).FoundationFoods
+
+	expectedNormalized := buildNormalizedDescriptions(foods)
+	expectedIngredients := buildIngredientIndex(foods)
+
+	for _, workers := range []int{1, 3, 16} {
+		t.Run(fmt.Sprintf("%d workers", workers), func(t *testing.T) {
+			indexes := buildDerivedIndexes(foods, workers)
+
+			assert.Equal(t, expectedNormalized, indexes.normalized)
+			assert.Equal(t, expectedIngredients, indexes.ingredientIndex)
+		})
+	}
+
+	t.Run("empty dataset", func(t *testing.T) {
+		indexes := buildDerivedIndexes(nil, 4)
+
+		assert.Empty(t, indexes.normalized)
+		assert.Empty(t, indexes.ingredientIndex)
+	})
+}
+
+func TestEngine_SearchesDuringParallelRebuild(t *testing.T) {
+	engine := &Engine{
+		logger:             config.NewTestLogger(io.Discard, "info"),
+		rebuildConcurrency: 4,
+	}
+	engine.swapData(newRebuildTestData(0, 2*rebuildChunkSize))
+
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+
+	// Every search must see one complete generation: the old data until the swap, then the new
+	for worker := 0; worker < 4; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+
+				results, err := engine.SearchFoodsByName(ctx, "milk variety", 10, SearchOptions{})
+				if !assert.NoError(t, err) || !assert.NotEmpty(t, results) {
+					return
+				}
+
+				generation := results[0].FdcId / 100000
+				for _, food := range results {
+					assert.True(t, strings.HasSuffix(food.Description, fmt.Sprintf("generation %d", generation)),
+						"mixed generations in one search: %q", food.Description)
+				}
+			}
+		}()
+	}
+
+	for generation := 1; generation <= 5; generation++ {
+		engine.swapData(newRebuildTestData(generation, 2*rebuildChunkSize))
+	}
+	require.NoError(t, engine.RefreshNormalization(ctx))
+
+	close(stop)
+	wg.Wait()
+
+	food, err := engine.GetFoodByFdcId(ctx, 500000)
+	require.NoError(t, err)
+	assert.Equal(t, "Milk, variety 0, generation 5", food.Description)
+}
@@ -0,0 +1,127 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// confidenceGapWeight is the share of the confidence that comes from the gap to the runner-up match
+const confidenceGapWeight = 0.3
+
+// scoreConfidence maps a relevance score onto a 0-1 confidence relative to exactScore, the scoring weights'
+// exact description bonus. Weights without an exact bonus fall back to the default one.
+func scoreConfidence(score, exactScore float64) float64 {
+	if exactScore <= 0 {
+		exactScore = DefaultScoringWeights.ExactDescription
+	}
+
+	if score <= 0 {
+		return 0
+	}
+	if score >= exactScore {
+		return 1
+	}
+	return score / exactScore
+}
+
+// matchConfidence turns ranked search results into a 0-1 confidence for the top match. Most of it
+// comes from the top score relative to an exact match worth exactScore; the rest from how far the top
+// match is ahead of the runner-up, so a strong but ambiguous match scores lower than an unambiguous one.
+func matchConfidence(results []SearchResult, exactScore float64) float64 {
+	if len(results) == 0 || results[0].Score <= 0 {
+		return 0
+	}
+
+	top := results[0].Score
+
+	// Without a runner-up the match is unambiguous
+	gap := 1.0
+	if len(results) > 1 {
+		gap = (top - results[1].Score) / top
+	}
+
+	return (1-confidenceGapWeight)*scoreConfidence(top, exactScore) + confidenceGapWeight*gap
+}
+
+// ResolveFoods finds the single best-matching food for each name, running the lookups concurrently
+func (e *Engine) ResolveFoods(ctx context.Context, names []string, opts SearchOptions) (*ResolveFoodsResponse, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.data == nil {
+		return nil, fmt.Errorf("foundation Foods data not loaded")
+	}
+
+	e.logger.DebugContext(ctx, "Resolving foods",
+		"name_count", len(names),
+		"category", opts.Category)
+
+	resolved := make([]ResolvedFood, len(names))
+	exactScore := e.scoringWeights().ExactDescription
+
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+
+			resolved[i] = ResolvedFood{Query: name}
+
+			results := e.scoreFoods(ctx, name, opts)
+			if len(results) == 0 {
+				return
+			}
+
+			best := results[0]
+			fdcId := best.Food.FdcId
+			description := best.Food.Description
+			resolved[i] = ResolvedFood{
+				Query:       name,
+				Found:       true,
+				FdcId:       &fdcId,
+				Description: &description,
+				Confidence:  matchConfidence(results, exactScore),
+			}
+		}(i, name)
+	}
+	wg.Wait()
+
+	response := &ResolveFoodsResponse{
+		Count: len(resolved),
+		Foods: resolved,
+	}
+	for _, food := range resolved {
+		if food.Found {
+			response.Resolved++
+		}
+	}
+
+	e.logger.DebugContext(ctx, "Resolve complete",
+		"name_count", response.Count,
+		"resolved", response.Resolved)
+
+	return response, nil
+}
+
+// CanonicalizeFoodName returns the exact USDA description of the best match for a loose name,
+// leaving the match empty when its confidence falls below minConfidence
+func (e *Engine) CanonicalizeFoodName(ctx context.Context, name string, minConfidence float64, opts SearchOptions) (*ResolvedFood, error) {
+	response, err := e.ResolveFoods(ctx, []string{name}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	canonical := response.Foods[0]
+	if canonical.Found && canonical.Confidence < minConfidence {
+		e.logger.DebugContext(ctx, "Canonical match below confidence threshold",
+			"query", name,
+			"description", *canonical.Description,
+			"confidence", canonical.Confidence,
+			"min_confidence", minConfidence)
+
+		canonical = ResolvedFood{Query: name, Confidence: canonical.Confidence}
+	}
+
+	return &canonical, nil
+}
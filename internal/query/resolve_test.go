@@ -0,0 +1,139 @@
+package query
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_ResolveFoods(t *testing.T) {
+	testData := &FoundationFoodsData{
+		FoundationFoods: []FoundationFood{
+			{Description: "Milk, whole, 3.25% milkfat", FdcId: 1},
+			{Description: "Eggs, whole, raw, fresh", FdcId: 2},
+			{Description: "Bread, white, commercially prepared", FdcId: 3},
+		},
+	}
+
+	engine := &Engine{
+		data:   testData,
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+
+	result, err := engine.ResolveFoods(context.Background(), []string{"milk", "xyz123nonexistent", "bread"}, SearchOptions{})
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Count)
+	assert.Equal(t, 2, result.Resolved)
+	require.Len(t, result.Foods, 3)
+
+	// Results keep the order of the requested names
+	milk := result.Foods[0]
+	assert.Equal(t, "milk", milk.Query)
+	assert.True(t, milk.Found)
+	require.NotNil(t, milk.FdcId)
+	assert.Equal(t, 1, *milk.FdcId)
+	assert.Equal(t, "Milk, whole, 3.25% milkfat", *milk.Description)
+	assert.Greater(t, milk.Confidence, 0.0)
+	assert.LessOrEqual(t, milk.Confidence, 1.0)
+
+	miss := result.Foods[1]
+	assert.False(t, miss.Found)
+	assert.Nil(t, miss.FdcId)
+	assert.Nil(t, miss.Description)
+	assert.Equal(t, 0.0, miss.Confidence)
+
+	bread := result.Foods[2]
+	require.NotNil(t, bread.FdcId)
+	assert.Equal(t, 3, *bread.FdcId)
+}
+
+func TestEngine_CanonicalizeFoodName(t *testing.T) {
+	testData := &FoundationFoodsData{
+		FoundationFoods: []FoundationFood{
+			{Description: "Milk, reduced fat, fluid, 2% milkfat, with added vitamin A and vitamin D", FdcId: 10},
+			{Description: "Cheese, cheddar", FdcId: 11},
+		},
+	}
+
+	engine := &Engine{
+		data:   testData,
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+
+	ctx := context.Background()
+
+	t.Run("maps a loose query to the canonical description", func(t *testing.T) {
+		result, err := engine.CanonicalizeFoodName(ctx, "2 percent milk", 0.1, SearchOptions{})
+
+		require.NoError(t, err)
+		assert.True(t, result.Found)
+		require.NotNil(t, result.Description)
+		assert.Equal(t, "Milk, reduced fat, fluid, 2% milkfat, with added vitamin A and vitamin D", *result.Description)
+		assert.Equal(t, 10, *result.FdcId)
+	})
+
+	t.Run("returns null when confidence is below the threshold", func(t *testing.T) {
+		result, err := engine.CanonicalizeFoodName(ctx, "2 percent milk", 0.99, SearchOptions{})
+
+		require.NoError(t, err)
+		assert.False(t, result.Found)
+		assert.Nil(t, result.Description)
+		assert.Nil(t, result.FdcId)
+		assert.Greater(t, result.Confidence, 0.0)
+	})
+}
+
+func TestMatchConfidence(t *testing.T) {
+	testData := &FoundationFoodsData{
+		FoundationFoods: []FoundationFood{
+			{Description: "Milk", FdcId: 1},
+			{Description: "Milk, whole, 3.25% milkfat", FdcId: 2},
+			{Description: "Buttermilk, low fat", FdcId: 3},
+			{Description: "Cheese, cheddar", FdcId: 4},
+		},
+	}
+
+	engine := &Engine{
+		data:   testData,
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+
+	t.Run("exact match yields high confidence", func(t *testing.T) {
+		confidence := matchConfidence(engine.scoreFoods(context.Background(), "milk", SearchOptions{}), DefaultScoringWeights.ExactDescription)
+
+		assert.Greater(t, confidence, 0.8)
+		assert.LessOrEqual(t, confidence, 1.0)
+	})
+
+	t.Run("weak substring match yields low confidence", func(t *testing.T) {
+		confidence := matchConfidence(engine.scoreFoods(context.Background(), "utterm", SearchOptions{}), DefaultScoringWeights.ExactDescription)
+
+		assert.Greater(t, confidence, 0.0)
+		assert.Less(t, confidence, 0.5)
+	})
+
+	t.Run("ambiguous matches score below unambiguous ones", func(t *testing.T) {
+		unambiguous := matchConfidence([]SearchResult{{Score: 600}, {Score: 60}}, DefaultScoringWeights.ExactDescription)
+		ambiguous := matchConfidence([]SearchResult{{Score: 600}, {Score: 590}}, DefaultScoringWeights.ExactDescription)
+
+		assert.Greater(t, unambiguous, ambiguous)
+	})
+
+	t.Run("confidence is relative to the configured exact match score", func(t *testing.T) {
+		results := []SearchResult{{Score: 600}}
+
+		assert.InDelta(t, 0.72, matchConfidence(results, 1000), 1e-9)
+		assert.InDelta(t, 0.51, matchConfidence(results, 2000), 1e-9)
+		assert.InDelta(t, 1.0, matchConfidence(results, 500), 1e-9)
+		assert.InDelta(t, 0.72, matchConfidence(results, 0), 1e-9, "no exact bonus falls back to the default")
+	})
+
+	t.Run("no results yield zero confidence", func(t *testing.T) {
+		assert.Equal(t, 0.0, matchConfidence(nil, DefaultScoringWeights.ExactDescription))
+	})
+}
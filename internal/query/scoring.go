@@ -0,0 +1,140 @@
+package query
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+)
+
+// ScoringWeights are the points and multipliers relevance scoring combines. Food-specific adjustments,
+// such as preferring "Milk, whole" over foods that merely mention milkfat, are not configurable.
+type ScoringWeights struct {
+	// ExactDescription is added when the whole description equals the query
+	ExactDescription float64 `json:"exactDescription"`
+
+	// DescriptionPrefix is added when the description starts with the query
+	DescriptionPrefix float64 `json:"descriptionPrefix"`
+
+	// DescriptionSubstring is added when the description contains the query anywhere
+	DescriptionSubstring float64 `json:"descriptionSubstring"`
+
+	// ExactWord, PluralWord, PrefixWord and PartialWord are added per query word for its best literal match
+	ExactWord   float64 `json:"exactWord"`
+	PluralWord  float64 `json:"pluralWord"`
+	PrefixWord  float64 `json:"prefixWord"`
+	PartialWord float64 `json:"partialWord"`
+
+	// ExactWordPositionBonus and PrefixWordPositionBonus are added per position a match is before the fourth
+	// description word, for exact and plural matches and for prefix matches respectively
+	ExactWordPositionBonus  float64 `json:"exactWordPositionBonus"`
+	PrefixWordPositionBonus float64 `json:"prefixWordPositionBonus"`
+
+	// SynonymWord is added for a query word without a literal match when one of its synonyms matches
+	SynonymWord float64 `json:"synonymWord"`
+
+	// FuzzyOneEdit and FuzzyTwoEdits are added for a fuzzy search's query word that is one or two edits
+	// from a description word
+	FuzzyOneEdit  float64 `json:"fuzzyOneEdit"`
+	FuzzyTwoEdits float64 `json:"fuzzyTwoEdits"`
+
+	// LongDescriptionPenalty multiplies the score of descriptions over 10 words that miss some query words
+	LongDescriptionPenalty float64 `json:"longDescriptionPenalty"`
+
+	// SimpleNameBoost multiplies the score of descriptions of up to 3 words whose first word contains a
+	// one-word query, so "Eggs, whole" outranks longer descriptions for "eggs"
+	SimpleNameBoost float64 `json:"simpleNameBoost"`
+}
+
+// DefaultScoringWeights are the weights searches use unless they are overridden
+var DefaultScoringWeights = ScoringWeights{
+	ExactDescription:        1000,
+	DescriptionPrefix:       500,
+	DescriptionSubstring:    100,
+	ExactWord:               50,
+	PluralWord:              45,
+	PrefixWord:              25,
+	PartialWord:             10,
+	ExactWordPositionBonus:  10,
+	PrefixWordPositionBonus: 5,
+	SynonymWord:             20,
+	FuzzyOneEdit:            20,
+	FuzzyTwoEdits:           12,
+	LongDescriptionPenalty:  0.8,
+	SimpleNameBoost:         1.5,
+}
+
+// LoadScoringWeights reads a JSON object of scoring weights. Weights the file leaves out keep their defaults.
+func LoadScoringWeights(path string) (ScoringWeights, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return ScoringWeights{}, fmt.Errorf("failed to read scoring weights file: %w", err)
+	}
+
+	weights := DefaultScoringWeights
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&weights); err != nil {
+		return ScoringWeights{}, fmt.Errorf("failed to parse scoring weights file: %w", err)
+	}
+
+	if err := weights.validate(); err != nil {
+		return ScoringWeights{}, err
+	}
+
+	return weights, nil
+}
+
+// validate rejects negative weights and multipliers that aren't positive
+func (w ScoringWeights) validate() error {
+	value := reflect.ValueOf(w)
+	for i := range value.NumField() {
+		if value.Field(i).Float() < 0 {
+			return fmt.Errorf("invalid scoring weight %s: must not be negative", value.Type().Field(i).Tag.Get("json"))
+		}
+	}
+	if w.LongDescriptionPenalty <= 0 || w.SimpleNameBoost <= 0 {
+		return fmt.Errorf("invalid scoring weights: longDescriptionPenalty and simpleNameBoost must be positive")
+	}
+	return nil
+}
+
+// WithScoringWeights replaces the default scoring weights
+func WithScoringWeights(weights ScoringWeights) EngineOption {
+	return func(e *Engine) {
+		e.weights = &weights
+	}
+}
+
+// WithScoringWeightsFile replaces the default scoring weights with those in a JSON file, like
+// WithScoringWeights. The file is read when the engine is created and again by RefreshNormalization.
+func WithScoringWeightsFile(path string) EngineOption {
+	return func(e *Engine) {
+		e.weightsFile = path
+	}
+}
+
+// loadScoringWeightsFile reads the engine's scoring weights file, or returns nil when none is configured
+func (e *Engine) loadScoringWeightsFile() (*ScoringWeights, error) {
+	if e.weightsFile == "" {
+		return nil, nil
+	}
+
+	weights, err := LoadScoringWeights(e.weightsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	e.logger.Info("Loaded scoring weights", "path", e.weightsFile)
+
+	return &weights, nil
+}
+
+// scoringWeights returns the engine's scoring weights, falling back to the defaults
+func (e *Engine) scoringWeights() ScoringWeights {
+	if e.weights == nil {
+		return DefaultScoringWeights
+	}
+	return *e.weights
+}
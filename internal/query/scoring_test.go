@@ -0,0 +1,141 @@
+package query
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScoringWeights_Injected(t *testing.T) {
+	score := func(weights ScoringWeights, description, query string) float64 {
+		normalizedQuery := normalizeString(query)
+		return weights.scoreDescription(normalizeString(description), normalizedQuery, []string{normalizedQuery}, false, nil, nil)
+	}
+
+	// Milk, whole: exact word at the first position (50 + 3×10), description prefix (500) and substring (100),
+	// boosted as a simple name (×1.5) and by the milk rule (×2)
+	assert.Equal(t, 680.0*1.5*2, score(DefaultScoringWeights, "Milk, whole", "milk"))
+
+	weights := DefaultScoringWeights
+	weights.DescriptionPrefix = 0
+	weights.ExactWordPositionBonus = 0
+	weights.SimpleNameBoost = 1
+	assert.Equal(t, 150.0*2, score(weights, "Milk, whole", "milk"))
+
+	t.Run("default weights keep calculateRelevanceScore unchanged", func(t *testing.T) {
+		normalizedQuery := normalizeString("chicken breast")
+		queryWords := []string{"chicken", "breast"}
+		description := "Chicken, breast, boneless, skinless, raw"
+
+		assert.Equal(t,
+			calculateRelevanceScore(description, normalizedQuery, queryWords),
+			DefaultScoringWeights.scoreDescription(normalizeString(description), normalizedQuery, queryWords, false, nil, nil))
+	})
+}
+
+func TestEngine_ScoringWeights(t *testing.T) {
+	engine := &Engine{
+		data: &FoundationFoodsData{
+			FoundationFoods: []FoundationFood{
+				{Description: "Cheddar cheese sauce", FdcId: 1},
+				{Description: "Sauce, cheddar", FdcId: 2},
+			},
+		},
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+
+	search := func() []int {
+		foods, err := engine.SearchFoodsByName(context.Background(), "cheddar", 10, SearchOptions{})
+		require.NoError(t, err)
+		ids := make([]int, len(foods))
+		for i, food := range foods {
+			ids[i] = food.FdcId
+		}
+		return ids
+	}
+
+	assert.Equal(t, []int{1, 2}, search())
+
+	// Without the prefix bonus, position no longer decides and the shorter description's boost wins
+	weights := DefaultScoringWeights
+	weights.DescriptionPrefix = 0
+	weights.ExactWordPositionBonus = 0
+	weights.SimpleNameBoost = 1
+	weights.LongDescriptionPenalty = 1
+	WithScoringWeights(weights)(engine)
+
+	scores, err := engine.SearchFoodsByName(context.Background(), "cheddar", 10, SearchOptions{IncludeScores: true})
+	require.NoError(t, err)
+	require.Len(t, scores, 2)
+	assert.Equal(t, *scores[0].Score, *scores[1].Score)
+}
+
+func TestEngine_RefreshNormalization_ScoringWeightsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "weights.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"exactDescription": 2000}`), 0o600))
+
+	engine := &Engine{
+		data: &FoundationFoodsData{
+			FoundationFoods: []FoundationFood{
+				{Description: "Cheese, cheddar", FdcId: 1},
+			},
+		},
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+	WithScoringWeightsFile(path)(engine)
+
+	ctx := context.Background()
+	confidence := func(t *testing.T) float64 {
+		t.Helper()
+		response, err := engine.ResolveFoods(ctx, []string{"cheddar"}, SearchOptions{})
+		require.NoError(t, err)
+		require.True(t, response.Foods[0].Found)
+		return response.Foods[0].Confidence
+	}
+
+	// "cheddar" scores 170: the substring bonus plus an exact second word
+	require.NoError(t, engine.RefreshNormalization(ctx))
+	assert.InDelta(t, 0.7*170.0/2000+0.3, confidence(t), 1e-9)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"exactDescription": 500}`), 0o600))
+	require.NoError(t, engine.RefreshNormalization(ctx))
+	assert.InDelta(t, 0.7*170.0/500+0.3, confidence(t), 1e-9)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"exactDescription": -1}`), 0o600))
+	assert.Error(t, engine.RefreshNormalization(ctx))
+	assert.Equal(t, 500.0, engine.scoringWeights().ExactDescription)
+}
+
+func TestLoadScoringWeights(t *testing.T) {
+	dir := t.TempDir()
+
+	partial := filepath.Join(dir, "weights.json")
+	require.NoError(t, os.WriteFile(partial, []byte(`{"descriptionPrefix": 400, "synonymWord": 15}`), 0o600))
+	weights, err := LoadScoringWeights(partial)
+	require.NoError(t, err)
+	expected := DefaultScoringWeights
+	expected.DescriptionPrefix = 400
+	expected.SynonymWord = 15
+	assert.Equal(t, expected, weights)
+
+	for name, content := range map[string]string{
+		"unknown.json":  `{"prefixBonus": 400}`,
+		"negative.json": `{"exactWord": -1}`,
+		"zero.json":     `{"simpleNameBoost": 0}`,
+		"invalid.json":  `{"exactWord": "high"}`,
+	} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		_, err := LoadScoringWeights(path)
+		assert.Error(t, err, name)
+	}
+
+	_, err = LoadScoringWeights(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,87 @@
+package query
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Search result orders for SearchOptions.SortBy
+const (
+	// SortByRelevance orders results by relevance score, highest first
+	SortByRelevance = "relevance"
+
+	// SortByDescription orders results alphabetically by description
+	SortByDescription = "description"
+
+	// SortByNutrientPrefix, followed by a nutrient name, orders results by that nutrient's amount per
+	// 100 g, highest first
+	SortByNutrientPrefix = "nutrient:"
+)
+
+// ValidateSortBy reports an error for unknown search result orders. An empty order means relevance.
+func ValidateSortBy(sortBy string) error {
+	switch {
+	case sortBy == "", sortBy == SortByRelevance, sortBy == SortByDescription:
+		return nil
+	case strings.HasPrefix(sortBy, SortByNutrientPrefix):
+		if sortByNutrient(sortBy) == "" {
+			return fmt.Errorf("sort order %q is missing a nutrient name", sortBy)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown sort order %q, expected %q, %q or %q followed by a nutrient name", sortBy, SortByRelevance, SortByDescription, SortByNutrientPrefix)
+	}
+}
+
+// sortByNutrient returns the nutrient name of a "nutrient:<name>" sort order, or "" for any other order
+func sortByNutrient(sortBy string) string {
+	name, ok := strings.CutPrefix(sortBy, SortByNutrientPrefix)
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(name)
+}
+
+// sortResults reorders scored search results by sortBy, leaving them in relevance order for relevance or
+// an empty order. Nutrient orders compare amounts after normalizing units like FindFoodsByNutrient, put
+// foods lacking the nutrient last and keep relevance order among ties. The caller must hold the read lock.
+func (e *Engine) sortResults(results []SearchResult, sortBy string) {
+	switch {
+	case sortBy == SortByDescription:
+		sort.SliceStable(results, func(i, j int) bool {
+			if results[i].Food.Description != results[j].Food.Description {
+				return results[i].Food.Description < results[j].Food.Description
+			}
+			return results[i].Food.FdcId < results[j].Food.FdcId
+		})
+	case sortByNutrient(sortBy) != "":
+		nutrientName := sortByNutrient(sortBy)
+		amounts := make(map[int]float64, len(results))
+		for i := range results {
+			if nutrient, ok := e.matchNutrient(&results[i].Food, nutrientName); ok {
+				amounts[i], _ = normalizeNutrientUnit(nutrient.Amount, nutrient.Nutrient.UnitName)
+			}
+		}
+
+		// Sort an index permutation so the amounts, keyed by original position, stay aligned
+		order := make([]int, len(results))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(i, j int) bool {
+			a, aok := amounts[order[i]]
+			b, bok := amounts[order[j]]
+			if aok != bok {
+				return aok
+			}
+			return aok && a > b
+		})
+
+		sorted := make([]SearchResult, len(results))
+		for i, index := range order {
+			sorted[i] = results[index]
+		}
+		copy(results, sorted)
+	}
+}
@@ -0,0 +1,90 @@
+package query
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSortBy(t *testing.T) {
+	for _, sortBy := range []string{"", SortByRelevance, SortByDescription, "nutrient:Protein", "nutrient: Vitamin C"} {
+		assert.NoError(t, ValidateSortBy(sortBy), sortBy)
+	}
+	for _, sortBy := range []string{"score", "Description", "nutrient:", "nutrient:  "} {
+		assert.Error(t, ValidateSortBy(sortBy), sortBy)
+	}
+}
+
+func TestEngine_SearchFoodsByName_SortBy(t *testing.T) {
+	engine := &Engine{
+		data: &FoundationFoodsData{
+			FoundationFoods: []FoundationFood{
+				{
+					Description: "Beans",
+					FdcId:       1,
+					FoodNutrients: []FoodNutrient{
+						{Nutrient: Nutrient{Name: "Protein", UnitName: "g"}, Amount: 21},
+					},
+				},
+				{
+					Description: "Beans, snap, green",
+					FdcId:       2,
+					FoodNutrients: []FoodNutrient{
+						{Nutrient: Nutrient{Name: "Protein", UnitName: "g"}, Amount: 1.8},
+					},
+				},
+				{
+					Description: "Beans, black, canned",
+					FdcId:       3,
+				},
+				{
+					Description: "Beans, adzuki, mature seeds",
+					FdcId:       4,
+					FoodNutrients: []FoodNutrient{
+						// 25000 mg normalizes above the 21 g of plain beans
+						{Nutrient: Nutrient{Name: "Protein", UnitName: "mg"}, Amount: 25000},
+					},
+				},
+			},
+		},
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+
+	ids := func(t *testing.T, limit int, opts SearchOptions) []int {
+		t.Helper()
+		foods, err := engine.SearchFoodsByName(context.Background(), "beans", limit, opts)
+		require.NoError(t, err)
+
+		ids := make([]int, 0, len(foods))
+		for _, food := range foods {
+			ids = append(ids, food.FdcId)
+		}
+		return ids
+	}
+
+	relevance := ids(t, 10, SearchOptions{})
+	require.Equal(t, 1, relevance[0], "the exact description should rank first by relevance")
+	assert.Equal(t, relevance, ids(t, 10, SearchOptions{SortBy: SortByRelevance}))
+
+	t.Run("description", func(t *testing.T) {
+		assert.Equal(t, []int{1, 4, 3, 2}, ids(t, 10, SearchOptions{SortBy: SortByDescription}))
+	})
+
+	t.Run("nutrient with missing values last", func(t *testing.T) {
+		assert.Equal(t, []int{4, 1, 2, 3}, ids(t, 10, SearchOptions{SortBy: "nutrient:protein"}))
+	})
+
+	t.Run("sorts every match before the limit and offset", func(t *testing.T) {
+		assert.Equal(t, []int{4, 1}, ids(t, 2, SearchOptions{SortBy: "nutrient:Protein"}))
+		assert.Equal(t, []int{2, 3}, ids(t, 2, SearchOptions{SortBy: "nutrient:Protein", Offset: 2}))
+	})
+
+	t.Run("unknown order", func(t *testing.T) {
+		_, err := engine.SearchFoodsByName(context.Background(), "beans", 10, SearchOptions{SortBy: "calories"})
+		assert.Error(t, err)
+	})
+}
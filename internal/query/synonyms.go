@@ -0,0 +1,156 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"strings"
+)
+
+// DefaultSynonyms maps words users commonly search for to the terms the dataset describes foods with.
+// Keys are single words; values may be phrases, which match when all of their words are in a description.
+var DefaultSynonyms = map[string][]string{
+	"maize":     {"corn"},
+	"aubergine": {"eggplant"},
+	"courgette": {"zucchini"},
+	"rocket":    {"arugula"},
+	"capsicum":  {"bell pepper"},
+	"prawn":     {"shrimp"},
+	"hamburger": {"ground beef"},
+	"catsup":    {"ketchup"},
+	"hotdog":    {"frankfurter"},
+	"wiener":    {"frankfurter"},
+	"groundnut": {"peanut"},
+	"yam":       {"sweet potato"},
+	"garbanzo":  {"chickpea"},
+	"scallion":  {"green onion"},
+	"cilantro":  {"coriander"},
+	"soda":      {"carbonated beverage", "soft drink"},
+}
+
+// LoadSynonyms reads a JSON object mapping single search words to the words or phrases they should also match
+func LoadSynonyms(path string) (map[string][]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read synonyms file: %w", err)
+	}
+
+	var synonyms map[string][]string
+	if err := json.Unmarshal(content, &synonyms); err != nil {
+		return nil, fmt.Errorf("failed to parse synonyms file: %w", err)
+	}
+
+	for word, phrases := range synonyms {
+		if len(strings.Fields(normalizeString(word))) != 1 {
+			return nil, fmt.Errorf("invalid synonym key %q: keys must be a single word", word)
+		}
+		for _, phrase := range phrases {
+			if normalizeString(phrase) == "" {
+				return nil, fmt.Errorf("invalid synonym for %q: synonyms can't be empty", word)
+			}
+		}
+	}
+
+	return synonyms, nil
+}
+
+// WithSynonyms adds to or replaces, by word, the built-in synonyms
+func WithSynonyms(synonyms map[string][]string) EngineOption {
+	return func(e *Engine) {
+		e.synonyms = mergedSynonymTable(synonyms)
+	}
+}
+
+// WithSynonymsFile adds the synonyms in a JSON file to the built-in ones like WithSynonyms. The file is read
+// when the engine is created and again by RefreshNormalization, so edits apply without a restart.
+func WithSynonymsFile(path string) EngineOption {
+	return func(e *Engine) {
+		e.synonymsFile = path
+	}
+}
+
+// loadSynonymsFile reads the engine's synonyms file merged over the built-in synonyms, or returns nil when
+// no file is configured
+func (e *Engine) loadSynonymsFile() (map[string][][]string, error) {
+	if e.synonymsFile == "" {
+		return nil, nil
+	}
+
+	synonyms, err := LoadSynonyms(e.synonymsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	e.logger.Info("Loaded search synonyms",
+		"path", e.synonymsFile,
+		"count", len(synonyms))
+
+	return mergedSynonymTable(synonyms), nil
+}
+
+// mergedSynonymTable indexes synonyms added to or replacing, by word, the built-in synonyms
+func mergedSynonymTable(synonyms map[string][]string) map[string][][]string {
+	merged := maps.Clone(DefaultSynonyms)
+	maps.Copy(merged, synonyms)
+	return synonymTable(merged)
+}
+
+// defaultSynonymTable is the built-in synonyms, indexed once
+var defaultSynonymTable = synonymTable(DefaultSynonyms)
+
+// synonymTable indexes synonyms by normalized word, splitting each synonym phrase into normalized words.
+// Single-word synonyms also map back to their key, so "corn" finds "maize" as well as the other way round.
+func synonymTable(synonyms map[string][]string) map[string][][]string {
+	table := make(map[string][][]string, len(synonyms))
+	for word, phrases := range synonyms {
+		word = normalizeString(word)
+		for _, phrase := range phrases {
+			phraseWords := strings.Fields(normalizeString(phrase))
+			if len(phraseWords) == 0 {
+				continue
+			}
+			table[word] = append(table[word], phraseWords)
+			if len(phraseWords) == 1 {
+				table[phraseWords[0]] = append(table[phraseWords[0]], []string{word})
+			}
+		}
+	}
+	return table
+}
+
+// synonymTable returns the engine's synonyms, falling back to the built-in ones
+func (e *Engine) synonymTable() map[string][][]string {
+	if e.synonyms == nil {
+		return defaultSynonymTable
+	}
+	return e.synonyms
+}
+
+// matchesSynonym reports whether any synonym phrase of a query word has all of its words in the description.
+// Description words match a phrase word exactly or as its singular or plural.
+func matchesSynonym(phrases [][]string, descWords []string) bool {
+	for _, phrase := range phrases {
+		if containsAllWords(descWords, phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsAllWords reports whether every word is among descWords, allowing singular/plural variants
+func containsAllWords(descWords, words []string) bool {
+	for _, word := range words {
+		found := false
+		for _, descWord := range descWords {
+			if descWord == word || pluralEquivalent(descWord, word) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,130 @@
+package query
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_SearchSynonyms(t *testing.T) {
+	newEngine := func(opts ...EngineOption) *Engine {
+		engine := &Engine{
+			data: &FoundationFoodsData{
+				FoundationFoods: []FoundationFood{
+					{Description: "Eggplant, raw", FdcId: 1},
+					{Description: "Beef, ground, 80% lean meat / 20% fat, raw", FdcId: 2},
+					{Description: "Corn, sweet, yellow and white kernels, fresh, raw", FdcId: 3},
+					{Description: "Aubergine dip", FdcId: 4},
+					{Description: "Peppers, bell, green, raw", FdcId: 5},
+				},
+			},
+			logger: config.NewTestLogger(io.Discard, "debug"),
+		}
+		for _, opt := range opts {
+			opt(engine)
+		}
+		return engine
+	}
+
+	search := func(t *testing.T, engine *Engine, query string) []int {
+		t.Helper()
+		foods, err := engine.SearchFoodsByName(context.Background(), query, 10, SearchOptions{})
+		require.NoError(t, err)
+		ids := make([]int, len(foods))
+		for i, food := range foods {
+			ids[i] = food.FdcId
+		}
+		return ids
+	}
+
+	t.Run("built-in synonyms match the dataset's terms", func(t *testing.T) {
+		engine := newEngine()
+
+		assert.Equal(t, []int{2}, search(t, engine, "hamburger"))
+		assert.Equal(t, []int{3}, search(t, engine, "maize"))
+		assert.Equal(t, []int{5}, search(t, engine, "capsicum"))
+	})
+
+	t.Run("literal matches outrank synonym matches", func(t *testing.T) {
+		assert.Equal(t, []int{4, 1}, search(t, newEngine(), "aubergine"))
+	})
+
+	t.Run("single-word synonyms work both ways", func(t *testing.T) {
+		assert.Equal(t, []int{1, 4}, search(t, newEngine(), "eggplant"))
+	})
+
+	t.Run("configured synonyms add to the built-in ones", func(t *testing.T) {
+		engine := newEngine(WithSynonyms(map[string][]string{"brinjal": {"eggplant"}}))
+
+		assert.Equal(t, []int{1}, search(t, engine, "brinjal"))
+		assert.Equal(t, []int{2}, search(t, engine, "hamburger"))
+	})
+}
+
+func TestEngine_RefreshNormalization_SynonymsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "synonyms.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"brinjal": ["eggplant"]}`), 0o600))
+
+	engine := &Engine{
+		data: &FoundationFoodsData{
+			FoundationFoods: []FoundationFood{
+				{Description: "Eggplant, raw", FdcId: 1},
+			},
+		},
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+	WithSynonymsFile(path)(engine)
+
+	ctx := context.Background()
+	count := func(t *testing.T, query string) int {
+		t.Helper()
+		foods, err := engine.SearchFoodsByName(ctx, query, 10, SearchOptions{})
+		require.NoError(t, err)
+		return len(foods)
+	}
+
+	require.NoError(t, engine.RefreshNormalization(ctx))
+	assert.Equal(t, 1, count(t, "brinjal"))
+	assert.Equal(t, 0, count(t, "melanzane"))
+
+	// An edited file applies on the next refresh
+	require.NoError(t, os.WriteFile(path, []byte(`{"melanzane": ["eggplant"]}`), 0o600))
+	require.NoError(t, engine.RefreshNormalization(ctx))
+	assert.Equal(t, 0, count(t, "brinjal"))
+	assert.Equal(t, 1, count(t, "melanzane"))
+	assert.Equal(t, 1, count(t, "aubergine"), "built-in synonyms stay")
+
+	// A broken file fails the refresh and keeps the synonyms in use
+	require.NoError(t, os.WriteFile(path, []byte(`{"melanzane": `), 0o600))
+	assert.Error(t, engine.RefreshNormalization(ctx))
+	assert.Equal(t, 1, count(t, "melanzane"))
+}
+
+func TestLoadSynonyms(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "synonyms.json")
+	require.NoError(t, os.WriteFile(valid, []byte(`{"brinjal": ["eggplant"], "soda": ["soft drink"]}`), 0o600))
+	synonyms, err := LoadSynonyms(valid)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"brinjal": {"eggplant"}, "soda": {"soft drink"}}, synonyms)
+
+	multiWordKey := filepath.Join(dir, "multiword.json")
+	require.NoError(t, os.WriteFile(multiWordKey, []byte(`{"soft drink": ["soda"]}`), 0o600))
+	_, err = LoadSynonyms(multiWordKey)
+	assert.Error(t, err)
+
+	emptySynonym := filepath.Join(dir, "empty.json")
+	require.NoError(t, os.WriteFile(emptySynonym, []byte(`{"soda": [" "]}`), 0o600))
+	_, err = LoadSynonyms(emptySynonym)
+	assert.Error(t, err)
+
+	_, err = LoadSynonyms(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
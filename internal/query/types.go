@@ -24,19 +24,44 @@ type FoundationFood struct {
 	FoodPortions              []FoodPortion  `json:"foodPortions"`
 	PublicationDate           string         `json:"publicationDate"`
 	InputFoods                []InputFood    `json:"inputFoods"`
+
+	// ServingPortion is set when nutrient amounts have been scaled to this portion instead of per 100 g
+	ServingPortion *FoodPortion `json:"servingPortion,omitempty"`
+
+	// FuzzyFallback is set when the food didn't match the query and was returned as a nearest fuzzy match
+	FuzzyFallback bool `json:"fuzzyFallback,omitempty"`
+
+	// Score is the search relevance score, set when scores were requested (an exact match scores 1000 or more)
+	Score *float64 `json:"score,omitempty"`
+
+	// Highlights says where each query word matched the description, set when highlights were requested
+	Highlights []MatchHighlight `json:"highlights,omitempty"`
+}
+
+// MatchHighlight is a query word's match in a food description. Start and End are character offsets into
+// the description, End exclusive, so description[Start:End] in characters is the matched word.
+type MatchHighlight struct {
+	// Word is the normalized query word
+	Word string `json:"word"`
+
+	// Match is how it matched: exact, plural, prefix, partial, synonym or fuzzy
+	Match string `json:"match"`
+
+	Start int `json:"start"`
+	End   int `json:"end"`
 }
 
 // FoodNutrient represents nutritional information for a food item
 type FoodNutrient struct {
-	Type                   string                 `json:"type"`
-	Id                     int                    `json:"id"`
-	Nutrient               Nutrient               `json:"nutrient"`
-	DataPoints             int                    `json:"dataPoints,omitempty"`
-	FoodNutrientDerivation FoodNutrientDerivation `json:"foodNutrientDerivation"`
-	Max                    float64                `json:"max,omitempty"`
-	Min                    float64                `json:"min,omitempty"`
-	Median                 float64                `json:"median,omitempty"`
-	Amount                 float64                `json:"amount"`
+	Type                   string                  `json:"type"`
+	Id                     int                     `json:"id"`
+	Nutrient               Nutrient                `json:"nutrient"`
+	DataPoints             int                     `json:"dataPoints,omitempty"`
+	FoodNutrientDerivation *FoodNutrientDerivation `json:"foodNutrientDerivation,omitempty"`
+	Max                    float64                 `json:"max,omitempty"`
+	Min                    float64                 `json:"min,omitempty"`
+	Median                 float64                 `json:"median,omitempty"`
+	Amount                 float64                 `json:"amount"`
 }
 
 // Nutrient represents a specific nutrient
@@ -50,9 +75,9 @@ type Nutrient struct {
 
 // FoodNutrientDerivation represents how a nutrient value was derived
 type FoodNutrientDerivation struct {
-	Code               string             `json:"code"`
-	Description        string             `json:"description"`
-	FoodNutrientSource FoodNutrientSource `json:"foodNutrientSource"`
+	Code               string              `json:"code"`
+	Description        string              `json:"description"`
+	FoodNutrientSource *FoodNutrientSource `json:"foodNutrientSource,omitempty"`
 }
 
 // FoodNutrientSource represents the source of nutrient data
@@ -109,6 +134,90 @@ type SearchProductsResponse struct {
 	Found    bool             `json:"found"`
 	Count    int              `json:"count"`
 	Products []FoundationFood `json:"products"`
+
+	// Total is the number of foods matching the query across all pages
+	Total int `json:"total"`
+
+	// NextCursor, when set, fetches the next page of results when passed back as the cursor argument
+	NextCursor string `json:"nextCursor,omitempty"`
+
+	// PartialDueToBudget is set when the search ran out of its response budget and returns the best matches found so far
+	PartialDueToBudget bool `json:"partialDueToBudget,omitempty"`
+}
+
+// SearchOptions holds optional filters applied to food searches
+type SearchOptions struct {
+	// Category restricts results to foods whose category description matches case-insensitively
+	Category string
+
+	// Offset skips this many ranked results before the limit is applied, for paging
+	Offset int
+
+	// ReferenceFdcId, when set, adds each nutrient's ratio to the same nutrient in this reference food
+	ReferenceFdcId int
+
+	// IncludeSequence surfaces each portion's USDA sequence number in simplified responses
+	IncludeSequence bool
+
+	// IncludeDerivation attaches each nutrient's derivation code and description in simplified responses
+	IncludeDerivation bool
+
+	// EnergyUnit selects which Energy entry simplified responses keep: EnergyUnitKcal (the default when
+	// empty), EnergyUnitKJ or EnergyUnitBoth
+	EnergyUnit string
+
+	// MergeDuplicateNutrients collapses same-name nutrient entries in simplified responses using MergeStrategy
+	MergeDuplicateNutrients bool
+	MergeStrategy           string
+
+	// NotableOnly keeps only nutrients where the food ranks at or above NotablePercentile across the dataset
+	NotableOnly bool
+
+	// NotablePercentile is the 0-100 percentile rank threshold for NotableOnly (0 uses DefaultNotablePercentile)
+	NotablePercentile float64
+
+	// Fuzzy lets query words match description words within a typo or two, such as "brocolli" for "broccoli"
+	Fuzzy bool
+
+	// Preparation boosts raw or cooked foods and demotes the opposite form (PreparationAny or empty ranks as usual)
+	Preparation string
+
+	// MinScore drops matches whose relevance score is below it before any limit is applied (0 keeps every match)
+	MinScore float64
+
+	// SortBy orders matches before the offset and limit are applied: SortByRelevance (the default when
+	// empty), SortByDescription, or SortByNutrientPrefix followed by a nutrient name
+	SortBy string
+
+	// IncludeScores sets each search result's relevance Score
+	IncludeScores bool
+
+	// Exact only matches foods whose normalized description equals the normalized query, without relevance
+	// scoring, fuzzy matching or nearest-match fallbacks. Matches all get the ExactDescription score.
+	Exact bool
+
+	// IncludeHighlights sets each search result's Highlights
+	IncludeHighlights bool
+
+	// ReturnNearestOnEmpty returns the most similar foods by edit distance, flagged FuzzyFallback,
+	// when no food scores above zero
+	ReturnNearestOnEmpty bool
+}
+
+// notablePercentile returns the configured notable threshold or the default
+func (o SearchOptions) notablePercentile() float64 {
+	if o.NotablePercentile <= 0 {
+		return DefaultNotablePercentile
+	}
+	return o.NotablePercentile
+}
+
+// SearchCacheStats reports how full the search result cache is and how often it was used
+type SearchCacheStats struct {
+	Size     int   `json:"size"`
+	Capacity int   `json:"capacity"`
+	Hits     int64 `json:"hits"`
+	Misses   int64 `json:"misses"`
 }
 
 // SearchResult represents a single search result with relevance score
@@ -120,16 +229,110 @@ type SearchResult struct {
 // QueryEngine defines the interface for querying Foundation Foods data
 type QueryEngine interface {
 	// SearchFoodsByName searches for foods by their description/name
-	SearchFoodsByName(ctx context.Context, query string, limit int) ([]FoundationFood, error)
+	SearchFoodsByName(ctx context.Context, query string, limit int, opts SearchOptions) ([]FoundationFood, error)
+
+	// SearchFoodsByNameWithTotal searches like SearchFoodsByName and also returns how many foods match
+	// across all pages
+	SearchFoodsByNameWithTotal(ctx context.Context, query string, limit int, opts SearchOptions) ([]FoundationFood, int, error)
 
 	// SearchFoodsByNameSimplified searches for foods and returns simplified nutrient information
-	SearchFoodsByNameSimplified(ctx context.Context, query string, limit int, nutrientsToInclude []string) (*SimplifiedNutrientResponse, error)
+	SearchFoodsByNameSimplified(ctx context.Context, query string, limit int, nutrientsToInclude []string, opts SearchOptions) (*SimplifiedNutrientResponse, error)
 
 	// GetFoodByFdcId retrieves a specific food by its FDC ID
 	GetFoodByFdcId(ctx context.Context, fdcId int) (*FoundationFood, error)
 
+	// GetFoodsByFdcIds retrieves several foods by FDC ID in one pass, listing the IDs that matched nothing
+	GetFoodsByFdcIds(ctx context.Context, fdcIds []int) (*FoodsByFdcIdsResponse, error)
+
+	// GetFoodByNdbNumber retrieves every food with a legacy NDB number
+	GetFoodByNdbNumber(ctx context.Context, ndbNumber int) (*NdbNumberLookupResponse, error)
+
+	// ResolveFoods finds the single best-matching food for each of the given names
+	ResolveFoods(ctx context.Context, names []string, opts SearchOptions) (*ResolveFoodsResponse, error)
+
+	// CanonicalizeFoodName returns the exact USDA description of the best match for a loose name
+	CanonicalizeFoodName(ctx context.Context, name string, minConfidence float64, opts SearchOptions) (*ResolvedFood, error)
+
+	// CompareFoodToCategory compares a food's nutrients against the averages of its category
+	CompareFoodToCategory(ctx context.Context, fdcId int) (*FoodVsCategoryResponse, error)
+
+	// NutrientsForHouseholdPortion returns a food's nutrients scaled to a household measure like "2 cups"
+	NutrientsForHouseholdPortion(ctx context.Context, fdcId int, measure string) (*HouseholdPortionResponse, error)
+
+	// NutrientsWithUpperLimits returns a food's nutrients for a serving annotated with their upper intake limits
+	NutrientsWithUpperLimits(ctx context.Context, fdcId int, measure string) (*UpperLimitsResponse, error)
+
+	// DailyIntakeSummary totals a day's foods' nutrients against a demographic's daily targets and upper limits
+	DailyIntakeSummary(ctx context.Context, entries []IntakeEntry, demographic string, opts SearchOptions) (*DailyIntakeSummaryResponse, error)
+
+	// NetCarbs returns a food's total carbohydrate minus fiber for a serving
+	NetCarbs(ctx context.Context, fdcId int, measure string, subtractSugarAlcohols bool) (*NetCarbsResponse, error)
+
+	// SearchWithAlternatives returns the best match plus scored, explained alternatives
+	SearchWithAlternatives(ctx context.Context, query string, alternatives int, opts SearchOptions) (*SearchWithAlternativesResponse, error)
+
+	// ExplainFoodSearch returns the step-by-step relevance score of one description, or one food by FDC ID, for a query
+	ExplainFoodSearch(ctx context.Context, query, description string, fdcId int, opts SearchOptions) (*SearchExplanation, error)
+
+	// MacroPercentages returns the best match's protein, fat and carb shares of macronutrient calories
+	MacroPercentages(ctx context.Context, name string, opts SearchOptions) (*MacroPercentagesResponse, error)
+
+	// ListUnits returns the distinct nutrient and portion units in the dataset with usage counts
+	ListUnits(ctx context.Context) (*UnitListResponse, error)
+
+	// DatasetInfo describes the loaded dataset's source file, release, size and latest publication date
+	DatasetInfo(ctx context.Context) (*DatasetInfoResponse, error)
+
+	// NutrientVectors returns matching foods' nutrient amounts as fixed-order numeric vectors
+	NutrientVectors(ctx context.Context, name string, limit int, nutrientNames []string, opts SearchOptions) (*NutrientVectorsResponse, error)
+
+	// NutrientsPer200Kcal returns the best match's nutrients scaled to 200 kcal
+	NutrientsPer200Kcal(ctx context.Context, name string, opts SearchOptions) (*EnergyBasisResponse, error)
+
+	// GetFoodWithInputs returns a food with its input foods expanded recursively
+	GetFoodWithInputs(ctx context.Context, fdcId int, depth int) (*FoodWithInputsResponse, error)
+
+	// GetFoodInputFoods returns the FDC IDs and descriptions of a food's direct input foods
+	GetFoodInputFoods(ctx context.Context, fdcId int) (*FoodInputFoodsResponse, error)
+
+	// NutrientHistogram returns the distribution of a nutrient's amount across foods
+	NutrientHistogram(ctx context.Context, nutrientName string, buckets int, opts SearchOptions) (*NutrientHistogramResponse, error)
+
+	// RankByProteinDensity returns the foods with the most grams of protein per 100 kcal
+	RankByProteinDensity(ctx context.Context, limit int, opts SearchOptions) (*ProteinDensityResponse, error)
+
+	// FindFoodsByNutrient returns the foods with the highest amount of a nutrient per 100 g
+	FindFoodsByNutrient(ctx context.Context, nutrientName string, limit int, opts SearchOptions) (*NutrientRankingResponse, error)
+
+	// FindFoodsByNutrientRange returns the foods whose amount of a nutrient per 100 g is within a range
+	FindFoodsByNutrientRange(ctx context.Context, nutrientName string, minAmount, maxAmount *float64, limit int, opts SearchOptions) (*NutrientRangeResponse, error)
+
+	// MultiNutrientSources returns the foods that rank highly in every one of several nutrients
+	MultiNutrientSources(ctx context.Context, nutrientNames []string, percentile float64, limit int, opts SearchOptions) (*MultiNutrientSourcesResponse, error)
+
+	// RankFoodsByNutrients ranks foods by a weighted sum of their dataset-normalized nutrient amounts
+	RankFoodsByNutrients(ctx context.Context, nutrientNames []string, weights []float64, limit int, opts SearchOptions) (*CompositeRankingResponse, error)
+
+	// NutrientHistory returns a food's nutrient amount in every loaded dataset release
+	NutrientHistory(ctx context.Context, fdcId int, nutrientName string) (*NutrientHistoryResponse, error)
+
+	// TopNutrientDifferences returns the nutrients that differ most between two foods
+	TopNutrientDifferences(ctx context.Context, fdcIdA, fdcIdB int, limit int, rankBy string) (*NutrientDifferencesResponse, error)
+
+	// CompareFoods returns foods side by side, one row per nutrient
+	CompareFoods(ctx context.Context, fdcIds []int, nutrientNames []string) (*CompareFoodsResponse, error)
+
+	// FindFoodsContainingIngredient finds foods whose input foods match an ingredient
+	FindFoodsContainingIngredient(ctx context.Context, ingredient string, limit int, opts SearchOptions) (*IngredientSearchResponse, error)
+
+	// RefreshNormalization rebuilds precomputed normalized forms and clears derived caches
+	RefreshNormalization(ctx context.Context) error
+
 	// Health checks if the query engine is ready and operational
 	Health(ctx context.Context) error
+
+	// SearchCacheStats reports the search result cache's size and hit counters
+	SearchCacheStats() SearchCacheStats
 }
 
 // SimplifiedNutrient represents a nutrient with only essential information
@@ -138,6 +341,19 @@ type SimplifiedNutrient struct {
 	Unit       string  `json:"unit"`
 	Amount     float64 `json:"amount"`
 	DataPoints int     `json:"dataPoints"`
+
+	// RelativeToReference is the ratio of this amount to the reference food's amount, when requested
+	RelativeToReference *float64 `json:"relativeToReference,omitempty"`
+
+	// Derivation says how the amount was obtained (e.g. analytical or calculated), when requested
+	Derivation *NutrientDerivation `json:"derivation,omitempty"`
+}
+
+// NutrientDerivation is the USDA derivation code and description of a nutrient amount, such as A (Analytical)
+// or NC (Calculated)
+type NutrientDerivation struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
 }
 
 // SimplifiedMeasureUnit represents a simplified measure unit
@@ -153,20 +369,545 @@ type SimplifiedFoodPortion struct {
 	Modifier    string                `json:"modifier,omitempty"`
 	GramWeight  float64               `json:"gramWeight"`
 	Amount      float64               `json:"amount"`
+
+	// SequenceNumber is USDA's intended display order, only included on request
+	SequenceNumber int `json:"sequenceNumber,omitempty"`
 }
 
 // SimplifiedFood represents a food item with simplified nutrient information
 type SimplifiedFood struct {
 	Name         string                  `json:"name"`
+	FdcId        int                     `json:"fdcId"`
 	Nutrients    []SimplifiedNutrient    `json:"nutrients"`
 	FoodPortions []SimplifiedFoodPortion `json:"foodPortions"`
+
+	// Basis is what the nutrient amounts refer to, such as "100 g" or "1 cup (244 g)"; set when a portion was requested
+	Basis string `json:"basis,omitempty"`
+
+	// FuzzyFallback is set when the food didn't match the query and was returned as a nearest fuzzy match
+	FuzzyFallback bool `json:"fuzzyFallback,omitempty"`
+}
+
+// ReferenceFood identifies the food that relative nutrient ratios are computed against
+type ReferenceFood struct {
+	FdcId       int    `json:"fdcId"`
+	Description string `json:"description"`
 }
 
 // SimplifiedNutrientResponse represents the response for simplified nutrient searches
 type SimplifiedNutrientResponse struct {
-	Found bool             `json:"found"`
-	Count int              `json:"count"`
-	Foods []SimplifiedFood `json:"foods"`
+	Found     bool             `json:"found"`
+	Count     int              `json:"count"`
+	Foods     []SimplifiedFood `json:"foods"`
+	Reference *ReferenceFood   `json:"reference,omitempty"`
+
+	// UnmatchedFilters lists nutrients_to_include entries that matched no nutrient of any returned food, often typos
+	UnmatchedFilters []string `json:"unmatchedFilters,omitempty"`
+
+	// PartialDueToBudget is set when the search ran out of its response budget and returns the best matches found so far
+	PartialDueToBudget bool `json:"partialDueToBudget,omitempty"`
+}
+
+// NutrientCategoryDifference represents how a single nutrient of a food deviates from its category mean
+type NutrientCategoryDifference struct {
+	Name              string  `json:"name"`
+	Unit              string  `json:"unit"`
+	Amount            float64 `json:"amount"`
+	CategoryMean      float64 `json:"categoryMean"`
+	Difference        float64 `json:"difference"`
+	PercentDifference float64 `json:"percentDifference"`
+	CategoryFoodCount int     `json:"categoryFoodCount"`
+}
+
+// FoodVsCategoryResponse represents the response for comparing a food against its category averages
+type FoodVsCategoryResponse struct {
+	FdcId       int                          `json:"fdcId"`
+	Description string                       `json:"description"`
+	Category    string                       `json:"category"`
+	Nutrients   []NutrientCategoryDifference `json:"nutrients"`
+	Page        *PageInfo                    `json:"page,omitempty"`
+}
+
+// ResolvedFood represents the best match for a single name, with null fields when nothing matched
+type ResolvedFood struct {
+	Query       string  `json:"query"`
+	Found       bool    `json:"found"`
+	FdcId       *int    `json:"fdcId"`
+	Description *string `json:"description"`
+	Confidence  float64 `json:"confidence"`
+}
+
+// ScoredMatch is a search match with its relevance score and a short note on why it matched
+type ScoredMatch struct {
+	FdcId       int     `json:"fdcId"`
+	Description string  `json:"description"`
+	Category    string  `json:"category"`
+	Score       float64 `json:"score"`
+	Why         string  `json:"why"`
+}
+
+// SearchWithAlternativesResponse represents the best match for a query and its runner-up alternatives
+type SearchWithAlternativesResponse struct {
+	Query        string        `json:"query"`
+	Found        bool          `json:"found"`
+	BestMatch    *ScoredMatch  `json:"bestMatch"`
+	Alternatives []ScoredMatch `json:"alternatives"`
+}
+
+// WordScore represents how one query word matched a description during relevance scoring
+type WordScore struct {
+	Word            string  `json:"word"`
+	Match           string  `json:"match,omitempty"`
+	DescriptionWord string  `json:"descriptionWord,omitempty"`
+	Points          float64 `json:"points"`
+}
+
+// ScoreStep represents one contribution to a relevance score: Points added or a Factor multiplied in,
+// with the running Score after it
+type ScoreStep struct {
+	Step   string  `json:"step"`
+	Points float64 `json:"points,omitempty"`
+	Factor float64 `json:"factor,omitempty"`
+	Score  float64 `json:"score"`
+}
+
+// ScoreBreakdown records the word matches and steps that produced a relevance score
+type ScoreBreakdown struct {
+	Words []WordScore `json:"words"`
+	Steps []ScoreStep `json:"steps"`
+}
+
+// SearchExplanation represents the relevance score of one description for a query, step by step
+type SearchExplanation struct {
+	Query                 string      `json:"query"`
+	NormalizedQuery       string      `json:"normalizedQuery"`
+	FdcId                 int         `json:"fdcId,omitempty"`
+	Description           string      `json:"description"`
+	NormalizedDescription string      `json:"normalizedDescription"`
+	Matched               bool        `json:"matched"`
+	Score                 float64     `json:"score"`
+	Words                 []WordScore `json:"words"`
+	Steps                 []ScoreStep `json:"steps"`
+}
+
+// ResolveFoodsResponse represents the response for resolving a list of food names
+type ResolveFoodsResponse struct {
+	Count    int            `json:"count"`
+	Resolved int            `json:"resolved"`
+	Foods    []ResolvedFood `json:"foods"`
+}
+
+// IngredientMatch represents a food that lists a matching input food
+type IngredientMatch struct {
+	FdcId             int    `json:"fdcId"`
+	Description       string `json:"description"`
+	Category          string `json:"category"`
+	MatchedIngredient string `json:"matchedIngredient"`
+}
+
+// IngredientSearchResponse represents the response for an ingredient search
+type IngredientSearchResponse struct {
+	Ingredient string            `json:"ingredient"`
+	Found      bool              `json:"found"`
+	Count      int               `json:"count"`
+	Foods      []IngredientMatch `json:"foods"`
+	Page       *PageInfo         `json:"page,omitempty"`
+}
+
+// FoodSummary identifies a food by FDC ID and description
+type FoodSummary struct {
+	FdcId       int    `json:"fdcId"`
+	Description string `json:"description"`
+}
+
+// NutrientAmount is a food's amount of a nutrient per 100 g
+type NutrientAmount struct {
+	Amount float64 `json:"amount"`
+	Unit   string  `json:"unit"`
+}
+
+// NutrientComparison is one nutrient's row of a side-by-side comparison. Amounts parallels the compared
+// foods, with null where a food lacks the nutrient.
+type NutrientComparison struct {
+	Nutrient string            `json:"nutrient"`
+	Amounts  []*NutrientAmount `json:"amounts"`
+}
+
+// CompareFoodsResponse represents foods side by side, one row per nutrient
+type CompareFoodsResponse struct {
+	Foods            []FoodSummary        `json:"foods"`
+	Nutrients        []NutrientComparison `json:"nutrients"`
+	UnmatchedFilters []string             `json:"unmatchedFilters,omitempty"`
+}
+
+// NutrientDifference represents how a nutrient differs between two foods, in unit-normalized amounts
+type NutrientDifference struct {
+	Name              string  `json:"name"`
+	Unit              string  `json:"unit"`
+	AmountA           float64 `json:"amountA"`
+	AmountB           float64 `json:"amountB"`
+	Difference        float64 `json:"difference"`
+	PercentDifference float64 `json:"percentDifference"`
+}
+
+// NutrientDifferencesResponse represents the nutrients that differ most between two foods
+type NutrientDifferencesResponse struct {
+	FoodA     FoodSummary          `json:"foodA"`
+	FoodB     FoodSummary          `json:"foodB"`
+	RankBy    string               `json:"rankBy"`
+	Count     int                  `json:"count"`
+	Nutrients []NutrientDifference `json:"nutrients"`
+	Page      *PageInfo            `json:"page,omitempty"`
+}
+
+// HouseholdPortionResponse represents a food's nutrients scaled to a household measure
+type HouseholdPortionResponse struct {
+	FdcId       int                  `json:"fdcId"`
+	Description string               `json:"description"`
+	Measure     string               `json:"measure"`
+	Quantity    float64              `json:"quantity"`
+	Unit        string               `json:"unit"`
+	Grams       float64              `json:"grams"`
+	Nutrients   []SimplifiedNutrient `json:"nutrients"`
+}
+
+// NetCarbsResponse represents a food's net carbohydrates for a serving
+type NetCarbsResponse struct {
+	FdcId            int      `json:"fdcId"`
+	Description      string   `json:"description"`
+	Measure          string   `json:"measure,omitempty"`
+	Grams            float64  `json:"grams"`
+	TotalCarbs       float64  `json:"totalCarbs"`
+	Fiber            float64  `json:"fiber"`
+	SugarAlcohols    *float64 `json:"sugarAlcohols,omitempty"`
+	NetCarbs         float64  `json:"netCarbs"`
+	FiberAssumedZero bool     `json:"fiberAssumedZero"`
+}
+
+// NutrientWithUpperLimit is a nutrient amount for a serving with its Tolerable Upper Intake Level, when one is
+// known, and the serving's share of it
+type NutrientWithUpperLimit struct {
+	Name                string      `json:"name"`
+	Amount              float64     `json:"amount"`
+	Unit                string      `json:"unit"`
+	UpperLimit          *UpperLimit `json:"upperLimit,omitempty"`
+	PercentOfUpperLimit *float64    `json:"percentOfUpperLimit,omitempty"`
+	OverUpperLimit      bool        `json:"overUpperLimit"`
+}
+
+// UpperLimitsResponse represents a food's nutrients for a serving against daily upper intake limits.
+// OverLimit names the nutrients whose serving amount alone exceeds the limit.
+type UpperLimitsResponse struct {
+	FdcId       int                      `json:"fdcId"`
+	Description string                   `json:"description"`
+	Measure     string                   `json:"measure,omitempty"`
+	Grams       float64                  `json:"grams"`
+	Nutrients   []NutrientWithUpperLimit `json:"nutrients"`
+	OverLimit   []string                 `json:"overLimit"`
+}
+
+// IntakeEntry is a food eaten during the day and how many grams of it
+type IntakeEntry struct {
+	Food  string  `json:"food"`
+	Grams float64 `json:"grams"`
+}
+
+// IntakeFood is a consumed food with its best match, with null fields when nothing matched
+type IntakeFood struct {
+	Food        string  `json:"food"`
+	Grams       float64 `json:"grams"`
+	Found       bool    `json:"found"`
+	FdcId       *int    `json:"fdcId"`
+	Description *string `json:"description"`
+}
+
+// DailyNutrientTotal is a nutrient's total across a day's foods with its daily target and upper limit, when
+// known. Remaining is how much more, in the target's unit, reaches the target; it is negative by the excess
+// once the target is passed.
+type DailyNutrientTotal struct {
+	Name           string        `json:"name"`
+	Amount         float64       `json:"amount"`
+	Unit           string        `json:"unit"`
+	Target         *IntakeTarget `json:"target,omitempty"`
+	Remaining      *float64      `json:"remaining,omitempty"`
+	UpperLimit     *UpperLimit   `json:"upperLimit,omitempty"`
+	OverUpperLimit bool          `json:"overUpperLimit"`
+}
+
+// DailyIntakeSummaryResponse represents a day's nutrient totals against a demographic's daily targets.
+// Unresolved lists the foods that matched nothing and OverLimit the nutrients whose total exceeds the upper limit.
+type DailyIntakeSummaryResponse struct {
+	Demographic string               `json:"demographic"`
+	Foods       []IntakeFood         `json:"foods"`
+	Unresolved  []string             `json:"unresolved"`
+	Nutrients   []DailyNutrientTotal `json:"nutrients"`
+	OverLimit   []string             `json:"overLimit"`
+}
+
+// NutrientHistoryPoint is a nutrient amount in one dataset release, null when the food or nutrient is absent
+type NutrientHistoryPoint struct {
+	Dataset string   `json:"dataset"`
+	Amount  *float64 `json:"amount"`
+	Unit    string   `json:"unit,omitempty"`
+}
+
+// NutrientHistoryResponse represents a food's nutrient amount across dataset releases, oldest first
+type NutrientHistoryResponse struct {
+	FdcId       int                    `json:"fdcId"`
+	Description string                 `json:"description"`
+	Nutrient    string                 `json:"nutrient"`
+	Points      []NutrientHistoryPoint `json:"points"`
+}
+
+// ProteinDensityFood represents a food's protein relative to its energy
+type ProteinDensityFood struct {
+	FdcId             int     `json:"fdcId"`
+	Description       string  `json:"description"`
+	Category          string  `json:"category"`
+	ProteinGrams      float64 `json:"proteinGrams"`
+	EnergyKcal        float64 `json:"energyKcal"`
+	ProteinPer100Kcal float64 `json:"proteinPer100Kcal"`
+}
+
+// ProteinDensityResponse represents foods ranked by grams of protein per 100 kcal
+type ProteinDensityResponse struct {
+	Count int                  `json:"count"`
+	Foods []ProteinDensityFood `json:"foods"`
+	Page  *PageInfo            `json:"page,omitempty"`
+}
+
+// NutrientRankedFood represents a food's amount of the ranked nutrient per 100 g, in the dataset's unit
+type NutrientRankedFood struct {
+	FdcId       int     `json:"fdcId"`
+	Description string  `json:"description"`
+	Category    string  `json:"category"`
+	Amount      float64 `json:"amount"`
+	Unit        string  `json:"unit"`
+}
+
+// NutrientRankingResponse represents foods ranked by their amount of one nutrient, highest first
+type NutrientRankingResponse struct {
+	Nutrient string               `json:"nutrient"`
+	Count    int                  `json:"count"`
+	Foods    []NutrientRankedFood `json:"foods"`
+	Page     *PageInfo            `json:"page,omitempty"`
+}
+
+// NutrientRangeResponse represents foods whose amount of one nutrient is within [Min, Max], highest first.
+// A nil bound was left open.
+type NutrientRangeResponse struct {
+	Nutrient string               `json:"nutrient"`
+	Min      *float64             `json:"min,omitempty"`
+	Max      *float64             `json:"max,omitempty"`
+	Count    int                  `json:"count"`
+	Foods    []NutrientRankedFood `json:"foods"`
+	Page     *PageInfo            `json:"page,omitempty"`
+}
+
+// NutrientPercentile is a food's amount of a nutrient and its percentile rank across the dataset
+type NutrientPercentile struct {
+	Name       string  `json:"name"`
+	Amount     float64 `json:"amount"`
+	Unit       string  `json:"unit"`
+	Percentile float64 `json:"percentile"`
+}
+
+// MultiNutrientFood is a food that is a good source of every requested nutrient. Score is the mean of its
+// nutrient percentiles.
+type MultiNutrientFood struct {
+	FdcId       int                  `json:"fdcId"`
+	Description string               `json:"description"`
+	Category    string               `json:"category"`
+	Score       float64              `json:"score"`
+	Nutrients   []NutrientPercentile `json:"nutrients"`
+}
+
+// MultiNutrientSourcesResponse represents foods ranking at or above Percentile for all of Nutrients
+type MultiNutrientSourcesResponse struct {
+	Nutrients  []string            `json:"nutrients"`
+	Percentile float64             `json:"percentile"`
+	Count      int                 `json:"count"`
+	Foods      []MultiNutrientFood `json:"foods"`
+	Page       *PageInfo           `json:"page,omitempty"`
+}
+
+// NutrientComponent is one nutrient's share of a food's composite score: its amount divided by the dataset
+// max (Normalized), times Weight
+type NutrientComponent struct {
+	Name         string  `json:"name"`
+	Amount       float64 `json:"amount"`
+	Unit         string  `json:"unit"`
+	Normalized   float64 `json:"normalized"`
+	Weight       float64 `json:"weight"`
+	Contribution float64 `json:"contribution"`
+}
+
+// CompositeRankedFood is a food's composite score with the per-nutrient breakdown that produced it
+type CompositeRankedFood struct {
+	FdcId       int                 `json:"fdcId"`
+	Description string              `json:"description"`
+	Category    string              `json:"category"`
+	Score       float64             `json:"score"`
+	Components  []NutrientComponent `json:"components"`
+}
+
+// CompositeRankingResponse represents foods ranked by a weighted sum of several nutrients, highest first
+type CompositeRankingResponse struct {
+	Nutrients []string              `json:"nutrients"`
+	Weights   []float64             `json:"weights"`
+	Count     int                   `json:"count"`
+	Foods     []CompositeRankedFood `json:"foods"`
+	Page      *PageInfo             `json:"page,omitempty"`
+}
+
+// ExpandedInputFood is an input food of ParentFdcId, Level steps below the requested food. Found is false for
+// stubs whose FDC ID isn't in the dataset; otherwise Food holds the input's full record.
+type ExpandedInputFood struct {
+	FdcId       int             `json:"fdcId"`
+	Description string          `json:"description"`
+	ParentFdcId int             `json:"parentFdcId"`
+	Level       int             `json:"level"`
+	Found       bool            `json:"found"`
+	Food        *FoundationFood `json:"food,omitempty"`
+}
+
+// FoodWithInputsResponse represents a food with its input foods expanded, listed depth-first so each
+// input is followed by its own inputs
+type FoodWithInputsResponse struct {
+	Food   FoundationFood      `json:"food"`
+	Depth  int                 `json:"depth"`
+	Inputs []ExpandedInputFood `json:"inputs"`
+}
+
+// InputFoodRef identifies an input food by FDC ID and description
+type InputFoodRef struct {
+	FdcId       int    `json:"fdcId"`
+	Description string `json:"description"`
+}
+
+// FoodInputFoodsResponse lists a food's direct input foods; InputFoods is empty for foods without any
+type FoodInputFoodsResponse struct {
+	FdcId       int            `json:"fdcId"`
+	Description string         `json:"description"`
+	Count       int            `json:"count"`
+	InputFoods  []InputFoodRef `json:"inputFoods"`
+}
+
+// EnergyBasisResponse represents a food's nutrients scaled to a fixed amount of energy instead of 100 g.
+// Grams is the weight of the food providing BasisKcal; ExcludedFoods are better matches skipped for lacking kcal energy.
+type EnergyBasisResponse struct {
+	FdcId         int                  `json:"fdcId"`
+	Description   string               `json:"description"`
+	EnergyKcal    float64              `json:"energyKcal"`
+	BasisKcal     float64              `json:"basisKcal"`
+	Grams         float64              `json:"grams"`
+	Nutrients     []SimplifiedNutrient `json:"nutrients"`
+	ExcludedFoods []FoodSummary        `json:"excludedFoods"`
+}
+
+// MacroPercentagesResponse represents the share of a food's macronutrient calories from protein, fat and
+// carbohydrate. MissingMacros lists the macros the food doesn't report, which were counted as zero.
+type MacroPercentagesResponse struct {
+	FdcId          int      `json:"fdcId"`
+	Description    string   `json:"description"`
+	ProteinGrams   float64  `json:"proteinGrams"`
+	FatGrams       float64  `json:"fatGrams"`
+	CarbGrams      float64  `json:"carbGrams"`
+	MacroKcal      float64  `json:"macroKcal"`
+	ProteinPercent float64  `json:"proteinPercent"`
+	FatPercent     float64  `json:"fatPercent"`
+	CarbPercent    float64  `json:"carbPercent"`
+	MissingMacros  []string `json:"missingMacros"`
+}
+
+// NutrientVector is one food's nutrient amounts in NutrientVectorsResponse.NutrientOrder
+type NutrientVector struct {
+	FdcId       int       `json:"fdcId"`
+	Description string    `json:"description"`
+	Values      []float64 `json:"values"`
+}
+
+// NutrientVectorsResponse represents matching foods as feature vectors. NutrientOrder names each column and
+// NutrientUnits gives its unit, empty when no food reports the nutrient.
+type NutrientVectorsResponse struct {
+	Query         string           `json:"query"`
+	NutrientOrder []string         `json:"nutrientOrder"`
+	NutrientUnits []string         `json:"nutrientUnits"`
+	Foods         []NutrientVector `json:"foods"`
+}
+
+// UnitCount is a unit name and how many nutrient entries or portions use it
+type UnitCount struct {
+	Unit  string `json:"unit"`
+	Count int    `json:"count"`
+}
+
+// UnitListResponse represents the distinct units used in the dataset, most used first
+type UnitListResponse struct {
+	NutrientUnits []UnitCount `json:"nutrientUnits"`
+	PortionUnits  []UnitCount `json:"portionUnits"`
+}
+
+// UnitConversionResponse is an amount converted between nutrient units. NutrientSpecific is set when an
+// International Unit conversion used the IU definition of Nutrient, described in Note.
+type UnitConversionResponse struct {
+	Amount           float64 `json:"amount"`
+	FromUnit         string  `json:"fromUnit"`
+	ConvertedAmount  float64 `json:"convertedAmount"`
+	ToUnit           string  `json:"toUnit"`
+	Nutrient         string  `json:"nutrient,omitempty"`
+	NutrientSpecific bool    `json:"nutrientSpecific,omitempty"`
+	Note             string  `json:"note,omitempty"`
+}
+
+// DatasetInfoResponse describes the loaded dataset
+type DatasetInfoResponse struct {
+	// SourceFile is the file name the dataset was loaded from
+	SourceFile string `json:"sourceFile"`
+
+	// Release is the release date in the file name, or the file name without its extension
+	Release string `json:"release"`
+
+	FoodCount     int `json:"foodCount"`
+	CategoryCount int `json:"categoryCount"`
+
+	// LatestPublicationDate is the most recent food publication date as YYYY-MM-DD, empty when no food has one
+	LatestPublicationDate string `json:"latestPublicationDate,omitempty"`
+}
+
+// FoodsByFdcIdsResponse lists the foods found for a batch of FDC IDs, in request order, and the IDs that
+// matched no food
+type FoodsByFdcIdsResponse struct {
+	Count    int              `json:"count"`
+	Foods    []FoundationFood `json:"foods"`
+	NotFound []int            `json:"notFound"`
+}
+
+// NdbNumberLookupResponse lists the foods that carry a legacy NDB number. NDB numbers are less unique than
+// FDC IDs, so more than one food can match.
+type NdbNumberLookupResponse struct {
+	NdbNumber int              `json:"ndbNumber"`
+	Count     int              `json:"count"`
+	Foods     []FoundationFood `json:"foods"`
+}
+
+// HistogramBucket is the number of foods whose nutrient amount falls in [Min, Max). The last bucket includes its Max.
+type HistogramBucket struct {
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Count int     `json:"count"`
+}
+
+// NutrientHistogramResponse represents the distribution of a nutrient's amount across foods
+type NutrientHistogramResponse struct {
+	Nutrient  string            `json:"nutrient"`
+	Unit      string            `json:"unit"`
+	Category  string            `json:"category,omitempty"`
+	FoodCount int               `json:"foodCount"`
+	Skipped   int               `json:"skipped"`
+	Min       float64           `json:"min"`
+	Max       float64           `json:"max"`
+	Buckets   []HistogramBucket `json:"buckets"`
 }
 
 // DefaultNutrients contains the standard set of nutrients to return by default
@@ -183,8 +924,7 @@ var DefaultNutrients = []string{
 	"Sugars, Total",               // total_sugars_g (126 foods)
 	"Total Sugars",                // total_sugars_g (alternative naming - 5 foods)
 
-	// Fats and fatty acids
-	"Total lipid (fat)",                  // total_fat_g
+	// Fats and fatty acids ("Total lipid (fat)" is listed under basic composition)
 	"Total fat (NLEA)",                   // NLEA compliant total fat
 	"Fatty acids, total saturated",       // saturated_fat_g
 	"Fatty acids, total trans",           // trans_fat_g
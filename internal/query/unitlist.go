@@ -0,0 +1,79 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ListUnits returns every distinct nutrient unit and portion measure unit in the dataset with how many
+// nutrient entries or portions use it, most used first. The result is computed once per dataset.
+func (e *Engine) ListUnits(ctx context.Context) (*UnitListResponse, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.data == nil {
+		return nil, fmt.Errorf("foundation Foods data not loaded")
+	}
+
+	units := e.unitList()
+	return &UnitListResponse{
+		NutrientUnits: slices.Clone(units.NutrientUnits),
+		PortionUnits:  slices.Clone(units.PortionUnits),
+	}, nil
+}
+
+// unitList computes and caches the dataset's unit usage on first use. The caller must hold the read lock.
+func (e *Engine) unitList() *UnitListResponse {
+	e.unitsMu.Lock()
+	defer e.unitsMu.Unlock()
+
+	if e.units != nil {
+		return e.units
+	}
+
+	nutrientCounts := make(map[string]int)
+	portionCounts := make(map[string]int)
+	for _, food := range e.data.FoundationFoods {
+		for _, nutrient := range food.FoodNutrients {
+			if unit := strings.TrimSpace(nutrient.Nutrient.UnitName); unit != "" {
+				nutrientCounts[unit]++
+			}
+		}
+		for _, portion := range food.FoodPortions {
+			if unit := strings.TrimSpace(portion.MeasureUnit.Name); unit != "" {
+				portionCounts[unit]++
+			}
+		}
+	}
+
+	e.units = &UnitListResponse{
+		NutrientUnits: sortedUnitCounts(nutrientCounts),
+		PortionUnits:  sortedUnitCounts(portionCounts),
+	}
+
+	e.logger.Debug("Computed unit list",
+		"nutrient_unit_count", len(e.units.NutrientUnits),
+		"portion_unit_count", len(e.units.PortionUnits))
+
+	return e.units
+}
+
+// sortedUnitCounts orders unit counts by descending count, then by name
+func sortedUnitCounts(counts map[string]int) []UnitCount {
+	result := make([]UnitCount, 0, len(counts))
+	for unit, count := range counts {
+		result = append(result, UnitCount{Unit: unit, Count: count})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Unit < result[j].Unit
+	})
+
+	return result
+}
@@ -0,0 +1,76 @@
+package query
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_ListUnits(t *testing.T) {
+	testData := &FoundationFoodsData{
+		FoundationFoods: []FoundationFood{
+			{
+				Description: "Milk, whole",
+				FdcId:       1,
+				FoodNutrients: []FoodNutrient{
+					{Nutrient: Nutrient{Name: "Energy", UnitName: "kcal"}, Amount: 61},
+					{Nutrient: Nutrient{Name: "Protein", UnitName: "g"}, Amount: 3.3},
+					{Nutrient: Nutrient{Name: "Calcium, Ca", UnitName: "mg"}, Amount: 113},
+				},
+				FoodPortions: []FoodPortion{
+					{MeasureUnit: MeasureUnit{Name: "cup"}, GramWeight: 244},
+				},
+			},
+			{
+				Description: "Cheese, cheddar",
+				FdcId:       2,
+				FoodNutrients: []FoodNutrient{
+					{Nutrient: Nutrient{Name: "Protein", UnitName: "g"}, Amount: 23.3},
+					{Nutrient: Nutrient{Name: "Total lipid (fat)", UnitName: "g"}, Amount: 34},
+					{Nutrient: Nutrient{Name: "Sodium, Na", UnitName: "mg"}, Amount: 653},
+				},
+				FoodPortions: []FoodPortion{
+					{MeasureUnit: MeasureUnit{Name: "cup"}, GramWeight: 113},
+					{MeasureUnit: MeasureUnit{Name: "slice"}, GramWeight: 28},
+				},
+			},
+		},
+	}
+
+	engine := &Engine{
+		data:   testData,
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+
+	result, err := engine.ListUnits(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []UnitCount{
+		{Unit: "g", Count: 3},
+		{Unit: "mg", Count: 2},
+		{Unit: "kcal", Count: 1},
+	}, result.NutrientUnits)
+	assert.Equal(t, []UnitCount{
+		{Unit: "cup", Count: 2},
+		{Unit: "slice", Count: 1},
+	}, result.PortionUnits)
+
+	t.Run("recomputes after the data is swapped", func(t *testing.T) {
+		engine.swapData(&FoundationFoodsData{
+			FoundationFoods: []FoundationFood{
+				{Description: "Salt", FdcId: 3, FoodNutrients: []FoodNutrient{
+					{Nutrient: Nutrient{Name: "Sodium, Na", UnitName: "mg"}, Amount: 38758},
+				}},
+			},
+		})
+
+		result, err := engine.ListUnits(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []UnitCount{{Unit: "mg", Count: 1}}, result.NutrientUnits)
+		assert.Empty(t, result.PortionUnits)
+	})
+}
@@ -0,0 +1,138 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// normalizeNutrientUnit converts mass amounts to grams and energy amounts to kcal so
+// nutrients reported in different units can be compared directly. Other units are returned unchanged.
+func normalizeNutrientUnit(amount float64, unit string) (float64, string) {
+	switch strings.ToLower(strings.TrimSpace(unit)) {
+	case "g":
+		return amount, "g"
+	case "mg":
+		return amount / 1000, "g"
+	case "µg", "μg", "ug", "mcg":
+		return amount / 1_000_000, "g"
+	case "kcal":
+		return amount, "kcal"
+	case "kj":
+		return amount / 4.184, "kcal"
+	default:
+		return amount, unit
+	}
+}
+
+// convertNutrientAmount expresses amount in toUnit, converting between mass units and between energy units.
+// It reports false when the two units measure different things.
+func convertNutrientAmount(amount float64, unit, toUnit string) (float64, bool) {
+	normalized, normalizedUnit := normalizeNutrientUnit(amount, unit)
+	perTargetUnit, targetUnit := normalizeNutrientUnit(1, toUnit)
+	if !strings.EqualFold(normalizedUnit, targetUnit) || perTargetUnit <= 0 {
+		return 0, false
+	}
+	return normalized / perTargetUnit, true
+}
+
+// internationalUnit is the USDA unit name for International Units, whose mass depends on the nutrient
+const internationalUnit = "IU"
+
+// iuMicrograms is the mass in µg of one International Unit of the nutrients with a standard IU definition
+var iuMicrograms = []struct {
+	nutrient   string
+	micrograms float64
+	note       string
+}{
+	{"vitamin d", 0.025, "1 IU of vitamin D is 0.025 µg of cholecalciferol or ergocalciferol"},
+	{"vitamin a", 0.3, "1 IU of vitamin A is 0.3 µg of retinol; carotenoid IUs convert differently"},
+	{"vitamin e", 670, "1 IU of vitamin E is 0.67 mg of natural alpha-tocopherol; synthetic forms convert differently"},
+}
+
+// isInternationalUnit reports whether unit is the IU unit
+func isInternationalUnit(unit string) bool {
+	return strings.EqualFold(strings.TrimSpace(unit), internationalUnit)
+}
+
+// knownNutrientUnit reports whether unit is a mass, energy or International Unit that conversions understand
+func knownNutrientUnit(unit string) bool {
+	if isInternationalUnit(unit) {
+		return true
+	}
+	_, normalized := normalizeNutrientUnit(1, unit)
+	return normalized == "g" || normalized == "kcal"
+}
+
+// ConvertNutrientUnit converts an amount between compatible nutrient units: mass units (g, mg, µg) with each
+// other and energy units (kcal, kJ) with each other. International Units only convert to or from mass for a
+// nutrient with a standard IU definition (vitamins A, D and E), so they need the nutrient name.
+func ConvertNutrientUnit(amount float64, fromUnit, toUnit, nutrient string) (*UnitConversionResponse, error) {
+	for _, unit := range []string{fromUnit, toUnit} {
+		if !knownNutrientUnit(unit) {
+			return nil, fmt.Errorf("unknown unit %q: supported units are g, mg, µg, kcal, kJ and IU", unit)
+		}
+	}
+
+	response := &UnitConversionResponse{
+		Amount:   amount,
+		FromUnit: fromUnit,
+		ToUnit:   toUnit,
+	}
+
+	fromIU, toIU := isInternationalUnit(fromUnit), isInternationalUnit(toUnit)
+	if fromIU && toIU {
+		response.ConvertedAmount = amount
+		return response, nil
+	}
+
+	if fromIU || toIU {
+		massUnit := toUnit
+		if toIU {
+			massUnit = fromUnit
+		}
+		if _, normalized := normalizeNutrientUnit(1, massUnit); normalized != "g" {
+			return nil, fmt.Errorf("cannot convert %s to %s: IU only converts to and from mass units", fromUnit, toUnit)
+		}
+
+		micrograms, note, err := iuMass(nutrient)
+		if err != nil {
+			return nil, err
+		}
+		response.Nutrient = nutrient
+		response.NutrientSpecific = true
+		response.Note = note
+
+		// Go through µg, the unit the IU definitions are given in
+		if fromIU {
+			response.ConvertedAmount, _ = convertNutrientAmount(amount*micrograms, "µg", toUnit)
+		} else {
+			inMicrograms, _ := convertNutrientAmount(amount, fromUnit, "µg")
+			response.ConvertedAmount = inMicrograms / micrograms
+		}
+		return response, nil
+	}
+
+	converted, ok := convertNutrientAmount(amount, fromUnit, toUnit)
+	if !ok {
+		return nil, fmt.Errorf("cannot convert %s to %s: the units measure different quantities", fromUnit, toUnit)
+	}
+	response.ConvertedAmount = converted
+
+	return response, nil
+}
+
+// iuMass returns the µg in one International Unit of a nutrient, matched by name
+func iuMass(nutrient string) (float64, string, error) {
+	if strings.TrimSpace(nutrient) == "" {
+		return 0, "", fmt.Errorf("IU conversions are nutrient-specific: pass the nutrient (vitamin A, D or E)")
+	}
+
+	name := strings.ToLower(nutrient)
+	for _, iu := range iuMicrograms {
+		if strings.Contains(name, iu.nutrient) {
+			return iu.micrograms, iu.note, nil
+		}
+	}
+
+	return 0, "", fmt.Errorf("no standard IU definition for %q: IU conversions are only defined for vitamins A, D and E", nutrient)
+}
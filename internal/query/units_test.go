@@ -0,0 +1,81 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertNutrientUnit(t *testing.T) {
+	t.Run("converts between the dataset's mass units", func(t *testing.T) {
+		cases := []struct {
+			amount   float64
+			from, to string
+			want     float64
+		}{
+			{1.5, "g", "mg", 1500},
+			{250, "mg", "g", 0.25},
+			{2, "mg", "µg", 2000},
+			{500, "µg", "mg", 0.5},
+			{3, "g", "µg", 3_000_000},
+			{40, "mcg", "mg", 0.04},
+			{7, "MG", "mg", 7},
+		}
+		for _, tc := range cases {
+			response, err := ConvertNutrientUnit(tc.amount, tc.from, tc.to, "")
+			require.NoError(t, err, "%s to %s", tc.from, tc.to)
+			assert.InDelta(t, tc.want, response.ConvertedAmount, 1e-9, "%s to %s", tc.from, tc.to)
+			assert.False(t, response.NutrientSpecific)
+		}
+	})
+
+	t.Run("converts between energy units", func(t *testing.T) {
+		response, err := ConvertNutrientUnit(418.4, "kJ", "kcal", "")
+
+		require.NoError(t, err)
+		assert.InDelta(t, 100, response.ConvertedAmount, 1e-9)
+	})
+
+	t.Run("converts IU with the nutrient's definition", func(t *testing.T) {
+		response, err := ConvertNutrientUnit(400, "IU", "µg", "Vitamin D (D2 + D3), International Units")
+
+		require.NoError(t, err)
+		assert.InDelta(t, 10, response.ConvertedAmount, 1e-9)
+		assert.True(t, response.NutrientSpecific)
+		assert.Equal(t, "Vitamin D (D2 + D3), International Units", response.Nutrient)
+		assert.Contains(t, response.Note, "0.025 µg")
+
+		response, err = ConvertNutrientUnit(0.6, "mg", "IU", "vitamin A")
+		require.NoError(t, err)
+		assert.InDelta(t, 2000, response.ConvertedAmount, 1e-9)
+
+		response, err = ConvertNutrientUnit(10, "IU", "mg", "Vitamin E")
+		require.NoError(t, err)
+		assert.InDelta(t, 6.7, response.ConvertedAmount, 1e-9)
+	})
+
+	t.Run("requires a nutrient with an IU definition", func(t *testing.T) {
+		_, err := ConvertNutrientUnit(400, "IU", "µg", "")
+		assert.ErrorContains(t, err, "nutrient-specific")
+
+		_, err = ConvertNutrientUnit(400, "IU", "mg", "Calcium, Ca")
+		assert.ErrorContains(t, err, "no standard IU definition")
+	})
+
+	t.Run("rejects incompatible units", func(t *testing.T) {
+		_, err := ConvertNutrientUnit(100, "kcal", "mg", "")
+		assert.ErrorContains(t, err, "different quantities")
+
+		_, err = ConvertNutrientUnit(100, "IU", "kcal", "Vitamin D")
+		assert.ErrorContains(t, err, "IU only converts to and from mass units")
+	})
+
+	t.Run("rejects unknown units", func(t *testing.T) {
+		_, err := ConvertNutrientUnit(1, "sp gr", "g", "")
+		assert.ErrorContains(t, err, "unknown unit")
+
+		_, err = ConvertNutrientUnit(1, "g", "cup", "")
+		assert.ErrorContains(t, err, "unknown unit")
+	})
+}
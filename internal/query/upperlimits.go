@@ -0,0 +1,166 @@
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// UpperLimit is a Tolerable Upper Intake Level: the most of a nutrient per day that is unlikely to cause
+// adverse effects
+type UpperLimit struct {
+	Nutrient string  `json:"nutrient"`
+	Amount   float64 `json:"amount"`
+	Unit     string  `json:"unit"`
+}
+
+// DefaultUpperLimits are the adult (19-50 years) Tolerable Upper Intake Levels from the National Academies'
+// Dietary Reference Intakes, keyed to the nutrient names used in the dataset. Limits that apply only to
+// supplemental or synthetic forms are listed under those forms (such as "Folic acid").
+var DefaultUpperLimits = []UpperLimit{
+	{Nutrient: "Sodium, Na", Amount: 2300, Unit: "mg"},
+	{Nutrient: "Calcium, Ca", Amount: 2500, Unit: "mg"},
+	{Nutrient: "Iron, Fe", Amount: 45, Unit: "mg"},
+	{Nutrient: "Zinc, Zn", Amount: 40, Unit: "mg"},
+	{Nutrient: "Phosphorus, P", Amount: 4000, Unit: "mg"},
+	{Nutrient: "Copper, Cu", Amount: 10, Unit: "mg"},
+	{Nutrient: "Manganese, Mn", Amount: 11, Unit: "mg"},
+	{Nutrient: "Selenium, Se", Amount: 400, Unit: "µg"},
+	{Nutrient: "Iodine, I", Amount: 1100, Unit: "µg"},
+	{Nutrient: "Molybdenum, Mo", Amount: 2000, Unit: "µg"},
+	{Nutrient: "Fluoride, F", Amount: 10, Unit: "mg"},
+	{Nutrient: "Retinol", Amount: 3000, Unit: "µg"},
+	{Nutrient: "Vitamin D (D2 + D3)", Amount: 100, Unit: "µg"},
+	{Nutrient: "Vitamin D (D2 + D3), International Units", Amount: 4000, Unit: "IU"},
+	{Nutrient: "Vitamin C, total ascorbic acid", Amount: 2000, Unit: "mg"},
+	{Nutrient: "Vitamin E (alpha-tocopherol)", Amount: 1000, Unit: "mg"},
+	{Nutrient: "Vitamin B-6", Amount: 100, Unit: "mg"},
+	{Nutrient: "Niacin", Amount: 35, Unit: "mg"},
+	{Nutrient: "Folic acid", Amount: 1000, Unit: "µg"},
+	{Nutrient: "Choline, total", Amount: 3500, Unit: "mg"},
+}
+
+// LoadUpperLimits reads a JSON array of upper limits, each with a nutrient name, amount and unit
+func LoadUpperLimits(path string) ([]UpperLimit, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read upper limits file: %w", err)
+	}
+
+	var limits []UpperLimit
+	if err := json.Unmarshal(content, &limits); err != nil {
+		return nil, fmt.Errorf("failed to parse upper limits file: %w", err)
+	}
+
+	for _, limit := range limits {
+		if strings.TrimSpace(limit.Nutrient) == "" || limit.Amount <= 0 || strings.TrimSpace(limit.Unit) == "" {
+			return nil, fmt.Errorf("invalid upper limit %+v: nutrient, a positive amount and unit are required", limit)
+		}
+	}
+
+	return limits, nil
+}
+
+// WithUpperLimits adds to or replaces, by nutrient name, the built-in upper limits
+func WithUpperLimits(limits []UpperLimit) EngineOption {
+	return func(e *Engine) {
+		e.upperLimits = upperLimitTable(append(append([]UpperLimit(nil), DefaultUpperLimits...), limits...))
+	}
+}
+
+// upperLimitTable indexes limits by lowercased nutrient name, later entries replacing earlier ones
+func upperLimitTable(limits []UpperLimit) map[string]UpperLimit {
+	table := make(map[string]UpperLimit, len(limits))
+	for _, limit := range limits {
+		table[strings.ToLower(strings.TrimSpace(limit.Nutrient))] = limit
+	}
+	return table
+}
+
+// upperLimitFor returns the upper limit configured for a dataset nutrient name, accepting the alternative
+// names shouldIncludeNutrient accepts
+func (e *Engine) upperLimitFor(nutrientName string) (UpperLimit, bool) {
+	table := e.upperLimits
+	if table == nil {
+		table = upperLimitTable(DefaultUpperLimits)
+	}
+
+	dataName := strings.ToLower(strings.TrimSpace(nutrientName))
+	if limit, ok := table[dataName]; ok {
+		return limit, true
+	}
+	for filterName, limit := range table {
+		if e.isAlternativeNutrientName(dataName, filterName) {
+			return limit, true
+		}
+	}
+	return UpperLimit{}, false
+}
+
+// limitFraction returns amount as a fraction of limit, converting mass units to a common scale. It reports
+// false when the units can't be compared.
+func limitFraction(amount float64, unit string, limit UpperLimit) (float64, bool) {
+	normalizedAmount, amountUnit := normalizeNutrientUnit(amount, unit)
+	normalizedLimit, limitUnit := normalizeNutrientUnit(limit.Amount, limit.Unit)
+	if !strings.EqualFold(amountUnit, limitUnit) || normalizedLimit <= 0 {
+		return 0, false
+	}
+	return normalizedAmount / normalizedLimit, true
+}
+
+// NutrientsWithUpperLimits returns a food's nutrients for a serving, scaled to a household measure or
+// 100 g when the measure is empty, each annotated with its Tolerable Upper Intake Level and flagged when
+// the serving alone exceeds it. Nutrients without a limit are returned without one.
+func (e *Engine) NutrientsWithUpperLimits(ctx context.Context, fdcId int, measure string) (*UpperLimitsResponse, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	food, err := e.getFoodByFdcId(fdcId)
+	if err != nil {
+		return nil, err
+	}
+
+	grams := 100.0
+	if strings.TrimSpace(measure) != "" {
+		grams, _, _, err = resolveHouseholdGrams(food, measure)
+		if err != nil {
+			return nil, err
+		}
+	}
+	factor := grams / 100
+
+	response := &UpperLimitsResponse{
+		FdcId:       food.FdcId,
+		Description: food.Description,
+		Measure:     measure,
+		Grams:       grams,
+		Nutrients:   make([]NutrientWithUpperLimit, 0, len(food.FoodNutrients)),
+		OverLimit:   []string{},
+	}
+
+	for _, nutrient := range food.FoodNutrients {
+		annotated := NutrientWithUpperLimit{
+			Name:   nutrient.Nutrient.Name,
+			Amount: nutrient.Amount * factor,
+			Unit:   nutrient.Nutrient.UnitName,
+		}
+
+		if limit, ok := e.upperLimitFor(nutrient.Nutrient.Name); ok {
+			if fraction, ok := limitFraction(annotated.Amount, annotated.Unit, limit); ok {
+				percent := fraction * 100
+				annotated.UpperLimit = &limit
+				annotated.PercentOfUpperLimit = &percent
+				annotated.OverUpperLimit = fraction > 1
+				if annotated.OverUpperLimit {
+					response.OverLimit = append(response.OverLimit, annotated.Name)
+				}
+			}
+		}
+
+		response.Nutrients = append(response.Nutrients, annotated)
+	}
+
+	return response, nil
+}
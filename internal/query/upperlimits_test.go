@@ -0,0 +1,112 @@
+package query
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_NutrientsWithUpperLimits(t *testing.T) {
+	testData := &FoundationFoodsData{
+		FoundationFoods: []FoundationFood{
+			{
+				Description: "Soy sauce",
+				FdcId:       1,
+				FoodNutrients: []FoodNutrient{
+					{Nutrient: Nutrient{Name: "Sodium, Na", UnitName: "mg"}, Amount: 5500},
+					{Nutrient: Nutrient{Name: "Protein", UnitName: "g"}, Amount: 8.1},
+					{Nutrient: Nutrient{Name: "Selenium, Se", UnitName: "µg"}, Amount: 0.5},
+				},
+				FoodPortions: []FoodPortion{
+					{Value: 1, MeasureUnit: MeasureUnit{Name: "tbsp"}, GramWeight: 16},
+				},
+			},
+		},
+	}
+
+	engine := &Engine{
+		data:   testData,
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+	ctx := context.Background()
+
+	byName := func(response *UpperLimitsResponse) map[string]NutrientWithUpperLimit {
+		nutrients := make(map[string]NutrientWithUpperLimit)
+		for _, nutrient := range response.Nutrients {
+			nutrients[nutrient.Name] = nutrient
+		}
+		return nutrients
+	}
+
+	t.Run("flags a high-sodium serving over the limit", func(t *testing.T) {
+		result, err := engine.NutrientsWithUpperLimits(ctx, 1, "")
+
+		require.NoError(t, err)
+		assert.Equal(t, 100.0, result.Grams)
+		nutrients := byName(result)
+
+		sodium := nutrients["Sodium, Na"]
+		require.NotNil(t, sodium.UpperLimit)
+		assert.Equal(t, 2300.0, sodium.UpperLimit.Amount)
+		assert.InDelta(t, 239.13, *sodium.PercentOfUpperLimit, 0.01)
+		assert.True(t, sodium.OverUpperLimit)
+		assert.Equal(t, []string{"Sodium, Na"}, result.OverLimit)
+
+		protein := nutrients["Protein"]
+		assert.Nil(t, protein.UpperLimit)
+		assert.Nil(t, protein.PercentOfUpperLimit)
+		assert.False(t, protein.OverUpperLimit)
+
+		selenium := nutrients["Selenium, Se"]
+		require.NotNil(t, selenium.PercentOfUpperLimit)
+		assert.InDelta(t, 0.125, *selenium.PercentOfUpperLimit, 0.0001)
+	})
+
+	t.Run("scales to a household measure", func(t *testing.T) {
+		result, err := engine.NutrientsWithUpperLimits(ctx, 1, "1 tbsp")
+
+		require.NoError(t, err)
+		sodium := byName(result)["Sodium, Na"]
+		assert.Equal(t, 880.0, sodium.Amount)
+		assert.False(t, sodium.OverUpperLimit)
+		assert.Empty(t, result.OverLimit)
+	})
+
+	t.Run("uses configured limits", func(t *testing.T) {
+		custom := &Engine{data: testData, logger: engine.logger}
+		WithUpperLimits([]UpperLimit{{Nutrient: "Sodium, Na", Amount: 0.5, Unit: "g"}})(custom)
+
+		result, err := custom.NutrientsWithUpperLimits(ctx, 1, "1 tbsp")
+
+		require.NoError(t, err)
+		sodium := byName(result)["Sodium, Na"]
+		assert.InDelta(t, 176, *sodium.PercentOfUpperLimit, 0.0001)
+		assert.True(t, sodium.OverUpperLimit)
+		// Built-in limits not overridden remain
+		assert.NotNil(t, byName(result)["Selenium, Se"].UpperLimit)
+	})
+}
+
+func TestLoadUpperLimits(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "limits.json")
+	require.NoError(t, os.WriteFile(valid, []byte(`[{"nutrient": "Sodium, Na", "amount": 1500, "unit": "mg"}]`), 0o600))
+	limits, err := LoadUpperLimits(valid)
+	require.NoError(t, err)
+	assert.Equal(t, []UpperLimit{{Nutrient: "Sodium, Na", Amount: 1500, Unit: "mg"}}, limits)
+
+	invalid := filepath.Join(dir, "invalid.json")
+	require.NoError(t, os.WriteFile(invalid, []byte(`[{"nutrient": "Sodium, Na", "amount": 0, "unit": "mg"}]`), 0o600))
+	_, err = LoadUpperLimits(invalid)
+	assert.Error(t, err)
+
+	_, err = LoadUpperLimits(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
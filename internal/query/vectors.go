@@ -0,0 +1,62 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// NutrientVectors returns, for up to limit foods matching a name, their amounts of each named nutrient as a
+// fixed-order vector (DefaultNutrients when nutrientNames is empty). Amounts are normalized to grams or kcal;
+// a nutrient the food doesn't report, or reports in a different unit than the column, is 0.
+func (e *Engine) NutrientVectors(ctx context.Context, name string, limit int, nutrientNames []string, opts SearchOptions) (*NutrientVectorsResponse, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("name must not be empty")
+	}
+	if len(nutrientNames) == 0 {
+		nutrientNames = DefaultNutrients
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	foods, _, err := e.searchFoodsByName(ctx, name, limit, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &NutrientVectorsResponse{
+		Query:         name,
+		NutrientOrder: append([]string(nil), nutrientNames...),
+		NutrientUnits: make([]string, len(nutrientNames)),
+		Foods:         make([]NutrientVector, 0, len(foods)),
+	}
+
+	for _, food := range foods {
+		vector := NutrientVector{
+			FdcId:       food.FdcId,
+			Description: food.Description,
+			Values:      make([]float64, len(nutrientNames)),
+		}
+
+		for i, nutrientName := range nutrientNames {
+			nutrient, ok := findNutrient(&food, nutrientName)
+			if !ok {
+				continue
+			}
+
+			// The first food reporting a nutrient fixes its column unit
+			amount, unit := normalizeNutrientUnit(nutrient.Amount, nutrient.Nutrient.UnitName)
+			if response.NutrientUnits[i] == "" {
+				response.NutrientUnits[i] = unit
+			}
+			if unit == response.NutrientUnits[i] {
+				vector.Values[i] = amount
+			}
+		}
+
+		response.Foods = append(response.Foods, vector)
+	}
+
+	return response, nil
+}
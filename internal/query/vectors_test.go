@@ -0,0 +1,76 @@
+package query
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/noot-app/foundation-foods-mcp-server/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_NutrientVectors(t *testing.T) {
+	testData := &FoundationFoodsData{
+		FoundationFoods: []FoundationFood{
+			{
+				Description: "Apples, fuji, raw",
+				FdcId:       1,
+				FoodNutrients: []FoodNutrient{
+					// Reported out of column order on purpose
+					{Nutrient: Nutrient{Name: "Sodium, Na", UnitName: "mg"}, Amount: 1},
+					{Nutrient: Nutrient{Name: "Energy", UnitName: "kJ"}, Amount: 263.592},
+					{Nutrient: Nutrient{Name: "Protein", UnitName: "g"}, Amount: 0.2},
+				},
+			},
+			{
+				Description: "Apples, gala, raw",
+				FdcId:       2,
+				FoodNutrients: []FoodNutrient{
+					{Nutrient: Nutrient{Name: "Protein", UnitName: "g"}, Amount: 0.3},
+				},
+			},
+		},
+	}
+
+	engine := &Engine{
+		data:   testData,
+		logger: config.NewTestLogger(io.Discard, "debug"),
+	}
+	ctx := context.Background()
+
+	t.Run("returns fixed-order vectors over the nutrient list", func(t *testing.T) {
+		nutrients := []string{"Protein", "Energy", "Sodium, Na", "Fiber, total dietary"}
+		result, err := engine.NutrientVectors(ctx, "apples", 10, nutrients, SearchOptions{})
+
+		require.NoError(t, err)
+		assert.Equal(t, nutrients, result.NutrientOrder)
+		assert.Equal(t, []string{"g", "kcal", "g", ""}, result.NutrientUnits)
+		require.Len(t, result.Foods, 2)
+
+		byId := map[int][]float64{}
+		for _, food := range result.Foods {
+			require.Len(t, food.Values, len(nutrients))
+			byId[food.FdcId] = food.Values
+		}
+		assert.InDelta(t, 0.2, byId[1][0], 0.0001)
+		assert.InDelta(t, 63.0, byId[1][1], 0.001)
+		assert.InDelta(t, 0.001, byId[1][2], 0.000001)
+		assert.Equal(t, 0.0, byId[1][3])
+		assert.Equal(t, []float64{0.3, 0, 0, 0}, byId[2])
+	})
+
+	t.Run("defaults to the standard nutrient list", func(t *testing.T) {
+		result, err := engine.NutrientVectors(ctx, "fuji", 1, nil, SearchOptions{})
+
+		require.NoError(t, err)
+		assert.Equal(t, DefaultNutrients, result.NutrientOrder)
+		require.Len(t, result.Foods, 1)
+		assert.Len(t, result.Foods[0].Values, len(DefaultNutrients))
+	})
+
+	t.Run("rejects an empty name", func(t *testing.T) {
+		_, err := engine.NutrientVectors(ctx, " ", 1, nil, SearchOptions{})
+		assert.Error(t, err)
+	})
+}
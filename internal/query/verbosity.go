@@ -0,0 +1,48 @@
+package query
+
+import "fmt"
+
+// Verbosity levels for full food responses
+const (
+	// VerbosityFull returns every field of the dataset
+	VerbosityFull = "full"
+
+	// VerbosityStandard keeps each nutrient's derivation but drops its source
+	VerbosityStandard = "standard"
+
+	// VerbosityLean drops each nutrient's derivation and source
+	VerbosityLean = "lean"
+)
+
+// ValidateVerbosity reports an error for unknown verbosity levels. An empty level means full.
+func ValidateVerbosity(verbosity string) error {
+	switch verbosity {
+	case "", VerbosityFull, VerbosityStandard, VerbosityLean:
+		return nil
+	default:
+		return fmt.Errorf("unknown verbosity %q, expected %q, %q or %q", verbosity, VerbosityFull, VerbosityStandard, VerbosityLean)
+	}
+}
+
+// ApplyVerbosity returns a copy of food trimmed to the given verbosity level without modifying the original
+func ApplyVerbosity(food FoundationFood, verbosity string) FoundationFood {
+	if verbosity == "" || verbosity == VerbosityFull {
+		return food
+	}
+
+	trimmed := food
+	trimmed.FoodNutrients = make([]FoodNutrient, len(food.FoodNutrients))
+	for i, nutrient := range food.FoodNutrients {
+		switch {
+		case verbosity == VerbosityLean:
+			nutrient.FoodNutrientDerivation = nil
+		case nutrient.FoodNutrientDerivation != nil:
+			derivation := *nutrient.FoodNutrientDerivation
+			derivation.FoodNutrientSource = nil
+			nutrient.FoodNutrientDerivation = &derivation
+		}
+		trimmed.FoodNutrients[i] = nutrient
+	}
+
+	return trimmed
+}
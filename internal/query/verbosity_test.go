@@ -0,0 +1,67 @@
+package query
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyVerbosity(t *testing.T) {
+	food := FoundationFood{
+		Description: "Milk, whole",
+		FdcId:       1,
+		FoodNutrients: []FoodNutrient{
+			{
+				Nutrient: Nutrient{Name: "Protein", UnitName: "g"},
+				Amount:   3.3,
+				FoodNutrientDerivation: &FoodNutrientDerivation{
+					Code:               "A",
+					Description:        "Analytical",
+					FoodNutrientSource: &FoodNutrientSource{Code: "1", Description: "Analytical or derived from analytical"},
+				},
+			},
+		},
+	}
+
+	nutrientJSON := func(t *testing.T, food FoundationFood) string {
+		encoded, err := json.Marshal(food.FoodNutrients[0])
+		require.NoError(t, err)
+		return string(encoded)
+	}
+
+	t.Run("full keeps derivation and source", func(t *testing.T) {
+		encoded := nutrientJSON(t, ApplyVerbosity(food, VerbosityFull))
+
+		assert.Contains(t, encoded, `"foodNutrientDerivation"`)
+		assert.Contains(t, encoded, `"foodNutrientSource"`)
+	})
+
+	t.Run("standard keeps derivation code but drops source", func(t *testing.T) {
+		encoded := nutrientJSON(t, ApplyVerbosity(food, VerbosityStandard))
+
+		assert.Contains(t, encoded, `"code":"A"`)
+		assert.NotContains(t, encoded, `"foodNutrientSource"`)
+	})
+
+	t.Run("lean drops derivation and source", func(t *testing.T) {
+		encoded := nutrientJSON(t, ApplyVerbosity(food, VerbosityLean))
+
+		assert.NotContains(t, encoded, `"foodNutrientDerivation"`)
+		assert.NotContains(t, encoded, `"foodNutrientSource"`)
+		assert.Contains(t, encoded, `"amount":3.3`)
+	})
+
+	t.Run("does not modify the original food", func(t *testing.T) {
+		ApplyVerbosity(food, VerbosityLean)
+
+		require.NotNil(t, food.FoodNutrients[0].FoodNutrientDerivation)
+		assert.NotNil(t, food.FoodNutrients[0].FoodNutrientDerivation.FoodNutrientSource)
+	})
+
+	t.Run("rejects unknown levels", func(t *testing.T) {
+		assert.NoError(t, ValidateVerbosity(""))
+		assert.Error(t, ValidateVerbosity("verbose"))
+	})
+}
@@ -21,6 +21,11 @@ func defaultBuildInfoReader() (*debug.BuildInfo, bool) {
 	return debug.ReadBuildInfo()
 }
 
+// Tag returns the release tag set via ldflags ("dev" for local builds)
+func Tag() string {
+	return tag
+}
+
 func String() string {
 	// Start with ldflags values
 	currentCommit := commit